@@ -0,0 +1,139 @@
+// Package api serves the gator data over a JSON REST API. Clients log in with
+// POST /api/v1/sessions and send the returned token as a bearer token; the
+// tokens are the same sessions the CLI uses. Feed readers, which rarely
+// support bearer tokens, may use HTTP basic auth with the password instead.
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/lmilojevicc/gator/internal/auth"
+	"github.com/lmilojevicc/gator/internal/database"
+	"github.com/lmilojevicc/gator/internal/store"
+)
+
+type Server struct {
+	store store.Store
+	// OpenRegistration lets anyone register. Otherwise registering needs
+	// an admin's session.
+	OpenRegistration bool
+}
+
+func New(st store.Store) *Server {
+	return &Server{store: st}
+}
+
+type authenticatedHandler = func(w http.ResponseWriter, r *http.Request, user database.User)
+
+// Handler returns the routes of the API.
+func (srv *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /api/v1/sessions", srv.handleLogin)
+	mux.HandleFunc("DELETE /api/v1/sessions", srv.authenticated(srv.handleLogout))
+	mux.HandleFunc("POST /api/v1/users", srv.handleRegister)
+	mux.HandleFunc("GET /api/v1/users", srv.authenticated(srv.handleUsers))
+	mux.HandleFunc("GET /api/v1/me", srv.authenticated(srv.handleMe))
+
+	mux.HandleFunc("GET /api/v1/feeds", srv.authenticated(srv.handleFeeds))
+	mux.HandleFunc("POST /api/v1/feeds", srv.authenticated(srv.handleAddFeed))
+	mux.HandleFunc("GET /api/v1/follows", srv.authenticated(srv.handleFollows))
+	mux.HandleFunc("POST /api/v1/follows", srv.authenticated(srv.handleFollow))
+	mux.HandleFunc("DELETE /api/v1/follows/{feedID}", srv.authenticated(srv.handleUnfollow))
+
+	mux.HandleFunc("GET /api/v1/posts", srv.authenticated(srv.handlePosts))
+	mux.HandleFunc("PUT /api/v1/posts/{postID}/read", srv.authenticated(srv.handleMarkRead))
+	mux.HandleFunc("DELETE /api/v1/posts/{postID}/read", srv.authenticated(srv.handleMarkUnread))
+
+	mux.HandleFunc("GET /api/v1/timeline.atom", srv.authenticated(srv.handleTimeline))
+	mux.HandleFunc("GET /api/v1/timeline.rss", srv.authenticated(srv.handleTimeline))
+
+	return mux
+}
+
+// authenticated resolves the bearer token or basic auth credentials to a user,
+// like middleware.LoggedIn does for the session token in the config file.
+func (srv *Server) authenticated(handler authenticatedHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if name, password, ok := r.BasicAuth(); ok {
+			user, err := auth.Authenticate(r.Context(), srv.store, name, password)
+			if errors.Is(err, auth.ErrWrongPassword) || errors.Is(err, auth.ErrNoPassword) {
+				w.Header().Set("WWW-Authenticate", `Basic realm="gator"`)
+				writeError(w, http.StatusUnauthorized, err.Error())
+				return
+			}
+			if err != nil {
+				serverError(w, "authenticating", err)
+				return
+			}
+
+			handler(w, r, user)
+			return
+		}
+
+		token := bearerToken(r)
+		if token == "" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="gator"`)
+			writeError(w, http.StatusUnauthorized, "missing bearer token or basic auth")
+			return
+		}
+
+		user, err := srv.store.GetUserBySession(r.Context(), auth.HashToken(token))
+		if err == sql.ErrNoRows {
+			writeError(w, http.StatusUnauthorized, "session is no longer valid, log in again")
+			return
+		}
+		if err != nil {
+			serverError(w, "getting user", err)
+			return
+		}
+
+		handler(w, r, user)
+	}
+}
+
+func bearerToken(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// decode reads a JSON request body into v, answering 400 if it does not parse.
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("api: writing response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
+
+// serverError logs err and answers 500 without leaking the details.
+func serverError(w http.ResponseWriter, doing string, err error) {
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	log.Printf("api: %s: %v", doing, err)
+	writeError(w, http.StatusInternalServerError, "internal server error")
+}
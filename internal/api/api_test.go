@@ -0,0 +1,466 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lmilojevicc/gator/internal/auth"
+	"github.com/lmilojevicc/gator/internal/database"
+	"github.com/lmilojevicc/gator/internal/filter"
+	"github.com/lmilojevicc/gator/internal/store/memory"
+)
+
+type client struct {
+	t     *testing.T
+	srv   *httptest.Server
+	token string
+}
+
+func newClient(t *testing.T) (*client, *memory.Store) {
+	t.Helper()
+
+	st := memory.New()
+	apiServer := New(st)
+	apiServer.OpenRegistration = true
+	srv := httptest.NewServer(apiServer.Handler())
+	t.Cleanup(srv.Close)
+	return &client{t: t, srv: srv}, st
+}
+
+// do sends body as JSON and decodes the response into out, if given.
+func (c *client) do(method, path string, body, out any) int {
+	c.t.Helper()
+
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			c.t.Fatal(err)
+		}
+	}
+
+	req, err := http.NewRequest(method, c.srv.URL+path, &buf)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.srv.Client().Do(req)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			c.t.Fatalf("%s %s: decoding response: %v", method, path, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+// login registers name and stores its session token in the client.
+func (c *client) login(name string) User {
+	c.t.Helper()
+
+	creds := map[string]string{"name": name, "password": "hunter2"}
+	if status := c.do("POST", "/api/v1/users", creds, nil); status != http.StatusCreated {
+		c.t.Fatalf("register: status %d", status)
+	}
+
+	var session Session
+	if status := c.do("POST", "/api/v1/sessions", creds, &session); status != http.StatusCreated {
+		c.t.Fatalf("login: status %d", status)
+	}
+	c.token = session.Token
+	return session.User
+}
+
+func TestAuthentication(t *testing.T) {
+	c, st := newClient(t)
+
+	if status := c.do("GET", "/api/v1/me", nil, nil); status != http.StatusUnauthorized {
+		t.Errorf("without token: status %d, want 401", status)
+	}
+
+	// Accounts created from the CLI may have no password.
+	_, err := st.CreateUser(context.Background(), database.CreateUserParams{ID: uuid.New(), Name: "cli"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := c.do("POST", "/api/v1/sessions", credentials{Name: "cli"}, nil); status != http.StatusForbidden {
+		t.Errorf("passwordless login: status %d, want 403", status)
+	}
+
+	alice := c.login("alice")
+
+	if status := c.do("POST", "/api/v1/sessions", credentials{Name: "alice", Password: "wrong"}, nil); status != http.StatusUnauthorized {
+		t.Errorf("wrong password: status %d, want 401", status)
+	}
+	if status := c.do("POST", "/api/v1/users", credentials{Name: "alice", Password: "x"}, nil); status != http.StatusConflict {
+		t.Errorf("duplicate register: status %d, want 409", status)
+	}
+
+	var me User
+	if status := c.do("GET", "/api/v1/me", nil, &me); status != http.StatusOK || me.ID != alice.ID {
+		t.Errorf("me = %+v (status %d), want alice", me, status)
+	}
+
+	// A password is not a session, so there is nothing to log out of.
+	req, err := http.NewRequest("DELETE", c.srv.URL+"/api/v1/sessions", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.SetBasicAuth("alice", "hunter2")
+	resp, err := c.srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("logout with a password: status %d, want 400", resp.StatusCode)
+	}
+
+	if status := c.do("DELETE", "/api/v1/sessions", nil, nil); status != http.StatusNoContent {
+		t.Fatalf("logout: status %d", status)
+	}
+	if status := c.do("GET", "/api/v1/me", nil, nil); status != http.StatusUnauthorized {
+		t.Errorf("after logout: status %d, want 401", status)
+	}
+}
+
+func TestRegistration(t *testing.T) {
+	c, st := newClient(t)
+
+	// The first account registered over HTTP is not an admin.
+	alice := c.login("alice")
+	if alice.IsAdmin {
+		t.Error("first user registered over HTTP is an admin")
+	}
+
+	// The same store served with registration closed.
+	closed := httptest.NewServer(New(st).Handler())
+	t.Cleanup(closed.Close)
+	c = &client{t: t, srv: closed, token: c.token}
+
+	creds := credentials{Name: "bob", Password: "hunter2"}
+	if status := c.do("POST", "/api/v1/users", creds, nil); status != http.StatusForbidden {
+		t.Errorf("closed registration as a user: status %d, want 403", status)
+	}
+	c.token = ""
+	if status := c.do("POST", "/api/v1/users", creds, nil); status != http.StatusUnauthorized {
+		t.Errorf("closed registration without a session: status %d, want 401", status)
+	}
+
+	admin, err := st.CreateUser(context.Background(), database.CreateUserParams{ID: uuid.New(), Name: "admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := auth.HashPassword("hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, step := range []error{
+		st.SetUserAdmin(context.Background(), database.SetUserAdminParams{ID: admin.ID, IsAdmin: true}),
+		st.SetUserPassword(context.Background(), database.SetUserPasswordParams{
+			ID:           admin.ID,
+			PasswordHash: sql.NullString{String: hash, Valid: true},
+		}),
+	} {
+		if step != nil {
+			t.Fatal(step)
+		}
+	}
+
+	var session Session
+	c.do("POST", "/api/v1/sessions", credentials{Name: "admin", Password: "hunter2"}, &session)
+	c.token = session.Token
+	var bob User
+	if status := c.do("POST", "/api/v1/users", creds, &bob); status != http.StatusCreated || bob.IsAdmin {
+		t.Errorf("registration by an admin = %+v (status %d), want a non-admin user", bob, status)
+	}
+}
+
+func TestUsersHidePasswordHash(t *testing.T) {
+	c, _ := newClient(t)
+	c.login("alice")
+
+	var users []map[string]any
+	if status := c.do("GET", "/api/v1/users", nil, &users); status != http.StatusOK {
+		t.Fatalf("status %d", status)
+	}
+	if len(users) != 1 {
+		t.Fatalf("got %d users, want 1", len(users))
+	}
+	for key := range users[0] {
+		if key != "id" && key != "name" && key != "is_admin" && key != "created_at" {
+			t.Errorf("unexpected field %q", key)
+		}
+	}
+}
+
+func TestFeedsAndFollows(t *testing.T) {
+	c, _ := newClient(t)
+	c.login("alice")
+
+	var feed Feed
+	status := c.do("POST", "/api/v1/feeds", map[string]string{"name": "Blog", "url": "https://example.com/rss"}, &feed)
+	if status != http.StatusCreated {
+		t.Fatalf("add feed: status %d", status)
+	}
+	if status := c.do("POST", "/api/v1/feeds", map[string]string{"name": "Again", "url": "https://example.com/rss"}, nil); status != http.StatusConflict {
+		t.Errorf("duplicate feed: status %d, want 409", status)
+	}
+
+	// Adding a feed follows it.
+	var follows []Follow
+	c.do("GET", "/api/v1/follows", nil, &follows)
+	if len(follows) != 1 || follows[0].FeedID != feed.ID {
+		t.Fatalf("follows = %+v, want the added feed", follows)
+	}
+
+	if status := c.do("POST", "/api/v1/follows", map[string]string{"feed_url": feed.URL}, nil); status != http.StatusConflict {
+		t.Errorf("follow twice: status %d, want 409", status)
+	}
+
+	if status := c.do("DELETE", "/api/v1/follows/"+feed.ID.String(), nil, nil); status != http.StatusNoContent {
+		t.Fatalf("unfollow: status %d", status)
+	}
+	if status := c.do("DELETE", "/api/v1/follows/"+feed.ID.String(), nil, nil); status != http.StatusNotFound {
+		t.Errorf("unfollow twice: status %d, want 404", status)
+	}
+
+	if status := c.do("POST", "/api/v1/follows", map[string]string{"feed_url": feed.URL}, nil); status != http.StatusCreated {
+		t.Errorf("follow: status %d, want 201", status)
+	}
+	if status := c.do("POST", "/api/v1/follows", map[string]string{"feed_url": "https://missing.example.com"}, nil); status != http.StatusNotFound {
+		t.Errorf("follow missing feed: status %d, want 404", status)
+	}
+}
+
+func TestPosts(t *testing.T) {
+	c, st := newClient(t)
+	c.login("alice")
+
+	var feed Feed
+	c.do("POST", "/api/v1/feeds", map[string]string{"name": "Blog", "url": "https://example.com/rss"}, &feed)
+
+	base := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	params := database.CreatePostsParams{FeedID: feed.ID}
+	for i := range 3 {
+		params.Ids = append(params.Ids, uuid.New())
+		params.Titles = append(params.Titles, fmt.Sprintf("post %d", i))
+		params.Urls = append(params.Urls, "https://example.com/"+uuid.NewString())
+		params.Descriptions = append(params.Descriptions, "")
+		params.PublishedAts = append(params.PublishedAts, base.Add(time.Duration(i)*time.Hour))
+		params.Authors = append(params.Authors, "")
+		params.Categories = append(params.Categories, "")
+	}
+	if _, err := st.CreatePosts(context.Background(), params); err != nil {
+		t.Fatal(err)
+	}
+
+	var page []Post
+	if status := c.do("GET", "/api/v1/posts?limit=2", nil, &page); status != http.StatusOK {
+		t.Fatalf("status %d", status)
+	}
+	if len(page) != 2 || page[0].ID != params.Ids[2] || page[1].ID != params.Ids[1] {
+		t.Fatalf("first page = %+v, want the two newest posts", page)
+	}
+	c.do("GET", "/api/v1/posts?limit=2&offset=2", nil, &page)
+	if len(page) != 1 || page[0].ID != params.Ids[0] {
+		t.Fatalf("second page = %+v, want the oldest post", page)
+	}
+
+	newest := params.Ids[2].String()
+	if status := c.do("PUT", "/api/v1/posts/"+newest+"/read", nil, nil); status != http.StatusNoContent {
+		t.Fatalf("mark read: status %d", status)
+	}
+
+	c.do("GET", "/api/v1/posts?unread=true", nil, &page)
+	if len(page) != 2 {
+		t.Errorf("got %d unread posts, want 2", len(page))
+	}
+	c.do("GET", "/api/v1/posts", nil, &page)
+	if len(page) != 3 || !page[0].Read || page[1].Read {
+		t.Errorf("posts = %+v, want only the newest read", page)
+	}
+
+	if status := c.do("DELETE", "/api/v1/posts/"+newest+"/read", nil, nil); status != http.StatusNoContent {
+		t.Fatalf("mark unread: status %d", status)
+	}
+	c.do("GET", "/api/v1/posts?unread=true", nil, &page)
+	if len(page) != 3 {
+		t.Errorf("got %d unread posts, want 3", len(page))
+	}
+
+	if status := c.do("PUT", "/api/v1/posts/"+uuid.NewString()+"/read", nil, nil); status != http.StatusNotFound {
+		t.Errorf("unknown post: status %d, want 404", status)
+	}
+
+	// Posts of feeds alice does not follow are not hers to mark.
+	bob, err := st.CreateUser(context.Background(), database.CreateUserParams{ID: uuid.New(), Name: "bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := st.CreateFeed(context.Background(), database.CreateFeedParams{
+		ID:     uuid.New(),
+		Name:   "Other",
+		Url:    "https://example.org/rss",
+		UserID: bob.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	unfollowed := uuid.New()
+	_, err = st.CreatePosts(context.Background(), database.CreatePostsParams{
+		FeedID:       other.ID,
+		Ids:          []uuid.UUID{unfollowed},
+		Titles:       []string{"post"},
+		Urls:         []string{"https://example.org/post"},
+		Descriptions: []string{""},
+		PublishedAts: []time.Time{base},
+		Authors:      []string{""},
+		Categories:   []string{""},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status := c.do("PUT", "/api/v1/posts/"+unfollowed.String()+"/read", nil, nil); status != http.StatusNotFound {
+		t.Errorf("post of an unfollowed feed: status %d, want 404", status)
+	}
+	if status := c.do("GET", "/api/v1/posts?limit=1000", nil, nil); status != http.StatusBadRequest {
+		t.Errorf("limit too large: status %d, want 400", status)
+	}
+	if status := c.do("GET", "/api/v1/posts?offset=4294967296", nil, nil); status != http.StatusBadRequest {
+		t.Errorf("offset beyond int32: status %d, want 400", status)
+	}
+
+	// Posts hidden by the filter rules do not count towards the offset.
+	alice, err := st.GetUserByName(context.Background(), "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = st.CreateFilterRule(context.Background(), database.CreateFilterRuleParams{
+		ID:      uuid.New(),
+		UserID:  alice.ID,
+		Action:  filter.ActionExclude,
+		Kind:    filter.KindKeyword,
+		Pattern: "post 1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.do("GET", "/api/v1/posts?limit=1&offset=1", nil, &page)
+	if len(page) != 1 || page[0].ID != params.Ids[0] {
+		t.Errorf("filtered page = %+v, want the oldest post", page)
+	}
+}
+
+func TestBearerTokenIsSessionToken(t *testing.T) {
+	c, st := newClient(t)
+
+	user, err := st.CreateUser(context.Background(), database.CreateUserParams{
+		ID:           uuid.New(),
+		Name:         "alice",
+		PasswordHash: sql.NullString{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A token from the CLI login works against the API too.
+	c.token = auth.NewSessionToken()
+	err = st.CreateSession(context.Background(), database.CreateSessionParams{
+		TokenHash: auth.HashToken(c.token),
+		UserID:    user.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var me User
+	if status := c.do("GET", "/api/v1/me", nil, &me); status != http.StatusOK || me.Name != "alice" {
+		t.Errorf("me = %+v (status %d), want alice", me, status)
+	}
+}
+
+func TestTimeline(t *testing.T) {
+	c, st := newClient(t)
+	c.login("alice")
+
+	var feed Feed
+	c.do("POST", "/api/v1/feeds", map[string]string{"name": "Blog", "url": "https://example.com/rss"}, &feed)
+	_, err := st.CreatePosts(context.Background(), database.CreatePostsParams{
+		FeedID:       feed.ID,
+		Ids:          []uuid.UUID{uuid.New()},
+		Titles:       []string{"Hello"},
+		Urls:         []string{"https://example.com/hello"},
+		Descriptions: []string{""},
+		PublishedAts: []time.Time{time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+		Authors:      []string{""},
+		Categories:   []string{""},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path        string
+		contentType string
+		want        string
+	}{
+		{"/api/v1/timeline.atom", "application/atom+xml; charset=utf-8", `<feed xmlns="http://www.w3.org/2005/Atom">`},
+		{"/api/v1/timeline.rss", "application/rss+xml; charset=utf-8", `<rss version="2.0">`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			// Feed readers authenticate with basic auth rather than tokens.
+			req, err := http.NewRequest("GET", c.srv.URL+tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.SetBasicAuth("alice", "hunter2")
+
+			resp, err := c.srv.Client().Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			var body bytes.Buffer
+			body.ReadFrom(resp.Body)
+			if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != tt.contentType {
+				t.Fatalf("status %d, content type %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+			}
+			if !bytes.Contains(body.Bytes(), []byte(tt.want)) || !bytes.Contains(body.Bytes(), []byte("https://example.com/hello")) {
+				t.Errorf("unexpected body:\n%s", body.String())
+			}
+		})
+	}
+
+	req, err := http.NewRequest("GET", c.srv.URL+"/api/v1/timeline.atom", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.SetBasicAuth("alice", "wrong")
+	resp, err := c.srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized || resp.Header.Get("WWW-Authenticate") == "" {
+		t.Errorf("wrong password: status %d, want 401 with a challenge", resp.StatusCode)
+	}
+}
@@ -0,0 +1,161 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/lmilojevicc/gator/internal/database"
+	"github.com/lmilojevicc/gator/internal/store"
+)
+
+func (srv *Server) handleFeeds(w http.ResponseWriter, r *http.Request, user database.User) {
+	dbFeeds, err := srv.store.GetAllFeeds(r.Context())
+	if err != nil {
+		serverError(w, "getting feeds", err)
+		return
+	}
+
+	feeds := []Feed{}
+	for _, feed := range dbFeeds {
+		feeds = append(feeds, feedFromDB(feed))
+	}
+	writeJSON(w, http.StatusOK, feeds)
+}
+
+// handleAddFeed creates a feed and follows it, like the addfeed command.
+func (srv *Server) handleAddFeed(w http.ResponseWriter, r *http.Request, user database.User) {
+	var body struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	}
+	if !decode(w, r, &body) {
+		return
+	}
+	if body.Name == "" || body.URL == "" {
+		writeError(w, http.StatusBadRequest, "name and url are required")
+		return
+	}
+
+	_, err := srv.store.GetFeedByURL(r.Context(), body.URL)
+	if err == nil {
+		writeError(w, http.StatusConflict, "feed already exists, follow it instead")
+		return
+	}
+	if err != sql.ErrNoRows {
+		serverError(w, "getting feed by url", err)
+		return
+	}
+
+	var feed database.Feed
+	err = srv.store.InTx(r.Context(), func(qtx store.Store) error {
+		feed, err = qtx.CreateFeed(r.Context(), database.CreateFeedParams{
+			ID:     uuid.New(),
+			Name:   body.Name,
+			Url:    body.URL,
+			UserID: user.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = qtx.CreateFeedFollow(r.Context(), database.CreateFeedFollowParams{
+			ID:     uuid.New(),
+			UserID: user.ID,
+			FeedID: feed.ID,
+		})
+		return err
+	})
+	if err != nil {
+		serverError(w, "creating feed", err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, feedFromDB(feed))
+}
+
+func (srv *Server) handleFollows(w http.ResponseWriter, r *http.Request, user database.User) {
+	dbFollows, err := srv.store.GetFeedFollowsForUser(r.Context(), user.ID)
+	if err != nil {
+		serverError(w, "getting follows", err)
+		return
+	}
+
+	follows := []Follow{}
+	for _, follow := range dbFollows {
+		follows = append(follows, followFromDB(follow))
+	}
+	writeJSON(w, http.StatusOK, follows)
+}
+
+func (srv *Server) handleFollow(w http.ResponseWriter, r *http.Request, user database.User) {
+	var body struct {
+		FeedURL string `json:"feed_url"`
+	}
+	if !decode(w, r, &body) {
+		return
+	}
+
+	feed, err := srv.store.GetFeedByURL(r.Context(), body.FeedURL)
+	if err == sql.ErrNoRows {
+		writeError(w, http.StatusNotFound, "no feed with that url")
+		return
+	}
+	if err != nil {
+		serverError(w, "getting feed by url", err)
+		return
+	}
+
+	follows, err := srv.store.GetFeedFollowsForUser(r.Context(), user.ID)
+	if err != nil {
+		serverError(w, "getting follows", err)
+		return
+	}
+	if slices.ContainsFunc(follows, func(f database.GetFeedFollowsForUserRow) bool { return f.FeedID == feed.ID }) {
+		writeError(w, http.StatusConflict, "already following that feed")
+		return
+	}
+
+	follow, err := srv.store.CreateFeedFollow(r.Context(), database.CreateFeedFollowParams{
+		ID:     uuid.New(),
+		UserID: user.ID,
+		FeedID: feed.ID,
+	})
+	if err != nil {
+		serverError(w, "creating follow", err)
+		return
+	}
+
+	// A new follow is not in a category yet.
+	writeJSON(w, http.StatusCreated, Follow{
+		ID:        follow.ID,
+		FeedID:    follow.FeedID,
+		FeedName:  follow.FeedName,
+		CreatedAt: follow.CreatedAt,
+	})
+}
+
+func (srv *Server) handleUnfollow(w http.ResponseWriter, r *http.Request, user database.User) {
+	feedID, err := uuid.Parse(r.PathValue("feedID"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid feed id")
+		return
+	}
+
+	_, err = srv.store.Unfollow(r.Context(), database.UnfollowParams{
+		FeedID: feedID,
+		UserID: user.ID,
+	})
+	if err == sql.ErrNoRows {
+		writeError(w, http.StatusNotFound, "not following that feed")
+		return
+	}
+	if err != nil {
+		serverError(w, "unfollowing feed", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,158 @@
+package api
+
+import (
+	"database/sql"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/lmilojevicc/gator/internal/database"
+	"github.com/lmilojevicc/gator/internal/filter"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// handlePosts lists posts from the feeds the user follows that pass their
+// filter rules, newest first. Query parameters: limit (1-100, default 20),
+// offset, feed_id to list a single feed, and unread=true to skip posts already
+// marked read.
+func (srv *Server) handlePosts(w http.ResponseWriter, r *http.Request, user database.User) {
+	query := r.URL.Query()
+
+	limit, ok := intParam(w, query.Get("limit"), defaultPageSize)
+	if !ok {
+		return
+	}
+	offset, ok := intParam(w, query.Get("offset"), 0)
+	if !ok {
+		return
+	}
+	if limit < 1 || limit > maxPageSize || offset < 0 || offset > math.MaxInt32 {
+		writeError(w, http.StatusBadRequest, "limit must be between 1 and 100 and offset between 0 and 2147483647")
+		return
+	}
+
+	unreadOnly := false
+	if v := query.Get("unread"); v != "" {
+		var err error
+		unreadOnly, err = strconv.ParseBool(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "unread must be true or false")
+			return
+		}
+	}
+
+	var feedID uuid.NullUUID
+	if v := query.Get("feed_id"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid feed id")
+			return
+		}
+		feedID = uuid.NullUUID{UUID: id, Valid: true}
+	}
+
+	f, err := filter.Load(r.Context(), srv.store, user.ID)
+	if err != nil {
+		serverError(w, "getting filter rules", err)
+		return
+	}
+
+	rows, err := filter.Page(f, offset, limit, filter.FromPostsForUser, func(offset, limit int) ([]database.GetPostsForUserRow, error) {
+		return srv.store.GetPostsForUser(r.Context(), database.GetPostsForUserParams{
+			UserID:     user.ID,
+			UnreadOnly: unreadOnly,
+			FeedID:     feedID,
+			PageOffset: int32(offset),
+			PageSize:   int32(limit),
+		})
+	})
+	if err != nil {
+		serverError(w, "getting posts", err)
+		return
+	}
+
+	posts := []Post{}
+	for _, row := range rows {
+		posts = append(posts, postFromDB(row))
+	}
+	writeJSON(w, http.StatusOK, posts)
+}
+
+func (srv *Server) handleMarkRead(w http.ResponseWriter, r *http.Request, user database.User) {
+	postID, ok := srv.postID(w, r, user)
+	if !ok {
+		return
+	}
+
+	err := srv.store.MarkPostRead(r.Context(), database.MarkPostReadParams{
+		UserID: user.ID,
+		PostID: postID,
+	})
+	if err != nil {
+		serverError(w, "marking post read", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (srv *Server) handleMarkUnread(w http.ResponseWriter, r *http.Request, user database.User) {
+	postID, ok := srv.postID(w, r, user)
+	if !ok {
+		return
+	}
+
+	err := srv.store.MarkPostUnread(r.Context(), database.MarkPostUnreadParams{
+		UserID: user.ID,
+		PostID: postID,
+	})
+	if err != nil {
+		serverError(w, "marking post unread", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// postID parses the {postID} path value and checks that the post is in a feed
+// user follows.
+func (srv *Server) postID(w http.ResponseWriter, r *http.Request, user database.User) (uuid.UUID, bool) {
+	id, err := uuid.Parse(r.PathValue("postID"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid post id")
+		return uuid.Nil, false
+	}
+
+	_, err = srv.store.GetPostForUser(r.Context(), database.GetPostForUserParams{
+		ID:     id,
+		UserID: user.ID,
+	})
+	if err == sql.ErrNoRows {
+		writeError(w, http.StatusNotFound, "no such post")
+		return uuid.Nil, false
+	}
+	if err != nil {
+		serverError(w, "getting post", err)
+		return uuid.Nil, false
+	}
+
+	return id, true
+}
+
+func intParam(w http.ResponseWriter, v string, fallback int) (int, bool) {
+	if v == "" {
+		return fallback, true
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid number "+strconv.Quote(v))
+		return 0, false
+	}
+	return n, true
+}
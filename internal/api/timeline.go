@@ -0,0 +1,69 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+
+	"github.com/lmilojevicc/gator/internal/database"
+	"github.com/lmilojevicc/gator/internal/filter"
+	"github.com/lmilojevicc/gator/internal/syndication"
+)
+
+const (
+	defaultTimelineSize = 50
+	maxTimelineSize     = 500
+)
+
+// handleTimeline serves the posts of every feed the user follows that pass
+// their filter rules as Atom or RSS 2.0, depending on the extension of the
+// path. Query parameter: limit (1-500, default 50).
+func (srv *Server) handleTimeline(w http.ResponseWriter, r *http.Request, user database.User) {
+	limit, ok := intParam(w, r.URL.Query().Get("limit"), defaultTimelineSize)
+	if !ok {
+		return
+	}
+	if limit < 1 || limit > maxTimelineSize {
+		writeError(w, http.StatusBadRequest, "limit must be between 1 and 500")
+		return
+	}
+
+	f, err := filter.Load(r.Context(), srv.store, user.ID)
+	if err != nil {
+		serverError(w, "getting filter rules", err)
+		return
+	}
+
+	posts, err := filter.Page(f, 0, limit, filter.FromPostsByUser, func(offset, limit int) ([]database.GetPostsByUserRow, error) {
+		return srv.store.GetPostsByUser(r.Context(), database.GetPostsByUserParams{
+			UserID: user.ID,
+			Limit:  int32(limit),
+			Offset: int32(offset),
+		})
+	})
+	if err != nil {
+		serverError(w, "getting posts", err)
+		return
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	feed := syndication.Timeline(user, posts)
+	feed.Link = scheme + "://" + r.Host + "/"
+	feed.SelfLink = scheme + "://" + r.Host + r.URL.RequestURI()
+
+	var buf bytes.Buffer
+	write, contentType := syndication.WriteRSS, "application/rss+xml; charset=utf-8"
+	if strings.HasSuffix(r.URL.Path, ".atom") {
+		write, contentType = syndication.WriteAtom, "application/atom+xml; charset=utf-8"
+	}
+	if err := write(&buf, feed); err != nil {
+		serverError(w, "writing timeline", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	buf.WriteTo(w)
+}
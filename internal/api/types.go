@@ -0,0 +1,114 @@
+package api
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lmilojevicc/gator/internal/database"
+)
+
+// The response types below decouple the JSON from the generated models, so
+// that columns like users.password_hash never leave the server.
+
+type User struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	IsAdmin   bool      `json:"is_admin"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func userFromDB(user database.User) User {
+	return User{
+		ID:        user.ID,
+		Name:      user.Name,
+		IsAdmin:   user.IsAdmin,
+		CreatedAt: user.CreatedAt,
+	}
+}
+
+type Session struct {
+	Token string `json:"token"`
+	User  User   `json:"user"`
+}
+
+type Feed struct {
+	ID            uuid.UUID  `json:"id"`
+	Name          string     `json:"name"`
+	URL           string     `json:"url"`
+	UserID        uuid.UUID  `json:"user_id"`
+	CreatedAt     time.Time  `json:"created_at"`
+	LastFetchedAt *time.Time `json:"last_fetched_at"`
+	DeadAt        *time.Time `json:"dead_at"`
+}
+
+func feedFromDB(feed database.Feed) Feed {
+	return Feed{
+		ID:            feed.ID,
+		Name:          feed.Name,
+		URL:           feed.Url,
+		UserID:        feed.UserID,
+		CreatedAt:     feed.CreatedAt,
+		LastFetchedAt: nullTime(feed.LastFetchedAt),
+		DeadAt:        nullTime(feed.DeadAt),
+	}
+}
+
+type Follow struct {
+	ID        uuid.UUID `json:"id"`
+	FeedID    uuid.UUID `json:"feed_id"`
+	FeedName  string    `json:"feed_name"`
+	Category  *string   `json:"category"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func followFromDB(follow database.GetFeedFollowsForUserRow) Follow {
+	return Follow{
+		ID:        follow.ID,
+		FeedID:    follow.FeedID,
+		FeedName:  follow.FeedName,
+		Category:  nullString(follow.CategoryName),
+		CreatedAt: follow.CreatedAt,
+	}
+}
+
+type Post struct {
+	ID          uuid.UUID  `json:"id"`
+	Title       string     `json:"title"`
+	URL         string     `json:"url"`
+	Description string     `json:"description"`
+	PublishedAt *time.Time `json:"published_at"`
+	FeedID      uuid.UUID  `json:"feed_id"`
+	FeedName    string     `json:"feed_name"`
+	Read        bool       `json:"read"`
+	Saved       bool       `json:"saved"`
+}
+
+func postFromDB(post database.GetPostsForUserRow) Post {
+	return Post{
+		ID:          post.ID,
+		Title:       post.Title.String,
+		URL:         post.Url,
+		Description: post.Description.String,
+		PublishedAt: nullTime(post.PublishedAt),
+		FeedID:      post.FeedID,
+		FeedName:    post.FeedName,
+		Read:        post.IsRead,
+		Saved:       post.IsSaved,
+	}
+}
+
+func nullTime(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func nullString(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	return &s.String
+}
@@ -0,0 +1,142 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/lmilojevicc/gator/internal/auth"
+	"github.com/lmilojevicc/gator/internal/database"
+)
+
+type credentials struct {
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+// handleLogin exchanges a username and password for a session token. Accounts
+// without a password can only be used from the CLI.
+func (srv *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	var body credentials
+	if !decode(w, r, &body) {
+		return
+	}
+
+	user, err := auth.Authenticate(r.Context(), srv.store, body.Name, body.Password)
+	if errors.Is(err, auth.ErrWrongPassword) {
+		writeError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+	if errors.Is(err, auth.ErrNoPassword) {
+		writeError(w, http.StatusForbidden, err.Error())
+		return
+	}
+	if err != nil {
+		serverError(w, "logging in", err)
+		return
+	}
+
+	token, err := auth.StartSession(r.Context(), srv.store, user.ID)
+	if err != nil {
+		serverError(w, "logging in", err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, Session{Token: token, User: userFromDB(user)})
+}
+
+// handleLogout ends the session of the bearer token. Requests authenticated
+// with a password have no session to end.
+func (srv *Server) handleLogout(w http.ResponseWriter, r *http.Request, user database.User) {
+	token := bearerToken(r)
+	if token == "" {
+		writeError(w, http.StatusBadRequest, "log out with the session token, not a password")
+		return
+	}
+
+	err := srv.store.DeleteSession(r.Context(), auth.HashToken(token))
+	if err != nil {
+		serverError(w, "deleting session", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleRegister creates a user, for anyone if registration is open and for
+// admins otherwise. Unlike the CLI, a password is required since it is the
+// only way to log in over the API, and the user is never an admin, even the
+// first one.
+func (srv *Server) handleRegister(w http.ResponseWriter, r *http.Request) {
+	if srv.OpenRegistration {
+		srv.register(w, r)
+		return
+	}
+
+	srv.authenticated(func(w http.ResponseWriter, r *http.Request, user database.User) {
+		if !auth.IsAdmin(user) {
+			writeError(w, http.StatusForbidden, "registration is closed, ask an admin to create your account")
+			return
+		}
+		srv.register(w, r)
+	})(w, r)
+}
+
+func (srv *Server) register(w http.ResponseWriter, r *http.Request) {
+	var body credentials
+	if !decode(w, r, &body) {
+		return
+	}
+	if body.Name == "" || body.Password == "" {
+		writeError(w, http.StatusBadRequest, "name and password are required")
+		return
+	}
+
+	_, err := srv.store.GetUserByName(r.Context(), body.Name)
+	if err == nil {
+		writeError(w, http.StatusConflict, "user already exists")
+		return
+	}
+	if err != sql.ErrNoRows {
+		serverError(w, "getting user", err)
+		return
+	}
+
+	hash, err := auth.HashPassword(body.Password)
+	if err != nil {
+		serverError(w, "hashing password", err)
+		return
+	}
+
+	user, err := srv.store.CreateNonAdminUser(r.Context(), database.CreateNonAdminUserParams{
+		ID:           uuid.New(),
+		Name:         body.Name,
+		PasswordHash: sql.NullString{String: hash, Valid: true},
+	})
+	if err != nil {
+		serverError(w, "creating user", err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, userFromDB(user))
+}
+
+func (srv *Server) handleUsers(w http.ResponseWriter, r *http.Request, user database.User) {
+	dbUsers, err := srv.store.GetUsers(r.Context())
+	if err != nil {
+		serverError(w, "getting users", err)
+		return
+	}
+
+	users := []User{}
+	for _, u := range dbUsers {
+		users = append(users, userFromDB(u))
+	}
+	writeJSON(w, http.StatusOK, users)
+}
+
+func (srv *Server) handleMe(w http.ResponseWriter, r *http.Request, user database.User) {
+	writeJSON(w, http.StatusOK, userFromDB(user))
+}
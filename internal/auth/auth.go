@@ -0,0 +1,94 @@
+// Package auth hashes passwords and issues session tokens.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/lmilojevicc/gator/internal/database"
+)
+
+// ErrWrongPassword is returned by CheckPassword when password does not match.
+var ErrWrongPassword = errors.New("invalid username or password")
+
+// ErrNoPassword is returned by Authenticate for accounts without a password,
+// which can only be used from the CLI.
+var ErrNoPassword = errors.New("account has no password, set one with the passwd command")
+
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("hashing password: %w", err)
+	}
+	return string(hash), nil
+}
+
+func CheckPassword(hash, password string) error {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return ErrWrongPassword
+	}
+	if err != nil {
+		return fmt.Errorf("checking password: %w", err)
+	}
+	return nil
+}
+
+// IsAdmin reports whether user may use admin rights. An admin without a
+// password, such as the oldest account promoted by the admin migration, has
+// none until they set one, since anyone can log in as them.
+func IsAdmin(user database.User) bool {
+	return user.IsAdmin && user.PasswordHash.Valid
+}
+
+// NewSessionToken returns a random token for the client to keep. Only its
+// HashToken is stored, so a leaked database does not leak sessions.
+func NewSessionToken() string {
+	return rand.Text()
+}
+
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Authenticate checks the password of the named user for logins over HTTP. An
+// unknown name is reported as ErrWrongPassword, like a wrong password.
+func Authenticate(ctx context.Context, q database.Querier, name, password string) (database.User, error) {
+	user, err := q.GetUserByName(ctx, name)
+	if err == sql.ErrNoRows {
+		return database.User{}, ErrWrongPassword
+	}
+	if err != nil {
+		return database.User{}, fmt.Errorf("getting user: %w", err)
+	}
+
+	if !user.PasswordHash.Valid {
+		return database.User{}, ErrNoPassword
+	}
+	if err := CheckPassword(user.PasswordHash.String, password); err != nil {
+		return database.User{}, err
+	}
+	return user, nil
+}
+
+// StartSession creates a session for userID and returns its token.
+func StartSession(ctx context.Context, q database.Querier, userID uuid.UUID) (string, error) {
+	token := NewSessionToken()
+	err := q.CreateSession(ctx, database.CreateSessionParams{
+		TokenHash: HashToken(token),
+		UserID:    userID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("creating session: %w", err)
+	}
+	return token, nil
+}
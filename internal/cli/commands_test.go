@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lmilojevicc/gator/internal/state"
+)
+
+func TestCommandsRun(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	var got Command
+	cmds := Commands{RegisteredCommands: map[string]func(*state.State, Command) error{}}
+	cmds.Register("ok", func(s *state.State, cmd Command) error {
+		got = cmd
+		return nil
+	})
+	cmds.Register("fail", func(s *state.State, cmd Command) error {
+		return errFailed
+	})
+
+	tests := []struct {
+		name    string
+		cmd     Command
+		wantErr error
+		wantMsg string
+	}{
+		{name: "registered", cmd: Command{Name: "ok", Arguments: []string{"a", "b"}}},
+		{name: "handler error", cmd: Command{Name: "fail"}, wantErr: errFailed},
+		{name: "unknown", cmd: Command{Name: "nope"}, wantMsg: "nope command not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = Command{}
+			err := cmds.Run(&state.State{}, tt.cmd)
+
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("err = %v, want %v", err, tt.wantErr)
+				}
+			case tt.wantMsg != "":
+				if err == nil || err.Error() != tt.wantMsg {
+					t.Errorf("err = %v, want %q", err, tt.wantMsg)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if got.Name != tt.cmd.Name || len(got.Arguments) != len(tt.cmd.Arguments) {
+					t.Errorf("handler got %+v, want %+v", got, tt.cmd)
+				}
+			}
+		})
+	}
+}
@@ -5,24 +5,120 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 const configFileName = "config.json"
 
+// Storage backends selectable through the db_url scheme.
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
 type Config struct {
-	DBURL           string `json:"db_url"`
-	CurrentUserName string `json:"current_user_name"`
+	// DBURL is a PostgreSQL connection string, or sqlite:///path/to/gator.db
+	// to store everything in a local SQLite file.
+	DBURL string `json:"db_url"`
+	// SessionToken is issued by login; the user it belongs to is looked up in
+	// the database on every command.
+	SessionToken string `json:"session_token"`
+	// RedirectThreshold is how many consecutive fetches must permanently
+	// redirect to the same URL before the feed's URL is updated.
+	RedirectThreshold int `json:"redirect_threshold"`
+	// AllowedSecrets lists the secret references feed credentials may use, as
+	// path.Match patterns such as "env:GATOR_FEED_*" or "file:/etc/gator/*".
+	// Feed credentials are refused when it is empty.
+	AllowedSecrets []string `json:"allowed_secrets"`
+	// AllowedWebhookNetworks lists the private networks, as CIDR prefixes
+	// such as "10.1.0.0/16", that notification webhooks may point at.
+	// Webhooks may not point at private addresses when it is empty.
+	AllowedWebhookNetworks []string `json:"allowed_webhook_networks"`
+	// OpenRegistration lets anyone create an account through the API of
+	// serve. Otherwise only admins can.
+	OpenRegistration bool            `json:"open_registration"`
+	Fetch            FetchConfig     `json:"fetch"`
+	Retention        RetentionConfig `json:"retention"`
+	SMTP             SMTPConfig      `json:"smtp"`
+}
+
+// SMTPConfig is the mail server digest sends through. STARTTLS is used when
+// the server offers it.
+type SMTPConfig struct {
+	Host string `json:"host"`
+	Port int    `json:"port"`
+	// Username enables PLAIN authentication with Password, a secret reference
+	// of the form "env:NAME" or "file:/path".
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// From is the sender address of the digests.
+	From string `json:"from"`
+}
+
+// RetentionConfig controls which posts prune removes. Saved posts are always kept.
+type RetentionConfig struct {
+	// MaxAge is a time.ParseDuration string; older posts are removed.
+	MaxAge string `json:"max_age"`
+	// MaxPostsPerFeed keeps only the newest posts of every feed.
+	MaxPostsPerFeed int `json:"max_posts_per_feed"`
+	// Auto prunes after every agg tick.
+	Auto bool `json:"auto"`
+}
+
+// FetchConfig configures the HTTP client used to fetch feeds.
+type FetchConfig struct {
+	// Timeout is a time.ParseDuration string covering the whole request.
+	Timeout string `json:"timeout"`
+	// MaxBodyBytes caps the size of a feed response.
+	MaxBodyBytes int64 `json:"max_body_bytes"`
+	// MaxItems caps how many items are processed per fetch.
+	MaxItems int `json:"max_items"`
+	// UserAgent replaces the default "gator/<version>" user agent.
+	UserAgent string `json:"user_agent"`
+	// Contact is appended to the user agent so feed owners can reach us.
+	Contact string `json:"contact"`
+	// ProxyURL overrides the proxy taken from the environment.
+	ProxyURL string    `json:"proxy_url"`
+	TLS      TLSConfig `json:"tls"`
+}
+
+type TLSConfig struct {
+	InsecureSkipVerify bool `json:"insecure_skip_verify"`
+	// CAFile is a PEM bundle trusted in addition to the system roots.
+	CAFile string `json:"ca_file"`
+	// MinVersion is one of "1.0", "1.1", "1.2" or "1.3".
+	MinVersion string `json:"min_version"`
 }
 
 func getDefaults() Config {
 	return Config{
-		DBURL:           "postgres://localhost:5432/gator?sslmode=disable",
-		CurrentUserName: "",
+		DBURL:             "postgres://localhost:5432/gator?sslmode=disable",
+		RedirectThreshold: 3,
+		Fetch: FetchConfig{
+			Timeout:      "30s",
+			MaxBodyBytes: 10 << 20,
+			MaxItems:     1000,
+		},
+		SMTP: SMTPConfig{
+			Port: 587,
+		},
+	}
+}
+
+// Database returns the storage backend selected by DBURL and the data source
+// to open it with.
+func (cfg *Config) Database() (driver, source string) {
+	if path, ok := strings.CutPrefix(cfg.DBURL, "sqlite://"); ok {
+		return DriverSQLite, path
+	}
+	if path, ok := strings.CutPrefix(cfg.DBURL, "sqlite:"); ok {
+		return DriverSQLite, path
 	}
+	return DriverPostgres, cfg.DBURL
 }
 
-func (cfg *Config) SetUser(name string) error {
-	cfg.CurrentUserName = name
+func (cfg *Config) SetSession(token string) error {
+	cfg.SessionToken = token
 	return cfg.Save()
 }
 
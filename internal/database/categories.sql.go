@@ -0,0 +1,171 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: categories.sql
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createCategory = `-- name: CreateCategory :one
+INSERT INTO categories (id, user_id, name, created_at)
+VALUES ($1, $2, $3, now())
+RETURNING id, seq, user_id, name, created_at
+`
+
+type CreateCategoryParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+	Name   string
+}
+
+func (q *Queries) CreateCategory(ctx context.Context, arg CreateCategoryParams) (Category, error) {
+	row := q.db.QueryRowContext(ctx, createCategory, arg.ID, arg.UserID, arg.Name)
+	var i Category
+	err := row.Scan(
+		&i.ID,
+		&i.Seq,
+		&i.UserID,
+		&i.Name,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteCategory = `-- name: DeleteCategory :execrows
+DELETE FROM categories
+WHERE id = $1 AND user_id = $2
+`
+
+type DeleteCategoryParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) DeleteCategory(ctx context.Context, arg DeleteCategoryParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteCategory, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getCategoriesForUser = `-- name: GetCategoriesForUser :many
+SELECT
+    categories.id, categories.seq, categories.user_id, categories.name, categories.created_at,
+    (
+        SELECT count(*) FROM feed_follows
+        WHERE feed_follows.category_id = categories.id
+    ) AS feed_count
+FROM categories
+WHERE categories.user_id = $1
+ORDER BY categories.name
+`
+
+type GetCategoriesForUserRow struct {
+	ID        uuid.UUID
+	Seq       int64
+	UserID    uuid.UUID
+	Name      string
+	CreatedAt time.Time
+	FeedCount int64
+}
+
+func (q *Queries) GetCategoriesForUser(ctx context.Context, userID uuid.UUID) ([]GetCategoriesForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getCategoriesForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetCategoriesForUserRow
+	for rows.Next() {
+		var i GetCategoriesForUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Seq,
+			&i.UserID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.FeedCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getCategoryByName = `-- name: GetCategoryByName :one
+SELECT id, seq, user_id, name, created_at FROM categories
+WHERE user_id = $1 AND name = $2
+`
+
+type GetCategoryByNameParams struct {
+	UserID uuid.UUID
+	Name   string
+}
+
+func (q *Queries) GetCategoryByName(ctx context.Context, arg GetCategoryByNameParams) (Category, error) {
+	row := q.db.QueryRowContext(ctx, getCategoryByName, arg.UserID, arg.Name)
+	var i Category
+	err := row.Scan(
+		&i.ID,
+		&i.Seq,
+		&i.UserID,
+		&i.Name,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const renameCategory = `-- name: RenameCategory :execrows
+UPDATE categories
+SET name = $3
+WHERE id = $1 AND user_id = $2
+`
+
+type RenameCategoryParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+	Name   string
+}
+
+func (q *Queries) RenameCategory(ctx context.Context, arg RenameCategoryParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, renameCategory, arg.ID, arg.UserID, arg.Name)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setFollowCategory = `-- name: SetFollowCategory :execrows
+UPDATE feed_follows
+SET category_id = $1, updated_at = now()
+WHERE user_id = $2 AND feed_id = $3
+`
+
+type SetFollowCategoryParams struct {
+	CategoryID uuid.NullUUID
+	UserID     uuid.UUID
+	FeedID     uuid.UUID
+}
+
+// category_id NULL takes the feed out of its category.
+func (q *Queries) SetFollowCategory(ctx context.Context, arg SetFollowCategoryParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setFollowCategory, arg.CategoryID, arg.UserID, arg.FeedID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,175 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: digest.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const getDigestPosts = `-- name: GetDigestPosts :many
+SELECT
+    posts.seq,
+    posts.feed_id,
+    COALESCE(feed_follows.title, feeds.name) AS feed_name,
+    posts.title,
+    posts.url,
+    posts.published_at,
+    posts.description,
+    posts.author,
+    posts.categories
+FROM posts
+INNER JOIN feeds ON posts.feed_id = feeds.id
+INNER JOIN feed_follows ON posts.feed_id = feed_follows.feed_id
+WHERE
+    feed_follows.user_id = $1
+    AND posts.created_at > $2
+    AND posts.created_at <= $3
+    AND posts.seq > $4
+ORDER BY posts.seq
+LIMIT $6 OFFSET $5
+`
+
+type GetDigestPostsParams struct {
+	UserID      uuid.UUID
+	AddedAfter  time.Time
+	AddedBefore time.Time
+	AfterSeq    int64
+	PageOffset  int32
+	MaxPosts    int32
+}
+
+type GetDigestPostsRow struct {
+	Seq         int64
+	FeedID      uuid.UUID
+	FeedName    string
+	Title       sql.NullString
+	Url         string
+	PublishedAt sql.NullTime
+	Description sql.NullString
+	Author      sql.NullString
+	Categories  sql.NullString
+}
+
+// The posts added to the user's follows in (added_after, added_before] after
+// the post with seq after_seq, in the order they were added.
+func (q *Queries) GetDigestPosts(ctx context.Context, arg GetDigestPostsParams) ([]GetDigestPostsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getDigestPosts,
+		arg.UserID,
+		arg.AddedAfter,
+		arg.AddedBefore,
+		arg.AfterSeq,
+		arg.PageOffset,
+		arg.MaxPosts,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetDigestPostsRow
+	for rows.Next() {
+		var i GetDigestPostsRow
+		if err := rows.Scan(
+			&i.Seq,
+			&i.FeedID,
+			&i.FeedName,
+			&i.Title,
+			&i.Url,
+			&i.PublishedAt,
+			&i.Description,
+			&i.Author,
+			&i.Categories,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDigestSubscribers = `-- name: GetDigestSubscribers :many
+SELECT id, created_at, updated_at, name, password_hash, is_admin, fever_key_hash, email, digest_frequency, last_digest_at, last_digest_seq FROM users
+WHERE email IS NOT NULL AND digest_frequency IS NOT NULL
+ORDER BY name
+`
+
+func (q *Queries) GetDigestSubscribers(ctx context.Context) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, getDigestSubscribers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.PasswordHash,
+			&i.IsAdmin,
+			&i.FeverKeyHash,
+			&i.Email,
+			&i.DigestFrequency,
+			&i.LastDigestAt,
+			&i.LastDigestSeq,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setUserDigest = `-- name: SetUserDigest :exec
+UPDATE users
+SET email = $2, digest_frequency = $3, updated_at = now()
+WHERE id = $1
+`
+
+type SetUserDigestParams struct {
+	ID              uuid.UUID
+	Email           sql.NullString
+	DigestFrequency sql.NullString
+}
+
+func (q *Queries) SetUserDigest(ctx context.Context, arg SetUserDigestParams) error {
+	_, err := q.db.ExecContext(ctx, setUserDigest, arg.ID, arg.Email, arg.DigestFrequency)
+	return err
+}
+
+const setUserLastDigest = `-- name: SetUserLastDigest :exec
+UPDATE users
+SET last_digest_at = $2, last_digest_seq = $3
+WHERE id = $1
+`
+
+type SetUserLastDigestParams struct {
+	ID            uuid.UUID
+	LastDigestAt  sql.NullTime
+	LastDigestSeq sql.NullInt64
+}
+
+func (q *Queries) SetUserLastDigest(ctx context.Context, arg SetUserLastDigestParams) error {
+	_, err := q.db.ExecContext(ctx, setUserLastDigest, arg.ID, arg.LastDigestAt, arg.LastDigestSeq)
+	return err
+}
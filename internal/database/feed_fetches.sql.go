@@ -0,0 +1,90 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: feed_fetches.sql
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createFeedFetch = `-- name: CreateFeedFetch :exec
+INSERT INTO feed_fetches (
+    id, fetched_at, feed_id, content_encoding, compressed_bytes, uncompressed_bytes
+)
+VALUES ($1, now(), $2, $3, $4, $5)
+`
+
+type CreateFeedFetchParams struct {
+	ID                uuid.UUID
+	FeedID            uuid.UUID
+	ContentEncoding   string
+	CompressedBytes   int64
+	UncompressedBytes int64
+}
+
+func (q *Queries) CreateFeedFetch(ctx context.Context, arg CreateFeedFetchParams) error {
+	_, err := q.db.ExecContext(ctx, createFeedFetch,
+		arg.ID,
+		arg.FeedID,
+		arg.ContentEncoding,
+		arg.CompressedBytes,
+		arg.UncompressedBytes,
+	)
+	return err
+}
+
+const getFeedBandwidth = `-- name: GetFeedBandwidth :many
+SELECT
+    feeds.name,
+    feeds.url,
+    count(*) AS fetches,
+    sum(feed_fetches.compressed_bytes)::BIGINT AS compressed_bytes,
+    sum(feed_fetches.uncompressed_bytes)::BIGINT AS uncompressed_bytes
+FROM feed_fetches
+INNER JOIN feeds ON feed_fetches.feed_id = feeds.id
+WHERE feed_fetches.fetched_at >= $1
+GROUP BY feeds.id
+ORDER BY compressed_bytes DESC
+`
+
+type GetFeedBandwidthRow struct {
+	Name              string
+	Url               string
+	Fetches           int64
+	CompressedBytes   int64
+	UncompressedBytes int64
+}
+
+func (q *Queries) GetFeedBandwidth(ctx context.Context, fetchedAt time.Time) ([]GetFeedBandwidthRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedBandwidth, fetchedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFeedBandwidthRow
+	for rows.Next() {
+		var i GetFeedBandwidthRow
+		if err := rows.Scan(
+			&i.Name,
+			&i.Url,
+			&i.Fetches,
+			&i.CompressedBytes,
+			&i.UncompressedBytes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
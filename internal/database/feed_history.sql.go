@@ -0,0 +1,69 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: feed_history.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const createFeedHistory = `-- name: CreateFeedHistory :exec
+INSERT INTO feed_history (id, created_at, feed_id, event, detail)
+VALUES ($1, now(), $2, $3, $4)
+`
+
+type CreateFeedHistoryParams struct {
+	ID     uuid.UUID
+	FeedID uuid.UUID
+	Event  string
+	Detail string
+}
+
+func (q *Queries) CreateFeedHistory(ctx context.Context, arg CreateFeedHistoryParams) error {
+	_, err := q.db.ExecContext(ctx, createFeedHistory,
+		arg.ID,
+		arg.FeedID,
+		arg.Event,
+		arg.Detail,
+	)
+	return err
+}
+
+const getFeedHistory = `-- name: GetFeedHistory :many
+SELECT id, created_at, feed_id, event, detail FROM feed_history
+WHERE feed_id = $1
+ORDER BY created_at DESC
+`
+
+func (q *Queries) GetFeedHistory(ctx context.Context, feedID uuid.UUID) ([]FeedHistory, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedHistory, feedID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FeedHistory
+	for rows.Next() {
+		var i FeedHistory
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.FeedID,
+			&i.Event,
+			&i.Detail,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -7,14 +7,26 @@ package database
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/google/uuid"
 )
 
+const clearFeedRedirect = `-- name: ClearFeedRedirect :exec
+UPDATE feeds
+SET redirect_url = NULL, redirect_count = 0
+WHERE id = $1
+`
+
+func (q *Queries) ClearFeedRedirect(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, clearFeedRedirect, id)
+	return err
+}
+
 const createFeed = `-- name: CreateFeed :one
 INSERT INTO feeds (id, name, url, user_id, created_at, updated_at)
 VALUES ($1, $2, $3, $4, now(), now())
-RETURNING id, name, url, user_id, created_at, updated_at, last_fetched_at
+RETURNING id, name, url, user_id, created_at, updated_at, last_fetched_at, redirect_url, redirect_count, dead_at, seq
 `
 
 type CreateFeedParams struct {
@@ -40,12 +52,26 @@ func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, e
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.LastFetchedAt,
+		&i.RedirectUrl,
+		&i.RedirectCount,
+		&i.DeadAt,
+		&i.Seq,
 	)
 	return i, err
 }
 
+const deleteFeed = `-- name: DeleteFeed :exec
+DELETE FROM feeds
+WHERE id = $1
+`
+
+func (q *Queries) DeleteFeed(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteFeed, id)
+	return err
+}
+
 const getAllFeeds = `-- name: GetAllFeeds :many
-SELECT id, name, url, user_id, created_at, updated_at, last_fetched_at FROM feeds
+SELECT id, name, url, user_id, created_at, updated_at, last_fetched_at, redirect_url, redirect_count, dead_at, seq FROM feeds
 `
 
 func (q *Queries) GetAllFeeds(ctx context.Context) ([]Feed, error) {
@@ -65,6 +91,10 @@ func (q *Queries) GetAllFeeds(ctx context.Context) ([]Feed, error) {
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.LastFetchedAt,
+			&i.RedirectUrl,
+			&i.RedirectCount,
+			&i.DeadAt,
+			&i.Seq,
 		); err != nil {
 			return nil, err
 		}
@@ -80,7 +110,7 @@ func (q *Queries) GetAllFeeds(ctx context.Context) ([]Feed, error) {
 }
 
 const getFeedByURL = `-- name: GetFeedByURL :one
-SELECT id, name, url, user_id, created_at, updated_at, last_fetched_at FROM feeds
+SELECT id, name, url, user_id, created_at, updated_at, last_fetched_at, redirect_url, redirect_count, dead_at, seq FROM feeds
 WHERE url = $1
 `
 
@@ -95,13 +125,18 @@ func (q *Queries) GetFeedByURL(ctx context.Context, url string) (Feed, error) {
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.LastFetchedAt,
+		&i.RedirectUrl,
+		&i.RedirectCount,
+		&i.DeadAt,
+		&i.Seq,
 	)
 	return i, err
 }
 
 const getNextFeedToFetch = `-- name: GetNextFeedToFetch :one
-SELECT id, name, url, user_id, created_at, updated_at, last_fetched_at
+SELECT id, name, url, user_id, created_at, updated_at, last_fetched_at, redirect_url, redirect_count, dead_at, seq
 FROM feeds
+WHERE dead_at IS NULL
 ORDER BY last_fetched_at ASC NULLS FIRST
 LIMIT 1
 `
@@ -117,10 +152,25 @@ func (q *Queries) GetNextFeedToFetch(ctx context.Context) (Feed, error) {
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.LastFetchedAt,
+		&i.RedirectUrl,
+		&i.RedirectCount,
+		&i.DeadAt,
+		&i.Seq,
 	)
 	return i, err
 }
 
+const markFeedDead = `-- name: MarkFeedDead :exec
+UPDATE feeds
+SET updated_at = now(), dead_at = now()
+WHERE id = $1
+`
+
+func (q *Queries) MarkFeedDead(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, markFeedDead, id)
+	return err
+}
+
 const markFeedFetched = `-- name: MarkFeedFetched :exec
 UPDATE feeds
 SET updated_at = now(), last_fetched_at = now()
@@ -131,3 +181,94 @@ func (q *Queries) MarkFeedFetched(ctx context.Context, id uuid.UUID) error {
 	_, err := q.db.ExecContext(ctx, markFeedFetched, id)
 	return err
 }
+
+const recordFeedRedirect = `-- name: RecordFeedRedirect :one
+UPDATE feeds
+SET
+    redirect_count = CASE
+        WHEN redirect_url = $2 THEN redirect_count + 1
+        ELSE 1
+    END,
+    redirect_url = $2
+WHERE id = $1
+RETURNING redirect_count
+`
+
+type RecordFeedRedirectParams struct {
+	ID          uuid.UUID
+	RedirectUrl sql.NullString
+}
+
+func (q *Queries) RecordFeedRedirect(ctx context.Context, arg RecordFeedRedirectParams) (int32, error) {
+	row := q.db.QueryRowContext(ctx, recordFeedRedirect, arg.ID, arg.RedirectUrl)
+	var redirect_count int32
+	err := row.Scan(&redirect_count)
+	return redirect_count, err
+}
+
+const renameFeed = `-- name: RenameFeed :one
+UPDATE feeds
+SET name = $2, updated_at = now()
+WHERE id = $1
+RETURNING id, name, url, user_id, created_at, updated_at, last_fetched_at, redirect_url, redirect_count, dead_at, seq
+`
+
+type RenameFeedParams struct {
+	ID   uuid.UUID
+	Name string
+}
+
+func (q *Queries) RenameFeed(ctx context.Context, arg RenameFeedParams) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, renameFeed, arg.ID, arg.Name)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LastFetchedAt,
+		&i.RedirectUrl,
+		&i.RedirectCount,
+		&i.DeadAt,
+		&i.Seq,
+	)
+	return i, err
+}
+
+const updateFeedURL = `-- name: UpdateFeedURL :one
+UPDATE feeds
+SET
+    url = $2,
+    redirect_url = NULL,
+    redirect_count = 0,
+    dead_at = NULL,
+    updated_at = now()
+WHERE id = $1
+RETURNING id, name, url, user_id, created_at, updated_at, last_fetched_at, redirect_url, redirect_count, dead_at, seq
+`
+
+type UpdateFeedURLParams struct {
+	ID  uuid.UUID
+	Url string
+}
+
+func (q *Queries) UpdateFeedURL(ctx context.Context, arg UpdateFeedURLParams) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, updateFeedURL, arg.ID, arg.Url)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LastFetchedAt,
+		&i.RedirectUrl,
+		&i.RedirectCount,
+		&i.DeadAt,
+		&i.Seq,
+	)
+	return i, err
+}
@@ -0,0 +1,329 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: fever.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const getFeverFeeds = `-- name: GetFeverFeeds :many
+
+SELECT feeds.seq, COALESCE(feed_follows.title, feeds.name) AS name, feeds.url, feeds.last_fetched_at, categories.seq AS category_seq
+FROM feeds
+INNER JOIN feed_follows ON feeds.id = feed_follows.feed_id
+LEFT JOIN categories ON feed_follows.category_id = categories.id
+WHERE feed_follows.user_id = $1
+ORDER BY feeds.seq
+`
+
+type GetFeverFeedsRow struct {
+	Seq           int64
+	Name          string
+	Url           string
+	LastFetchedAt sql.NullTime
+	CategorySeq   sql.NullInt64
+}
+
+// Queries for the Fever API, which identifies feeds and posts by their seq.
+func (q *Queries) GetFeverFeeds(ctx context.Context, userID uuid.UUID) ([]GetFeverFeedsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFeverFeeds, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFeverFeedsRow
+	for rows.Next() {
+		var i GetFeverFeedsRow
+		if err := rows.Scan(
+			&i.Seq,
+			&i.Name,
+			&i.Url,
+			&i.LastFetchedAt,
+			&i.CategorySeq,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFeverItemStates = `-- name: GetFeverItemStates :many
+SELECT
+    posts.seq,
+    posts.feed_id,
+    posts.title,
+    posts.description,
+    posts.author,
+    posts.categories,
+    EXISTS (
+        SELECT 1 FROM read_posts
+        WHERE read_posts.post_id = posts.id AND read_posts.user_id = feed_follows.user_id
+    ) AS is_read,
+    EXISTS (
+        SELECT 1 FROM saved_posts
+        WHERE saved_posts.post_id = posts.id AND saved_posts.user_id = feed_follows.user_id
+    ) AS is_saved
+FROM posts
+INNER JOIN feed_follows ON posts.feed_id = feed_follows.feed_id
+WHERE feed_follows.user_id = $1
+ORDER BY posts.seq
+`
+
+type GetFeverItemStatesRow struct {
+	Seq         int64
+	FeedID      uuid.UUID
+	Title       sql.NullString
+	Description sql.NullString
+	Author      sql.NullString
+	Categories  sql.NullString
+	IsRead      bool
+	IsSaved     bool
+}
+
+func (q *Queries) GetFeverItemStates(ctx context.Context, userID uuid.UUID) ([]GetFeverItemStatesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFeverItemStates, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFeverItemStatesRow
+	for rows.Next() {
+		var i GetFeverItemStatesRow
+		if err := rows.Scan(
+			&i.Seq,
+			&i.FeedID,
+			&i.Title,
+			&i.Description,
+			&i.Author,
+			&i.Categories,
+			&i.IsRead,
+			&i.IsSaved,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFeverItemsAfter = `-- name: GetFeverItemsAfter :many
+SELECT
+    posts.id,
+    posts.seq,
+    feeds.seq AS feed_seq,
+    posts.title,
+    posts.url,
+    posts.description,
+    posts.published_at,
+    posts.created_at,
+    posts.feed_id,
+    posts.author,
+    posts.categories,
+    EXISTS (
+        SELECT 1 FROM read_posts
+        WHERE read_posts.post_id = posts.id AND read_posts.user_id = feed_follows.user_id
+    ) AS is_read,
+    EXISTS (
+        SELECT 1 FROM saved_posts
+        WHERE saved_posts.post_id = posts.id AND saved_posts.user_id = feed_follows.user_id
+    ) AS is_saved
+FROM posts
+INNER JOIN feeds ON posts.feed_id = feeds.id
+INNER JOIN feed_follows ON posts.feed_id = feed_follows.feed_id
+WHERE feed_follows.user_id = $1 AND posts.seq > $2::BIGINT
+ORDER BY posts.seq
+LIMIT $3::INTEGER
+`
+
+type GetFeverItemsAfterParams struct {
+	UserID   uuid.UUID
+	AfterSeq int64
+	MaxItems int32
+}
+
+type GetFeverItemsAfterRow struct {
+	ID          uuid.UUID
+	Seq         int64
+	FeedSeq     int64
+	Title       sql.NullString
+	Url         string
+	Description sql.NullString
+	PublishedAt sql.NullTime
+	CreatedAt   time.Time
+	FeedID      uuid.UUID
+	Author      sql.NullString
+	Categories  sql.NullString
+	IsRead      bool
+	IsSaved     bool
+}
+
+// The oldest items after after_seq, for clients syncing with since_id.
+func (q *Queries) GetFeverItemsAfter(ctx context.Context, arg GetFeverItemsAfterParams) ([]GetFeverItemsAfterRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFeverItemsAfter, arg.UserID, arg.AfterSeq, arg.MaxItems)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFeverItemsAfterRow
+	for rows.Next() {
+		var i GetFeverItemsAfterRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Seq,
+			&i.FeedSeq,
+			&i.Title,
+			&i.Url,
+			&i.Description,
+			&i.PublishedAt,
+			&i.CreatedAt,
+			&i.FeedID,
+			&i.Author,
+			&i.Categories,
+			&i.IsRead,
+			&i.IsSaved,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFeverItemsBefore = `-- name: GetFeverItemsBefore :many
+SELECT
+    posts.id,
+    posts.seq,
+    feeds.seq AS feed_seq,
+    posts.title,
+    posts.url,
+    posts.description,
+    posts.published_at,
+    posts.created_at,
+    posts.feed_id,
+    posts.author,
+    posts.categories,
+    EXISTS (
+        SELECT 1 FROM read_posts
+        WHERE read_posts.post_id = posts.id AND read_posts.user_id = feed_follows.user_id
+    ) AS is_read,
+    EXISTS (
+        SELECT 1 FROM saved_posts
+        WHERE saved_posts.post_id = posts.id AND saved_posts.user_id = feed_follows.user_id
+    ) AS is_saved
+FROM posts
+INNER JOIN feeds ON posts.feed_id = feeds.id
+INNER JOIN feed_follows ON posts.feed_id = feed_follows.feed_id
+WHERE feed_follows.user_id = $1 AND posts.seq < $2::BIGINT
+ORDER BY posts.seq DESC
+LIMIT $3::INTEGER
+`
+
+type GetFeverItemsBeforeParams struct {
+	UserID    uuid.UUID
+	BeforeSeq int64
+	MaxItems  int32
+}
+
+type GetFeverItemsBeforeRow struct {
+	ID          uuid.UUID
+	Seq         int64
+	FeedSeq     int64
+	Title       sql.NullString
+	Url         string
+	Description sql.NullString
+	PublishedAt sql.NullTime
+	CreatedAt   time.Time
+	FeedID      uuid.UUID
+	Author      sql.NullString
+	Categories  sql.NullString
+	IsRead      bool
+	IsSaved     bool
+}
+
+// The newest items before before_seq, for clients paging back with max_id.
+func (q *Queries) GetFeverItemsBefore(ctx context.Context, arg GetFeverItemsBeforeParams) ([]GetFeverItemsBeforeRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFeverItemsBefore, arg.UserID, arg.BeforeSeq, arg.MaxItems)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFeverItemsBeforeRow
+	for rows.Next() {
+		var i GetFeverItemsBeforeRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Seq,
+			&i.FeedSeq,
+			&i.Title,
+			&i.Url,
+			&i.Description,
+			&i.PublishedAt,
+			&i.CreatedAt,
+			&i.FeedID,
+			&i.Author,
+			&i.Categories,
+			&i.IsRead,
+			&i.IsSaved,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markFeverPostsRead = `-- name: MarkFeverPostsRead :exec
+INSERT INTO read_posts (user_id, post_id, read_at)
+SELECT feed_follows.user_id, posts.id, now()
+FROM posts
+INNER JOIN feeds ON posts.feed_id = feeds.id
+INNER JOIN feed_follows ON posts.feed_id = feed_follows.feed_id
+WHERE
+    feed_follows.user_id = $1
+    AND ($2::BIGINT IS NULL OR feeds.seq = $2::BIGINT)
+    AND posts.created_at <= $3
+ON CONFLICT (user_id, post_id) DO NOTHING
+`
+
+type MarkFeverPostsReadParams struct {
+	UserID      uuid.UUID
+	FeedSeq     sql.NullInt64
+	AddedBefore time.Time
+}
+
+func (q *Queries) MarkFeverPostsRead(ctx context.Context, arg MarkFeverPostsReadParams) error {
+	_, err := q.db.ExecContext(ctx, markFeverPostsRead, arg.UserID, arg.FeedSeq, arg.AddedBefore)
+	return err
+}
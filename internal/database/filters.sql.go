@@ -0,0 +1,120 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: filters.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createFilterRule = `-- name: CreateFilterRule :one
+INSERT INTO filter_rules (id, user_id, feed_id, action, kind, pattern, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, now())
+RETURNING id, user_id, feed_id, action, kind, pattern, created_at
+`
+
+type CreateFilterRuleParams struct {
+	ID      uuid.UUID
+	UserID  uuid.UUID
+	FeedID  uuid.NullUUID
+	Action  string
+	Kind    string
+	Pattern string
+}
+
+func (q *Queries) CreateFilterRule(ctx context.Context, arg CreateFilterRuleParams) (FilterRule, error) {
+	row := q.db.QueryRowContext(ctx, createFilterRule,
+		arg.ID,
+		arg.UserID,
+		arg.FeedID,
+		arg.Action,
+		arg.Kind,
+		arg.Pattern,
+	)
+	var i FilterRule
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.FeedID,
+		&i.Action,
+		&i.Kind,
+		&i.Pattern,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteFilterRule = `-- name: DeleteFilterRule :execrows
+DELETE FROM filter_rules
+WHERE id = $1 AND user_id = $2
+`
+
+type DeleteFilterRuleParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) DeleteFilterRule(ctx context.Context, arg DeleteFilterRuleParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteFilterRule, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getFilterRulesForUser = `-- name: GetFilterRulesForUser :many
+SELECT filter_rules.id, filter_rules.user_id, filter_rules.feed_id, filter_rules.action, filter_rules.kind, filter_rules.pattern, filter_rules.created_at, feeds.name AS feed_name
+FROM filter_rules
+LEFT JOIN feeds ON filter_rules.feed_id = feeds.id
+WHERE filter_rules.user_id = $1
+ORDER BY filter_rules.created_at
+`
+
+type GetFilterRulesForUserRow struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	FeedID    uuid.NullUUID
+	Action    string
+	Kind      string
+	Pattern   string
+	CreatedAt time.Time
+	FeedName  sql.NullString
+}
+
+func (q *Queries) GetFilterRulesForUser(ctx context.Context, userID uuid.UUID) ([]GetFilterRulesForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFilterRulesForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFilterRulesForUserRow
+	for rows.Next() {
+		var i GetFilterRulesForUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.FeedID,
+			&i.Action,
+			&i.Kind,
+			&i.Pattern,
+			&i.CreatedAt,
+			&i.FeedName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -7,20 +7,39 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+const countOtherFeedFollowers = `-- name: CountOtherFeedFollowers :one
+SELECT count(*)
+FROM feed_follows
+WHERE feed_id = $1 AND user_id <> $2
+`
+
+type CountOtherFeedFollowersParams struct {
+	FeedID uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) CountOtherFeedFollowers(ctx context.Context, arg CountOtherFeedFollowersParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countOtherFeedFollowers, arg.FeedID, arg.UserID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createFeedFollow = `-- name: CreateFeedFollow :one
 WITH inserted_feed_follow AS (
     INSERT INTO feed_follows (id, created_at, updated_at, user_id, feed_id)
     VALUES ($1, now(), now(), $2, $3)
-    RETURNING id, created_at, updated_at, user_id, feed_id
+    RETURNING id, created_at, updated_at, user_id, feed_id, category_id, title
 )
 
 SELECT
-    inserted_feed_follow.id, inserted_feed_follow.created_at, inserted_feed_follow.updated_at, inserted_feed_follow.user_id, inserted_feed_follow.feed_id,
+    inserted_feed_follow.id, inserted_feed_follow.created_at, inserted_feed_follow.updated_at, inserted_feed_follow.user_id, inserted_feed_follow.feed_id, inserted_feed_follow.category_id, inserted_feed_follow.title,
     feeds.name AS feed_name,
     users.name AS user_name
 FROM inserted_feed_follow
@@ -35,13 +54,15 @@ type CreateFeedFollowParams struct {
 }
 
 type CreateFeedFollowRow struct {
-	ID        uuid.UUID
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	UserID    uuid.UUID
-	FeedID    uuid.UUID
-	FeedName  string
-	UserName  string
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	UserID     uuid.UUID
+	FeedID     uuid.UUID
+	CategoryID uuid.NullUUID
+	Title      sql.NullString
+	FeedName   string
+	UserName   string
 }
 
 func (q *Queries) CreateFeedFollow(ctx context.Context, arg CreateFeedFollowParams) (CreateFeedFollowRow, error) {
@@ -53,6 +74,8 @@ func (q *Queries) CreateFeedFollow(ctx context.Context, arg CreateFeedFollowPara
 		&i.UpdatedAt,
 		&i.UserID,
 		&i.FeedID,
+		&i.CategoryID,
+		&i.Title,
 		&i.FeedName,
 		&i.UserName,
 	)
@@ -61,23 +84,28 @@ func (q *Queries) CreateFeedFollow(ctx context.Context, arg CreateFeedFollowPara
 
 const getFeedFollowsForUser = `-- name: GetFeedFollowsForUser :many
 SELECT
-    feed_follows.id, feed_follows.created_at, feed_follows.updated_at, feed_follows.user_id, feed_follows.feed_id,
-    feeds.name AS feed_name,
-    users.name AS user_name
+    feed_follows.id, feed_follows.created_at, feed_follows.updated_at, feed_follows.user_id, feed_follows.feed_id, feed_follows.category_id, feed_follows.title,
+    COALESCE(feed_follows.title, feeds.name) AS feed_name,
+    users.name AS user_name,
+    categories.name AS category_name
 FROM feed_follows
 INNER JOIN feeds ON feed_follows.feed_id = feeds.id
 INNER JOIN users ON feed_follows.user_id = users.id
+LEFT JOIN categories ON feed_follows.category_id = categories.id
 WHERE feed_follows.user_id = $1
 `
 
 type GetFeedFollowsForUserRow struct {
-	ID        uuid.UUID
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	UserID    uuid.UUID
-	FeedID    uuid.UUID
-	FeedName  string
-	UserName  string
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	UserID       uuid.UUID
+	FeedID       uuid.UUID
+	CategoryID   uuid.NullUUID
+	Title        sql.NullString
+	FeedName     string
+	UserName     string
+	CategoryName sql.NullString
 }
 
 func (q *Queries) GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) ([]GetFeedFollowsForUserRow, error) {
@@ -95,8 +123,11 @@ func (q *Queries) GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) (
 			&i.UpdatedAt,
 			&i.UserID,
 			&i.FeedID,
+			&i.CategoryID,
+			&i.Title,
 			&i.FeedName,
 			&i.UserName,
+			&i.CategoryName,
 		); err != nil {
 			return nil, err
 		}
@@ -111,10 +142,31 @@ func (q *Queries) GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) (
 	return items, nil
 }
 
+const setFollowTitle = `-- name: SetFollowTitle :execrows
+UPDATE feed_follows
+SET title = $1, updated_at = now()
+WHERE user_id = $2 AND feed_id = $3
+`
+
+type SetFollowTitleParams struct {
+	Title  sql.NullString
+	UserID uuid.UUID
+	FeedID uuid.UUID
+}
+
+// title NULL shows the feed under its own name again.
+func (q *Queries) SetFollowTitle(ctx context.Context, arg SetFollowTitleParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setFollowTitle, arg.Title, arg.UserID, arg.FeedID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const unfollow = `-- name: Unfollow :one
 DELETE FROM feed_follows
 WHERE user_id = $1 AND feed_id = $2
-RETURNING id, created_at, updated_at, user_id, feed_id
+RETURNING id, created_at, updated_at, user_id, feed_id, category_id, title
 `
 
 type UnfollowParams struct {
@@ -131,6 +183,8 @@ func (q *Queries) Unfollow(ctx context.Context, arg UnfollowParams) (FeedFollow,
 		&i.UpdatedAt,
 		&i.UserID,
 		&i.FeedID,
+		&i.CategoryID,
+		&i.Title,
 	)
 	return i, err
 }
@@ -8,53 +8,218 @@ package database
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
-const createPost = `-- name: CreatePost :one
+const createPosts = `-- name: CreatePosts :many
 INSERT INTO posts (
-    id, created_at, updated_at, title, url, description, published_at, feed_id
+    id, created_at, updated_at, title, url, description, published_at, feed_id,
+    author, categories
+)
+SELECT
+    new_posts.id,
+    now(),
+    now(),
+    new_posts.title,
+    new_posts.url,
+    new_posts.description,
+    new_posts.published_at,
+    $1,
+    new_posts.author,
+    new_posts.categories
+FROM (
+    SELECT
+        unnest($2::UUID []) AS id,
+        nullif(unnest($3::TEXT []), '') AS title,
+        unnest($4::TEXT []) AS url,
+        nullif(unnest($5::TEXT []), '') AS description,
+        unnest($6::TIMESTAMP []) AS published_at,
+        nullif(unnest($7::TEXT []), '') AS author,
+        nullif(unnest($8::TEXT []), '') AS categories
+) AS new_posts
+WHERE NOT EXISTS (
+    SELECT 1 FROM pruned_posts
+    WHERE pruned_posts.url = new_posts.url
 )
-VALUES ($1, now(), now(), $2, $3, $4, $5, $6)
 ON CONFLICT (url) DO NOTHING
-RETURNING id, created_at, updated_at, title, url, description, published_at, feed_id
+RETURNING id
+`
+
+type CreatePostsParams struct {
+	FeedID       uuid.UUID
+	Ids          []uuid.UUID
+	Titles       []string
+	Urls         []string
+	Descriptions []string
+	PublishedAts []time.Time
+	Authors      []string
+	Categories   []string
+}
+
+func (q *Queries) CreatePosts(ctx context.Context, arg CreatePostsParams) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, createPosts,
+		arg.FeedID,
+		pq.Array(arg.Ids),
+		pq.Array(arg.Titles),
+		pq.Array(arg.Urls),
+		pq.Array(arg.Descriptions),
+		pq.Array(arg.PublishedAts),
+		pq.Array(arg.Authors),
+		pq.Array(arg.Categories),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const expirePrunedPosts = `-- name: ExpirePrunedPosts :execrows
+DELETE FROM pruned_posts
+WHERE coalesce(seen_at, pruned_at) < (
+    SELECT max(feed_fetches.fetched_at) FROM feed_fetches
+    WHERE feed_fetches.feed_id = pruned_posts.feed_id
+)
+`
+
+func (q *Queries) ExpirePrunedPosts(ctx context.Context) (int64, error) {
+	result, err := q.db.ExecContext(ctx, expirePrunedPosts)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getPostByID = `-- name: GetPostByID :one
+SELECT id, created_at, updated_at, title, url, description, published_at, feed_id, seq, author, categories FROM posts
+WHERE id = $1
+`
+
+func (q *Queries) GetPostByID(ctx context.Context, id uuid.UUID) (Post, error) {
+	row := q.db.QueryRowContext(ctx, getPostByID, id)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Title,
+		&i.Url,
+		&i.Description,
+		&i.PublishedAt,
+		&i.FeedID,
+		&i.Seq,
+		&i.Author,
+		&i.Categories,
+	)
+	return i, err
+}
+
+const getPostByURL = `-- name: GetPostByURL :one
+SELECT id, created_at, updated_at, title, url, description, published_at, feed_id, seq, author, categories FROM posts
+WHERE url = $1
+`
+
+func (q *Queries) GetPostByURL(ctx context.Context, url string) (Post, error) {
+	row := q.db.QueryRowContext(ctx, getPostByURL, url)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Title,
+		&i.Url,
+		&i.Description,
+		&i.PublishedAt,
+		&i.FeedID,
+		&i.Seq,
+		&i.Author,
+		&i.Categories,
+	)
+	return i, err
+}
+
+const getPostForUser = `-- name: GetPostForUser :one
+SELECT
+    posts.id,
+    posts.title,
+    posts.url,
+    posts.description,
+    posts.published_at,
+    posts.author,
+    posts.categories,
+    posts.feed_id,
+    COALESCE(feed_follows.title, feeds.name) AS feed_name,
+    EXISTS (
+        SELECT 1 FROM read_posts
+        WHERE read_posts.post_id = posts.id AND read_posts.user_id = feed_follows.user_id
+    ) AS is_read,
+    EXISTS (
+        SELECT 1 FROM saved_posts
+        WHERE saved_posts.post_id = posts.id AND saved_posts.user_id = feed_follows.user_id
+    ) AS is_saved
+FROM posts
+INNER JOIN feeds ON posts.feed_id = feeds.id
+INNER JOIN feed_follows ON posts.feed_id = feed_follows.feed_id
+WHERE posts.id = $1 AND feed_follows.user_id = $2
 `
 
-type CreatePostParams struct {
+type GetPostForUserParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+type GetPostForUserRow struct {
 	ID          uuid.UUID
 	Title       sql.NullString
 	Url         string
 	Description sql.NullString
 	PublishedAt sql.NullTime
+	Author      sql.NullString
+	Categories  sql.NullString
 	FeedID      uuid.UUID
+	FeedName    string
+	IsRead      bool
+	IsSaved     bool
 }
 
-func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, error) {
-	row := q.db.QueryRowContext(ctx, createPost,
-		arg.ID,
-		arg.Title,
-		arg.Url,
-		arg.Description,
-		arg.PublishedAt,
-		arg.FeedID,
-	)
-	var i Post
+func (q *Queries) GetPostForUser(ctx context.Context, arg GetPostForUserParams) (GetPostForUserRow, error) {
+	row := q.db.QueryRowContext(ctx, getPostForUser, arg.ID, arg.UserID)
+	var i GetPostForUserRow
 	err := row.Scan(
 		&i.ID,
-		&i.CreatedAt,
-		&i.UpdatedAt,
 		&i.Title,
 		&i.Url,
 		&i.Description,
 		&i.PublishedAt,
+		&i.Author,
+		&i.Categories,
 		&i.FeedID,
+		&i.FeedName,
+		&i.IsRead,
+		&i.IsSaved,
 	)
 	return i, err
 }
 
 const getPostsByUser = `-- name: GetPostsByUser :many
-SELECT posts.title, posts.url, posts.description, posts.published_at
+SELECT posts.title, posts.url, posts.description, posts.published_at, posts.feed_id, posts.author, posts.categories
 FROM posts
 INNER JOIN feeds
     ON feeds.id = posts.feed_id
@@ -64,12 +229,13 @@ INNER JOIN feed_follows
     ON feed_follows.feed_id = posts.feed_id
 WHERE feed_follows.user_id = $1
 ORDER BY published_at DESC NULLS LAST
-LIMIT $2
+LIMIT $2 OFFSET $3
 `
 
 type GetPostsByUserParams struct {
 	UserID uuid.UUID
 	Limit  int32
+	Offset int32
 }
 
 type GetPostsByUserRow struct {
@@ -77,10 +243,13 @@ type GetPostsByUserRow struct {
 	Url         string
 	Description sql.NullString
 	PublishedAt sql.NullTime
+	FeedID      uuid.UUID
+	Author      sql.NullString
+	Categories  sql.NullString
 }
 
 func (q *Queries) GetPostsByUser(ctx context.Context, arg GetPostsByUserParams) ([]GetPostsByUserRow, error) {
-	rows, err := q.db.QueryContext(ctx, getPostsByUser, arg.UserID, arg.Limit)
+	rows, err := q.db.QueryContext(ctx, getPostsByUser, arg.UserID, arg.Limit, arg.Offset)
 	if err != nil {
 		return nil, err
 	}
@@ -93,6 +262,236 @@ func (q *Queries) GetPostsByUser(ctx context.Context, arg GetPostsByUserParams)
 			&i.Url,
 			&i.Description,
 			&i.PublishedAt,
+			&i.FeedID,
+			&i.Author,
+			&i.Categories,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPostsForUser = `-- name: GetPostsForUser :many
+SELECT
+    posts.id,
+    posts.title,
+    posts.url,
+    posts.description,
+    posts.published_at,
+    posts.author,
+    posts.categories,
+    posts.feed_id,
+    COALESCE(feed_follows.title, feeds.name) AS feed_name,
+    EXISTS (
+        SELECT 1 FROM read_posts
+        WHERE read_posts.post_id = posts.id AND read_posts.user_id = feed_follows.user_id
+    ) AS is_read,
+    EXISTS (
+        SELECT 1 FROM saved_posts
+        WHERE saved_posts.post_id = posts.id AND saved_posts.user_id = feed_follows.user_id
+    ) AS is_saved
+FROM posts
+INNER JOIN feeds ON posts.feed_id = feeds.id
+INNER JOIN feed_follows ON posts.feed_id = feed_follows.feed_id
+WHERE
+    feed_follows.user_id = $1
+    AND (
+        NOT $2::BOOLEAN
+        OR NOT EXISTS (
+            SELECT 1 FROM read_posts
+            WHERE read_posts.post_id = posts.id AND read_posts.user_id = feed_follows.user_id
+        )
+    )
+    AND ($3::UUID IS NULL OR posts.feed_id = $3::UUID)
+    AND ($4::UUID IS NULL OR feed_follows.category_id = $4::UUID)
+ORDER BY posts.published_at DESC NULLS LAST, posts.id
+LIMIT $6::INTEGER OFFSET $5::INTEGER
+`
+
+type GetPostsForUserParams struct {
+	UserID     uuid.UUID
+	UnreadOnly bool
+	FeedID     uuid.NullUUID
+	CategoryID uuid.NullUUID
+	PageOffset int32
+	PageSize   int32
+}
+
+type GetPostsForUserRow struct {
+	ID          uuid.UUID
+	Title       sql.NullString
+	Url         string
+	Description sql.NullString
+	PublishedAt sql.NullTime
+	Author      sql.NullString
+	Categories  sql.NullString
+	FeedID      uuid.UUID
+	FeedName    string
+	IsRead      bool
+	IsSaved     bool
+}
+
+func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams) ([]GetPostsForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPostsForUser,
+		arg.UserID,
+		arg.UnreadOnly,
+		arg.FeedID,
+		arg.CategoryID,
+		arg.PageOffset,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPostsForUserRow
+	for rows.Next() {
+		var i GetPostsForUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Url,
+			&i.Description,
+			&i.PublishedAt,
+			&i.Author,
+			&i.Categories,
+			&i.FeedID,
+			&i.FeedName,
+			&i.IsRead,
+			&i.IsSaved,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markPostRead = `-- name: MarkPostRead :exec
+INSERT INTO read_posts (user_id, post_id, read_at)
+VALUES ($1, $2, now())
+ON CONFLICT (user_id, post_id) DO NOTHING
+`
+
+type MarkPostReadParams struct {
+	UserID uuid.UUID
+	PostID uuid.UUID
+}
+
+func (q *Queries) MarkPostRead(ctx context.Context, arg MarkPostReadParams) error {
+	_, err := q.db.ExecContext(ctx, markPostRead, arg.UserID, arg.PostID)
+	return err
+}
+
+const markPostUnread = `-- name: MarkPostUnread :exec
+DELETE FROM read_posts
+WHERE user_id = $1 AND post_id = $2
+`
+
+type MarkPostUnreadParams struct {
+	UserID uuid.UUID
+	PostID uuid.UUID
+}
+
+func (q *Queries) MarkPostUnread(ctx context.Context, arg MarkPostUnreadParams) error {
+	_, err := q.db.ExecContext(ctx, markPostUnread, arg.UserID, arg.PostID)
+	return err
+}
+
+const markPrunedPostsSeen = `-- name: MarkPrunedPostsSeen :exec
+UPDATE pruned_posts SET seen_at = now()
+WHERE feed_id = $1 AND url = ANY($2::TEXT [])
+`
+
+type MarkPrunedPostsSeenParams struct {
+	FeedID uuid.UUID
+	Urls   []string
+}
+
+func (q *Queries) MarkPrunedPostsSeen(ctx context.Context, arg MarkPrunedPostsSeenParams) error {
+	_, err := q.db.ExecContext(ctx, markPrunedPostsSeen, arg.FeedID, pq.Array(arg.Urls))
+	return err
+}
+
+const prunePostsBeyondNewest = `-- name: PrunePostsBeyondNewest :many
+WITH deleted AS (
+    DELETE FROM posts
+    WHERE
+        posts.id IN (
+            SELECT ranked.id
+            FROM (
+                SELECT
+                    feed_posts.id,
+                    row_number() OVER (
+                        PARTITION BY feed_posts.feed_id
+                        ORDER BY
+                            feed_posts.published_at DESC NULLS LAST,
+                            feed_posts.created_at DESC
+                    ) AS position
+                FROM posts AS feed_posts
+            ) AS ranked
+            WHERE ranked.position > $1::BIGINT
+        )
+        AND NOT EXISTS (
+            SELECT 1 FROM saved_posts
+            WHERE saved_posts.post_id = posts.id
+        )
+    RETURNING posts.feed_id, posts.url
+),
+
+tombstones AS (
+    INSERT INTO pruned_posts (url, feed_id, pruned_at)
+    SELECT deleted.url, deleted.feed_id, now()
+    FROM deleted
+    ON CONFLICT (url) DO NOTHING
+)
+
+SELECT
+    feeds.id AS feed_id,
+    feeds.name,
+    feeds.url,
+    count(*) AS removed
+FROM deleted
+INNER JOIN feeds ON deleted.feed_id = feeds.id
+GROUP BY feeds.id
+ORDER BY feeds.name, feeds.url
+`
+
+type PrunePostsBeyondNewestRow struct {
+	FeedID  uuid.UUID
+	Name    string
+	Url     string
+	Removed int64
+}
+
+func (q *Queries) PrunePostsBeyondNewest(ctx context.Context, keep int64) ([]PrunePostsBeyondNewestRow, error) {
+	rows, err := q.db.QueryContext(ctx, prunePostsBeyondNewest, keep)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PrunePostsBeyondNewestRow
+	for rows.Next() {
+		var i PrunePostsBeyondNewestRow
+		if err := rows.Scan(
+			&i.FeedID,
+			&i.Name,
+			&i.Url,
+			&i.Removed,
 		); err != nil {
 			return nil, err
 		}
@@ -106,3 +505,102 @@ func (q *Queries) GetPostsByUser(ctx context.Context, arg GetPostsByUserParams)
 	}
 	return items, nil
 }
+
+const prunePostsOlderThan = `-- name: PrunePostsOlderThan :many
+WITH deleted AS (
+    DELETE FROM posts
+    WHERE
+        coalesce(posts.published_at, posts.created_at) < $1::TIMESTAMP
+        AND NOT EXISTS (
+            SELECT 1 FROM saved_posts
+            WHERE saved_posts.post_id = posts.id
+        )
+    RETURNING posts.feed_id, posts.url
+),
+
+tombstones AS (
+    INSERT INTO pruned_posts (url, feed_id, pruned_at)
+    SELECT deleted.url, deleted.feed_id, now()
+    FROM deleted
+    ON CONFLICT (url) DO NOTHING
+)
+
+SELECT
+    feeds.id AS feed_id,
+    feeds.name,
+    feeds.url,
+    count(*) AS removed
+FROM deleted
+INNER JOIN feeds ON deleted.feed_id = feeds.id
+GROUP BY feeds.id
+ORDER BY feeds.name, feeds.url
+`
+
+type PrunePostsOlderThanRow struct {
+	FeedID  uuid.UUID
+	Name    string
+	Url     string
+	Removed int64
+}
+
+func (q *Queries) PrunePostsOlderThan(ctx context.Context, cutoff time.Time) ([]PrunePostsOlderThanRow, error) {
+	rows, err := q.db.QueryContext(ctx, prunePostsOlderThan, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PrunePostsOlderThanRow
+	for rows.Next() {
+		var i PrunePostsOlderThanRow
+		if err := rows.Scan(
+			&i.FeedID,
+			&i.Name,
+			&i.Url,
+			&i.Removed,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const savePost = `-- name: SavePost :exec
+INSERT INTO saved_posts (user_id, post_id, created_at)
+VALUES ($1, $2, now())
+ON CONFLICT (user_id, post_id) DO NOTHING
+`
+
+type SavePostParams struct {
+	UserID uuid.UUID
+	PostID uuid.UUID
+}
+
+func (q *Queries) SavePost(ctx context.Context, arg SavePostParams) error {
+	_, err := q.db.ExecContext(ctx, savePost, arg.UserID, arg.PostID)
+	return err
+}
+
+const unsavePost = `-- name: UnsavePost :execrows
+DELETE FROM saved_posts
+WHERE user_id = $1 AND post_id = $2
+`
+
+type UnsavePostParams struct {
+	UserID uuid.UUID
+	PostID uuid.UUID
+}
+
+func (q *Queries) UnsavePost(ctx context.Context, arg UnsavePostParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, unsavePost, arg.UserID, arg.PostID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
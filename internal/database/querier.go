@@ -0,0 +1,104 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Querier interface {
+	ClearFeedCredentials(ctx context.Context, feedID uuid.UUID) error
+	ClearFeedRedirect(ctx context.Context, id uuid.UUID) error
+	CountAdmins(ctx context.Context) (int64, error)
+	CountOtherFeedFollowers(ctx context.Context, arg CountOtherFeedFollowersParams) (int64, error)
+	CreateCategory(ctx context.Context, arg CreateCategoryParams) (Category, error)
+	CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, error)
+	CreateFeedFetch(ctx context.Context, arg CreateFeedFetchParams) error
+	CreateFeedFollow(ctx context.Context, arg CreateFeedFollowParams) (CreateFeedFollowRow, error)
+	CreateFeedHistory(ctx context.Context, arg CreateFeedHistoryParams) error
+	CreateFilterRule(ctx context.Context, arg CreateFilterRuleParams) (FilterRule, error)
+	CreateNonAdminUser(ctx context.Context, arg CreateNonAdminUserParams) (User, error)
+	CreateNotificationDelivery(ctx context.Context, arg CreateNotificationDeliveryParams) error
+	CreateNotificationRule(ctx context.Context, arg CreateNotificationRuleParams) (NotificationRule, error)
+	CreatePosts(ctx context.Context, arg CreatePostsParams) ([]uuid.UUID, error)
+	CreateSession(ctx context.Context, arg CreateSessionParams) error
+	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	DeleteCategory(ctx context.Context, arg DeleteCategoryParams) (int64, error)
+	DeleteFeed(ctx context.Context, id uuid.UUID) error
+	DeleteFeedAuthorization(ctx context.Context, feedID uuid.UUID) error
+	DeleteFilterRule(ctx context.Context, arg DeleteFilterRuleParams) (int64, error)
+	DeleteNotificationRule(ctx context.Context, arg DeleteNotificationRuleParams) (int64, error)
+	DeleteOtherSessions(ctx context.Context, arg DeleteOtherSessionsParams) error
+	DeleteSession(ctx context.Context, tokenHash string) error
+	DeleteUser(ctx context.Context, id uuid.UUID) error
+	ExpirePrunedPosts(ctx context.Context) (int64, error)
+	GetAllFeeds(ctx context.Context) ([]Feed, error)
+	GetCategoriesForUser(ctx context.Context, userID uuid.UUID) ([]GetCategoriesForUserRow, error)
+	GetCategoryByName(ctx context.Context, arg GetCategoryByNameParams) (Category, error)
+	// The posts added to the user's follows in (added_after, added_before] after
+	// the post with seq after_seq, in the order they were added.
+	GetDigestPosts(ctx context.Context, arg GetDigestPostsParams) ([]GetDigestPostsRow, error)
+	GetDigestSubscribers(ctx context.Context) ([]User, error)
+	GetFeedBandwidth(ctx context.Context, fetchedAt time.Time) ([]GetFeedBandwidthRow, error)
+	GetFeedByURL(ctx context.Context, url string) (Feed, error)
+	GetFeedCredentials(ctx context.Context, feedID uuid.UUID) ([]FeedCredential, error)
+	GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) ([]GetFeedFollowsForUserRow, error)
+	GetFeedHistory(ctx context.Context, feedID uuid.UUID) ([]FeedHistory, error)
+	// Queries for the Fever API, which identifies feeds and posts by their seq.
+	GetFeverFeeds(ctx context.Context, userID uuid.UUID) ([]GetFeverFeedsRow, error)
+	GetFeverItemStates(ctx context.Context, userID uuid.UUID) ([]GetFeverItemStatesRow, error)
+	// The oldest items after after_seq, for clients syncing with since_id.
+	GetFeverItemsAfter(ctx context.Context, arg GetFeverItemsAfterParams) ([]GetFeverItemsAfterRow, error)
+	// The newest items before before_seq, for clients paging back with max_id.
+	GetFeverItemsBefore(ctx context.Context, arg GetFeverItemsBeforeParams) ([]GetFeverItemsBeforeRow, error)
+	GetFilterRulesForUser(ctx context.Context, userID uuid.UUID) ([]GetFilterRulesForUserRow, error)
+	GetNextFeedToFetch(ctx context.Context) (Feed, error)
+	GetNotificationDeliveries(ctx context.Context, arg GetNotificationDeliveriesParams) ([]GetNotificationDeliveriesRow, error)
+	// The rules of the feed's followers that cover it.
+	GetNotificationRulesForFeed(ctx context.Context, feedID uuid.UUID) ([]NotificationRule, error)
+	GetNotificationRulesForUser(ctx context.Context, userID uuid.UUID) ([]GetNotificationRulesForUserRow, error)
+	GetPostByID(ctx context.Context, id uuid.UUID) (Post, error)
+	GetPostByURL(ctx context.Context, url string) (Post, error)
+	GetPostForUser(ctx context.Context, arg GetPostForUserParams) (GetPostForUserRow, error)
+	GetPostsByUser(ctx context.Context, arg GetPostsByUserParams) ([]GetPostsByUserRow, error)
+	GetPostsForUser(ctx context.Context, arg GetPostsForUserParams) ([]GetPostsForUserRow, error)
+	GetUserByFeverKey(ctx context.Context, feverKeyHash sql.NullString) (User, error)
+	GetUserByID(ctx context.Context, id uuid.UUID) (User, error)
+	GetUserByName(ctx context.Context, name string) (User, error)
+	GetUserBySession(ctx context.Context, tokenHash string) (User, error)
+	GetUsers(ctx context.Context) ([]User, error)
+	MarkFeedDead(ctx context.Context, id uuid.UUID) error
+	MarkFeedFetched(ctx context.Context, id uuid.UUID) error
+	MarkFeverPostsRead(ctx context.Context, arg MarkFeverPostsReadParams) error
+	MarkPostRead(ctx context.Context, arg MarkPostReadParams) error
+	MarkPostUnread(ctx context.Context, arg MarkPostUnreadParams) error
+	MarkPrunedPostsSeen(ctx context.Context, arg MarkPrunedPostsSeenParams) error
+	PrunePostsBeyondNewest(ctx context.Context, keep int64) ([]PrunePostsBeyondNewestRow, error)
+	PrunePostsOlderThan(ctx context.Context, cutoff time.Time) ([]PrunePostsOlderThanRow, error)
+	RecordFeedRedirect(ctx context.Context, arg RecordFeedRedirectParams) (int32, error)
+	RenameCategory(ctx context.Context, arg RenameCategoryParams) (int64, error)
+	RenameFeed(ctx context.Context, arg RenameFeedParams) (Feed, error)
+	ResetUsers(ctx context.Context) error
+	SavePost(ctx context.Context, arg SavePostParams) error
+	SetFeedCredential(ctx context.Context, arg SetFeedCredentialParams) error
+	// category_id NULL takes the feed out of its category.
+	SetFollowCategory(ctx context.Context, arg SetFollowCategoryParams) (int64, error)
+	// title NULL shows the feed under its own name again.
+	SetFollowTitle(ctx context.Context, arg SetFollowTitleParams) (int64, error)
+	SetUserAdmin(ctx context.Context, arg SetUserAdminParams) error
+	SetUserDigest(ctx context.Context, arg SetUserDigestParams) error
+	SetUserFeverKey(ctx context.Context, arg SetUserFeverKeyParams) error
+	SetUserLastDigest(ctx context.Context, arg SetUserLastDigestParams) error
+	SetUserPassword(ctx context.Context, arg SetUserPasswordParams) error
+	Unfollow(ctx context.Context, arg UnfollowParams) (FeedFollow, error)
+	UnsavePost(ctx context.Context, arg UnsavePostParams) (int64, error)
+	UpdateFeedURL(ctx context.Context, arg UpdateFeedURLParams) (Feed, error)
+}
+
+var _ Querier = (*Queries)(nil)
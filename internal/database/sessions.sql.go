@@ -0,0 +1,78 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: sessions.sql
+
+package database
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const createSession = `-- name: CreateSession :exec
+INSERT INTO sessions (token_hash, user_id, created_at)
+VALUES ($1, $2, now())
+`
+
+type CreateSessionParams struct {
+	TokenHash string
+	UserID    uuid.UUID
+}
+
+func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) error {
+	_, err := q.db.ExecContext(ctx, createSession, arg.TokenHash, arg.UserID)
+	return err
+}
+
+const deleteOtherSessions = `-- name: DeleteOtherSessions :exec
+DELETE FROM sessions
+WHERE user_id = $1 AND token_hash <> $2
+`
+
+type DeleteOtherSessionsParams struct {
+	UserID    uuid.UUID
+	TokenHash string
+}
+
+func (q *Queries) DeleteOtherSessions(ctx context.Context, arg DeleteOtherSessionsParams) error {
+	_, err := q.db.ExecContext(ctx, deleteOtherSessions, arg.UserID, arg.TokenHash)
+	return err
+}
+
+const deleteSession = `-- name: DeleteSession :exec
+DELETE FROM sessions
+WHERE token_hash = $1
+`
+
+func (q *Queries) DeleteSession(ctx context.Context, tokenHash string) error {
+	_, err := q.db.ExecContext(ctx, deleteSession, tokenHash)
+	return err
+}
+
+const getUserBySession = `-- name: GetUserBySession :one
+SELECT users.id, users.created_at, users.updated_at, users.name, users.password_hash, users.is_admin, users.fever_key_hash, users.email, users.digest_frequency, users.last_digest_at, users.last_digest_seq
+FROM sessions
+INNER JOIN users ON sessions.user_id = users.id
+WHERE sessions.token_hash = $1
+`
+
+func (q *Queries) GetUserBySession(ctx context.Context, tokenHash string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserBySession, tokenHash)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.PasswordHash,
+		&i.IsAdmin,
+		&i.FeverKeyHash,
+		&i.Email,
+		&i.DigestFrequency,
+		&i.LastDigestAt,
+		&i.LastDigestSeq,
+	)
+	return i, err
+}
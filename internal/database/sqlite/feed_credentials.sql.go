@@ -0,0 +1,104 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: feed_credentials.sql
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const clearFeedCredentials = `-- name: ClearFeedCredentials :exec
+DELETE FROM feed_credentials
+WHERE feed_id = ?
+`
+
+func (q *Queries) ClearFeedCredentials(ctx context.Context, feedID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, clearFeedCredentials, feedID)
+	return err
+}
+
+const deleteFeedAuthorization = `-- name: DeleteFeedAuthorization :exec
+DELETE FROM feed_credentials
+WHERE feed_id = ? AND kind IN ('basic', 'bearer')
+`
+
+func (q *Queries) DeleteFeedAuthorization(ctx context.Context, feedID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteFeedAuthorization, feedID)
+	return err
+}
+
+const getFeedCredentials = `-- name: GetFeedCredentials :many
+SELECT id, created_at, updated_at, feed_id, kind, header_name, username, secret_ref FROM feed_credentials
+WHERE feed_id = ?
+ORDER BY kind, header_name
+`
+
+func (q *Queries) GetFeedCredentials(ctx context.Context, feedID uuid.UUID) ([]FeedCredential, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedCredentials, feedID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FeedCredential
+	for rows.Next() {
+		var i FeedCredential
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.FeedID,
+			&i.Kind,
+			&i.HeaderName,
+			&i.Username,
+			&i.SecretRef,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setFeedCredential = `-- name: SetFeedCredential :exec
+INSERT INTO feed_credentials (
+    id, created_at, updated_at, feed_id, kind, header_name, username, secret_ref
+)
+VALUES (?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP, ?, ?, ?, ?, ?)
+ON CONFLICT (feed_id, kind, header_name) DO UPDATE
+SET
+    username = excluded.username,
+    secret_ref = excluded.secret_ref,
+    updated_at = CURRENT_TIMESTAMP
+`
+
+type SetFeedCredentialParams struct {
+	ID         uuid.UUID
+	FeedID     uuid.UUID
+	Kind       string
+	HeaderName string
+	Username   sql.NullString
+	SecretRef  string
+}
+
+func (q *Queries) SetFeedCredential(ctx context.Context, arg SetFeedCredentialParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedCredential,
+		arg.ID,
+		arg.FeedID,
+		arg.Kind,
+		arg.HeaderName,
+		arg.Username,
+		arg.SecretRef,
+	)
+	return err
+}
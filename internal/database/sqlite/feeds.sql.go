@@ -0,0 +1,277 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: feeds.sql
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const clearFeedRedirect = `-- name: ClearFeedRedirect :exec
+UPDATE feeds
+SET redirect_url = NULL, redirect_count = 0
+WHERE id = ?
+`
+
+func (q *Queries) ClearFeedRedirect(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, clearFeedRedirect, id)
+	return err
+}
+
+const createFeed = `-- name: CreateFeed :one
+INSERT INTO feeds (id, name, url, user_id, created_at, updated_at, seq)
+VALUES (
+    ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP,
+    (SELECT COALESCE(MAX(seq), 0) + 1 FROM feeds)
+)
+RETURNING id, name, url, user_id, created_at, updated_at, last_fetched_at, redirect_url, redirect_count, dead_at, seq
+`
+
+type CreateFeedParams struct {
+	ID     uuid.UUID
+	Name   string
+	Url    string
+	UserID uuid.UUID
+}
+
+func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, createFeed,
+		arg.ID,
+		arg.Name,
+		arg.Url,
+		arg.UserID,
+	)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LastFetchedAt,
+		&i.RedirectUrl,
+		&i.RedirectCount,
+		&i.DeadAt,
+		&i.Seq,
+	)
+	return i, err
+}
+
+const deleteFeed = `-- name: DeleteFeed :exec
+DELETE FROM feeds
+WHERE id = ?
+`
+
+func (q *Queries) DeleteFeed(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteFeed, id)
+	return err
+}
+
+const getAllFeeds = `-- name: GetAllFeeds :many
+SELECT id, name, url, user_id, created_at, updated_at, last_fetched_at, redirect_url, redirect_count, dead_at, seq FROM feeds
+`
+
+func (q *Queries) GetAllFeeds(ctx context.Context) ([]Feed, error) {
+	rows, err := q.db.QueryContext(ctx, getAllFeeds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Feed
+	for rows.Next() {
+		var i Feed
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Url,
+			&i.UserID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LastFetchedAt,
+			&i.RedirectUrl,
+			&i.RedirectCount,
+			&i.DeadAt,
+			&i.Seq,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFeedByURL = `-- name: GetFeedByURL :one
+SELECT id, name, url, user_id, created_at, updated_at, last_fetched_at, redirect_url, redirect_count, dead_at, seq FROM feeds
+WHERE url = ?
+`
+
+func (q *Queries) GetFeedByURL(ctx context.Context, url string) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, getFeedByURL, url)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LastFetchedAt,
+		&i.RedirectUrl,
+		&i.RedirectCount,
+		&i.DeadAt,
+		&i.Seq,
+	)
+	return i, err
+}
+
+const getNextFeedToFetch = `-- name: GetNextFeedToFetch :one
+SELECT id, name, url, user_id, created_at, updated_at, last_fetched_at, redirect_url, redirect_count, dead_at, seq
+FROM feeds
+WHERE dead_at IS NULL
+ORDER BY last_fetched_at ASC NULLS FIRST
+LIMIT 1
+`
+
+func (q *Queries) GetNextFeedToFetch(ctx context.Context) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, getNextFeedToFetch)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LastFetchedAt,
+		&i.RedirectUrl,
+		&i.RedirectCount,
+		&i.DeadAt,
+		&i.Seq,
+	)
+	return i, err
+}
+
+const markFeedDead = `-- name: MarkFeedDead :exec
+UPDATE feeds
+SET updated_at = CURRENT_TIMESTAMP, dead_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+func (q *Queries) MarkFeedDead(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, markFeedDead, id)
+	return err
+}
+
+const markFeedFetched = `-- name: MarkFeedFetched :exec
+UPDATE feeds
+SET updated_at = CURRENT_TIMESTAMP, last_fetched_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+func (q *Queries) MarkFeedFetched(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, markFeedFetched, id)
+	return err
+}
+
+const recordFeedRedirect = `-- name: RecordFeedRedirect :one
+UPDATE feeds
+SET
+    redirect_count = CASE
+        WHEN redirect_url = ?1 THEN redirect_count + 1
+        ELSE 1
+    END,
+    redirect_url = ?1
+WHERE id = ?2
+RETURNING redirect_count
+`
+
+type RecordFeedRedirectParams struct {
+	RedirectUrl sql.NullString
+	ID          uuid.UUID
+}
+
+func (q *Queries) RecordFeedRedirect(ctx context.Context, arg RecordFeedRedirectParams) (int32, error) {
+	row := q.db.QueryRowContext(ctx, recordFeedRedirect, arg.RedirectUrl, arg.ID)
+	var redirect_count int32
+	err := row.Scan(&redirect_count)
+	return redirect_count, err
+}
+
+const renameFeed = `-- name: RenameFeed :one
+UPDATE feeds
+SET name = ?1, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?2
+RETURNING id, name, url, user_id, created_at, updated_at, last_fetched_at, redirect_url, redirect_count, dead_at, seq
+`
+
+type RenameFeedParams struct {
+	Name string
+	ID   uuid.UUID
+}
+
+func (q *Queries) RenameFeed(ctx context.Context, arg RenameFeedParams) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, renameFeed, arg.Name, arg.ID)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LastFetchedAt,
+		&i.RedirectUrl,
+		&i.RedirectCount,
+		&i.DeadAt,
+		&i.Seq,
+	)
+	return i, err
+}
+
+const updateFeedURL = `-- name: UpdateFeedURL :one
+UPDATE feeds
+SET
+    url = ?1,
+    redirect_url = NULL,
+    redirect_count = 0,
+    dead_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = ?2
+RETURNING id, name, url, user_id, created_at, updated_at, last_fetched_at, redirect_url, redirect_count, dead_at, seq
+`
+
+type UpdateFeedURLParams struct {
+	Url string
+	ID  uuid.UUID
+}
+
+func (q *Queries) UpdateFeedURL(ctx context.Context, arg UpdateFeedURLParams) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, updateFeedURL, arg.Url, arg.ID)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LastFetchedAt,
+		&i.RedirectUrl,
+		&i.RedirectCount,
+		&i.DeadAt,
+		&i.Seq,
+	)
+	return i, err
+}
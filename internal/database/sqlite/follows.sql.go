@@ -0,0 +1,206 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: follows.sql
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const countOtherFeedFollowers = `-- name: CountOtherFeedFollowers :one
+SELECT count(*)
+FROM feed_follows
+WHERE feed_id = ? AND user_id <> ?
+`
+
+type CountOtherFeedFollowersParams struct {
+	FeedID uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) CountOtherFeedFollowers(ctx context.Context, arg CountOtherFeedFollowersParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countOtherFeedFollowers, arg.FeedID, arg.UserID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createFeedFollow = `-- name: CreateFeedFollow :one
+INSERT INTO feed_follows (id, created_at, updated_at, user_id, feed_id)
+VALUES (?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP, ?, ?)
+RETURNING id, created_at, updated_at, user_id, feed_id, category_id, title
+`
+
+type CreateFeedFollowParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+	FeedID uuid.UUID
+}
+
+func (q *Queries) CreateFeedFollow(ctx context.Context, arg CreateFeedFollowParams) (FeedFollow, error) {
+	row := q.db.QueryRowContext(ctx, createFeedFollow, arg.ID, arg.UserID, arg.FeedID)
+	var i FeedFollow
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.FeedID,
+		&i.CategoryID,
+		&i.Title,
+	)
+	return i, err
+}
+
+const getFeedFollowByID = `-- name: GetFeedFollowByID :one
+SELECT
+    feed_follows.id, feed_follows.created_at, feed_follows.updated_at, feed_follows.user_id, feed_follows.feed_id, feed_follows.category_id, feed_follows.title,
+    feeds.name AS feed_name,
+    users.name AS user_name
+FROM feed_follows
+INNER JOIN feeds ON feed_follows.feed_id = feeds.id
+INNER JOIN users ON feed_follows.user_id = users.id
+WHERE feed_follows.id = ?
+`
+
+type GetFeedFollowByIDRow struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	UserID     uuid.UUID
+	FeedID     uuid.UUID
+	CategoryID uuid.NullUUID
+	Title      sql.NullString
+	FeedName   string
+	UserName   string
+}
+
+func (q *Queries) GetFeedFollowByID(ctx context.Context, id uuid.UUID) (GetFeedFollowByIDRow, error) {
+	row := q.db.QueryRowContext(ctx, getFeedFollowByID, id)
+	var i GetFeedFollowByIDRow
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.FeedID,
+		&i.CategoryID,
+		&i.Title,
+		&i.FeedName,
+		&i.UserName,
+	)
+	return i, err
+}
+
+const getFeedFollowsForUser = `-- name: GetFeedFollowsForUser :many
+SELECT
+    feed_follows.id, feed_follows.created_at, feed_follows.updated_at, feed_follows.user_id, feed_follows.feed_id, feed_follows.category_id, feed_follows.title,
+    COALESCE(feed_follows.title, feeds.name) AS feed_name,
+    users.name AS user_name,
+    categories.name AS category_name
+FROM feed_follows
+INNER JOIN feeds ON feed_follows.feed_id = feeds.id
+INNER JOIN users ON feed_follows.user_id = users.id
+LEFT JOIN categories ON feed_follows.category_id = categories.id
+WHERE feed_follows.user_id = ?
+`
+
+type GetFeedFollowsForUserRow struct {
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	UserID       uuid.UUID
+	FeedID       uuid.UUID
+	CategoryID   uuid.NullUUID
+	Title        sql.NullString
+	FeedName     string
+	UserName     string
+	CategoryName sql.NullString
+}
+
+func (q *Queries) GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) ([]GetFeedFollowsForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedFollowsForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFeedFollowsForUserRow
+	for rows.Next() {
+		var i GetFeedFollowsForUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.FeedID,
+			&i.CategoryID,
+			&i.Title,
+			&i.FeedName,
+			&i.UserName,
+			&i.CategoryName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setFollowTitle = `-- name: SetFollowTitle :execrows
+UPDATE feed_follows
+SET title = ?1, updated_at = CURRENT_TIMESTAMP
+WHERE user_id = ?2 AND feed_id = ?3
+`
+
+type SetFollowTitleParams struct {
+	Title  sql.NullString
+	UserID uuid.UUID
+	FeedID uuid.UUID
+}
+
+// title NULL shows the feed under its own name again.
+func (q *Queries) SetFollowTitle(ctx context.Context, arg SetFollowTitleParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setFollowTitle, arg.Title, arg.UserID, arg.FeedID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const unfollow = `-- name: Unfollow :one
+DELETE FROM feed_follows
+WHERE user_id = ? AND feed_id = ?
+RETURNING id, created_at, updated_at, user_id, feed_id, category_id, title
+`
+
+type UnfollowParams struct {
+	UserID uuid.UUID
+	FeedID uuid.UUID
+}
+
+func (q *Queries) Unfollow(ctx context.Context, arg UnfollowParams) (FeedFollow, error) {
+	row := q.db.QueryRowContext(ctx, unfollow, arg.UserID, arg.FeedID)
+	var i FeedFollow
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.FeedID,
+		&i.CategoryID,
+		&i.Title,
+	)
+	return i, err
+}
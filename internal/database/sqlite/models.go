@@ -0,0 +1,157 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+
+package sqlite
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Category struct {
+	ID        uuid.UUID
+	Seq       int64
+	UserID    uuid.UUID
+	Name      string
+	CreatedAt time.Time
+}
+
+type Feed struct {
+	ID            uuid.UUID
+	Name          string
+	Url           string
+	UserID        uuid.UUID
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	LastFetchedAt sql.NullTime
+	RedirectUrl   sql.NullString
+	RedirectCount int32
+	DeadAt        sql.NullTime
+	Seq           int64
+}
+
+type FeedCredential struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	FeedID     uuid.UUID
+	Kind       string
+	HeaderName string
+	Username   sql.NullString
+	SecretRef  string
+}
+
+type FeedFetch struct {
+	ID                uuid.UUID
+	FetchedAt         time.Time
+	FeedID            uuid.UUID
+	ContentEncoding   string
+	CompressedBytes   int64
+	UncompressedBytes int64
+}
+
+type FeedFollow struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	UserID     uuid.UUID
+	FeedID     uuid.UUID
+	CategoryID uuid.NullUUID
+	Title      sql.NullString
+}
+
+type FeedHistory struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	FeedID    uuid.UUID
+	Event     string
+	Detail    string
+}
+
+type FilterRule struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	FeedID    uuid.NullUUID
+	Action    string
+	Kind      string
+	Pattern   string
+	CreatedAt time.Time
+}
+
+type NotificationDelivery struct {
+	ID          uuid.UUID
+	RuleID      uuid.UUID
+	FeedID      uuid.UUID
+	PostCount   int32
+	Attempts    int32
+	StatusCode  sql.NullInt32
+	Error       sql.NullString
+	DeliveredAt sql.NullTime
+	CreatedAt   time.Time
+}
+
+type NotificationRule struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	FeedID     uuid.NullUUID
+	Keyword    sql.NullString
+	WebhookUrl string
+	Format     string
+	CreatedAt  time.Time
+}
+
+type Post struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Title       sql.NullString
+	Url         string
+	Description sql.NullString
+	PublishedAt sql.NullTime
+	FeedID      uuid.UUID
+	Seq         int64
+	Author      sql.NullString
+	Categories  sql.NullString
+}
+
+type PrunedPost struct {
+	Url      string
+	FeedID   uuid.UUID
+	PrunedAt time.Time
+	SeenAt   sql.NullTime
+}
+
+type ReadPost struct {
+	UserID uuid.UUID
+	PostID uuid.UUID
+	ReadAt time.Time
+}
+
+type SavedPost struct {
+	UserID    uuid.UUID
+	PostID    uuid.UUID
+	CreatedAt time.Time
+}
+
+type Session struct {
+	TokenHash string
+	UserID    uuid.UUID
+	CreatedAt time.Time
+}
+
+type User struct {
+	ID              uuid.UUID
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	Name            string
+	PasswordHash    sql.NullString
+	IsAdmin         bool
+	FeverKeyHash    sql.NullString
+	Email           sql.NullString
+	DigestFrequency sql.NullString
+	LastDigestAt    sql.NullTime
+	LastDigestSeq   sql.NullInt64
+}
@@ -0,0 +1,262 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: notifications.sql
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createNotificationDelivery = `-- name: CreateNotificationDelivery :exec
+INSERT INTO notification_deliveries (
+    id, rule_id, feed_id, post_count, attempts, status_code, error, delivered_at, created_at
+)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+`
+
+type CreateNotificationDeliveryParams struct {
+	ID          uuid.UUID
+	RuleID      uuid.UUID
+	FeedID      uuid.UUID
+	PostCount   int32
+	Attempts    int32
+	StatusCode  sql.NullInt32
+	Error       sql.NullString
+	DeliveredAt sql.NullTime
+}
+
+func (q *Queries) CreateNotificationDelivery(ctx context.Context, arg CreateNotificationDeliveryParams) error {
+	_, err := q.db.ExecContext(ctx, createNotificationDelivery,
+		arg.ID,
+		arg.RuleID,
+		arg.FeedID,
+		arg.PostCount,
+		arg.Attempts,
+		arg.StatusCode,
+		arg.Error,
+		arg.DeliveredAt,
+	)
+	return err
+}
+
+const createNotificationRule = `-- name: CreateNotificationRule :one
+INSERT INTO notification_rules (id, user_id, feed_id, keyword, webhook_url, format, created_at)
+VALUES (?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)
+RETURNING id, user_id, feed_id, keyword, webhook_url, format, created_at
+`
+
+type CreateNotificationRuleParams struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	FeedID     uuid.NullUUID
+	Keyword    sql.NullString
+	WebhookUrl string
+	Format     string
+}
+
+func (q *Queries) CreateNotificationRule(ctx context.Context, arg CreateNotificationRuleParams) (NotificationRule, error) {
+	row := q.db.QueryRowContext(ctx, createNotificationRule,
+		arg.ID,
+		arg.UserID,
+		arg.FeedID,
+		arg.Keyword,
+		arg.WebhookUrl,
+		arg.Format,
+	)
+	var i NotificationRule
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.FeedID,
+		&i.Keyword,
+		&i.WebhookUrl,
+		&i.Format,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteNotificationRule = `-- name: DeleteNotificationRule :execrows
+DELETE FROM notification_rules
+WHERE id = ? AND user_id = ?
+`
+
+type DeleteNotificationRuleParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) DeleteNotificationRule(ctx context.Context, arg DeleteNotificationRuleParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteNotificationRule, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getNotificationDeliveries = `-- name: GetNotificationDeliveries :many
+SELECT
+    notification_deliveries.id, notification_deliveries.rule_id, notification_deliveries.feed_id, notification_deliveries.post_count, notification_deliveries.attempts, notification_deliveries.status_code, notification_deliveries.error, notification_deliveries.delivered_at, notification_deliveries.created_at,
+    notification_rules.webhook_url,
+    feeds.name AS feed_name
+FROM notification_deliveries
+INNER JOIN notification_rules ON notification_deliveries.rule_id = notification_rules.id
+INNER JOIN feeds ON notification_deliveries.feed_id = feeds.id
+WHERE notification_rules.user_id = ?
+ORDER BY notification_deliveries.created_at DESC
+LIMIT ?
+`
+
+type GetNotificationDeliveriesParams struct {
+	UserID uuid.UUID
+	Limit  int64
+}
+
+type GetNotificationDeliveriesRow struct {
+	ID          uuid.UUID
+	RuleID      uuid.UUID
+	FeedID      uuid.UUID
+	PostCount   int32
+	Attempts    int32
+	StatusCode  sql.NullInt32
+	Error       sql.NullString
+	DeliveredAt sql.NullTime
+	CreatedAt   time.Time
+	WebhookUrl  string
+	FeedName    string
+}
+
+func (q *Queries) GetNotificationDeliveries(ctx context.Context, arg GetNotificationDeliveriesParams) ([]GetNotificationDeliveriesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getNotificationDeliveries, arg.UserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetNotificationDeliveriesRow
+	for rows.Next() {
+		var i GetNotificationDeliveriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.RuleID,
+			&i.FeedID,
+			&i.PostCount,
+			&i.Attempts,
+			&i.StatusCode,
+			&i.Error,
+			&i.DeliveredAt,
+			&i.CreatedAt,
+			&i.WebhookUrl,
+			&i.FeedName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNotificationRulesForFeed = `-- name: GetNotificationRulesForFeed :many
+SELECT notification_rules.id, notification_rules.user_id, notification_rules.feed_id, notification_rules.keyword, notification_rules.webhook_url, notification_rules.format, notification_rules.created_at
+FROM notification_rules
+INNER JOIN feed_follows
+    ON notification_rules.user_id = feed_follows.user_id
+WHERE
+    feed_follows.feed_id = ?1
+    AND (notification_rules.feed_id IS NULL OR notification_rules.feed_id = ?1)
+ORDER BY notification_rules.created_at
+`
+
+// The rules of the feed's followers that cover it.
+func (q *Queries) GetNotificationRulesForFeed(ctx context.Context, feedID uuid.UUID) ([]NotificationRule, error) {
+	rows, err := q.db.QueryContext(ctx, getNotificationRulesForFeed, feedID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NotificationRule
+	for rows.Next() {
+		var i NotificationRule
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.FeedID,
+			&i.Keyword,
+			&i.WebhookUrl,
+			&i.Format,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getNotificationRulesForUser = `-- name: GetNotificationRulesForUser :many
+SELECT notification_rules.id, notification_rules.user_id, notification_rules.feed_id, notification_rules.keyword, notification_rules.webhook_url, notification_rules.format, notification_rules.created_at, feeds.name AS feed_name
+FROM notification_rules
+LEFT JOIN feeds ON notification_rules.feed_id = feeds.id
+WHERE notification_rules.user_id = ?
+ORDER BY notification_rules.created_at
+`
+
+type GetNotificationRulesForUserRow struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	FeedID     uuid.NullUUID
+	Keyword    sql.NullString
+	WebhookUrl string
+	Format     string
+	CreatedAt  time.Time
+	FeedName   sql.NullString
+}
+
+func (q *Queries) GetNotificationRulesForUser(ctx context.Context, userID uuid.UUID) ([]GetNotificationRulesForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getNotificationRulesForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetNotificationRulesForUserRow
+	for rows.Next() {
+		var i GetNotificationRulesForUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.FeedID,
+			&i.Keyword,
+			&i.WebhookUrl,
+			&i.Format,
+			&i.CreatedAt,
+			&i.FeedName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
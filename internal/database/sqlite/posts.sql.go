@@ -0,0 +1,547 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: posts.sql
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const createPost = `-- name: CreatePost :execrows
+INSERT INTO posts (
+    id, created_at, updated_at, title, url, description, published_at, feed_id,
+    author, categories, seq
+)
+VALUES (
+    ?1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP,
+    nullif(CAST(?2 AS TEXT), ''), ?3,
+    nullif(CAST(?4 AS TEXT), ''),
+    ?5, ?6,
+    nullif(CAST(?7 AS TEXT), ''),
+    nullif(CAST(?8 AS TEXT), ''),
+    (SELECT COALESCE(MAX(seq), 0) + 1 FROM posts)
+)
+ON CONFLICT (url) DO NOTHING
+`
+
+type CreatePostParams struct {
+	ID          uuid.UUID
+	Title       string
+	Url         string
+	Description string
+	PublishedAt sql.NullTime
+	FeedID      uuid.UUID
+	Author      string
+	Categories  string
+}
+
+func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createPost,
+		arg.ID,
+		arg.Title,
+		arg.Url,
+		arg.Description,
+		arg.PublishedAt,
+		arg.FeedID,
+		arg.Author,
+		arg.Categories,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const createPrunedPost = `-- name: CreatePrunedPost :exec
+INSERT INTO pruned_posts (url, feed_id, pruned_at)
+VALUES (?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT (url) DO NOTHING
+`
+
+type CreatePrunedPostParams struct {
+	Url    string
+	FeedID uuid.UUID
+}
+
+func (q *Queries) CreatePrunedPost(ctx context.Context, arg CreatePrunedPostParams) error {
+	_, err := q.db.ExecContext(ctx, createPrunedPost, arg.Url, arg.FeedID)
+	return err
+}
+
+const expirePrunedPosts = `-- name: ExpirePrunedPosts :execrows
+DELETE FROM pruned_posts
+WHERE coalesce(seen_at, pruned_at) < (
+    SELECT max(feed_fetches.fetched_at) FROM feed_fetches
+    WHERE feed_fetches.feed_id = pruned_posts.feed_id
+)
+`
+
+func (q *Queries) ExpirePrunedPosts(ctx context.Context) (int64, error) {
+	result, err := q.db.ExecContext(ctx, expirePrunedPosts)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getPostByID = `-- name: GetPostByID :one
+SELECT id, created_at, updated_at, title, url, description, published_at, feed_id, seq, author, categories FROM posts
+WHERE id = ?
+`
+
+func (q *Queries) GetPostByID(ctx context.Context, id uuid.UUID) (Post, error) {
+	row := q.db.QueryRowContext(ctx, getPostByID, id)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Title,
+		&i.Url,
+		&i.Description,
+		&i.PublishedAt,
+		&i.FeedID,
+		&i.Seq,
+		&i.Author,
+		&i.Categories,
+	)
+	return i, err
+}
+
+const getPostByURL = `-- name: GetPostByURL :one
+SELECT id, created_at, updated_at, title, url, description, published_at, feed_id, seq, author, categories FROM posts
+WHERE url = ?
+`
+
+func (q *Queries) GetPostByURL(ctx context.Context, url string) (Post, error) {
+	row := q.db.QueryRowContext(ctx, getPostByURL, url)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Title,
+		&i.Url,
+		&i.Description,
+		&i.PublishedAt,
+		&i.FeedID,
+		&i.Seq,
+		&i.Author,
+		&i.Categories,
+	)
+	return i, err
+}
+
+const getPostForUser = `-- name: GetPostForUser :one
+SELECT
+    posts.id,
+    posts.title,
+    posts.url,
+    posts.description,
+    posts.published_at,
+    posts.author,
+    posts.categories,
+    posts.feed_id,
+    COALESCE(feed_follows.title, feeds.name) AS feed_name,
+    CAST(EXISTS (
+        SELECT 1 FROM read_posts
+        WHERE read_posts.post_id = posts.id AND read_posts.user_id = feed_follows.user_id
+    ) AS BOOLEAN) AS is_read,
+    CAST(EXISTS (
+        SELECT 1 FROM saved_posts
+        WHERE saved_posts.post_id = posts.id AND saved_posts.user_id = feed_follows.user_id
+    ) AS BOOLEAN) AS is_saved
+FROM posts
+INNER JOIN feeds ON posts.feed_id = feeds.id
+INNER JOIN feed_follows ON posts.feed_id = feed_follows.feed_id
+WHERE posts.id = ?1 AND feed_follows.user_id = ?2
+`
+
+type GetPostForUserParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+type GetPostForUserRow struct {
+	ID          uuid.UUID
+	Title       sql.NullString
+	Url         string
+	Description sql.NullString
+	PublishedAt sql.NullTime
+	Author      sql.NullString
+	Categories  sql.NullString
+	FeedID      uuid.UUID
+	FeedName    string
+	IsRead      bool
+	IsSaved     bool
+}
+
+func (q *Queries) GetPostForUser(ctx context.Context, arg GetPostForUserParams) (GetPostForUserRow, error) {
+	row := q.db.QueryRowContext(ctx, getPostForUser, arg.ID, arg.UserID)
+	var i GetPostForUserRow
+	err := row.Scan(
+		&i.ID,
+		&i.Title,
+		&i.Url,
+		&i.Description,
+		&i.PublishedAt,
+		&i.Author,
+		&i.Categories,
+		&i.FeedID,
+		&i.FeedName,
+		&i.IsRead,
+		&i.IsSaved,
+	)
+	return i, err
+}
+
+const getPostsByUser = `-- name: GetPostsByUser :many
+SELECT posts.title, posts.url, posts.description, posts.published_at, posts.feed_id, posts.author, posts.categories
+FROM posts
+INNER JOIN feeds
+    ON feeds.id = posts.feed_id
+INNER JOIN users
+    ON feeds.user_id = users.id
+INNER JOIN feed_follows
+    ON feed_follows.feed_id = posts.feed_id
+WHERE feed_follows.user_id = ?
+ORDER BY published_at DESC NULLS LAST
+LIMIT ? OFFSET ?
+`
+
+type GetPostsByUserParams struct {
+	UserID uuid.UUID
+	Limit  int64
+	Offset int64
+}
+
+type GetPostsByUserRow struct {
+	Title       sql.NullString
+	Url         string
+	Description sql.NullString
+	PublishedAt sql.NullTime
+	FeedID      uuid.UUID
+	Author      sql.NullString
+	Categories  sql.NullString
+}
+
+func (q *Queries) GetPostsByUser(ctx context.Context, arg GetPostsByUserParams) ([]GetPostsByUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPostsByUser, arg.UserID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPostsByUserRow
+	for rows.Next() {
+		var i GetPostsByUserRow
+		if err := rows.Scan(
+			&i.Title,
+			&i.Url,
+			&i.Description,
+			&i.PublishedAt,
+			&i.FeedID,
+			&i.Author,
+			&i.Categories,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPostsForUser = `-- name: GetPostsForUser :many
+SELECT
+    posts.id,
+    posts.title,
+    posts.url,
+    posts.description,
+    posts.published_at,
+    posts.author,
+    posts.categories,
+    posts.feed_id,
+    COALESCE(feed_follows.title, feeds.name) AS feed_name,
+    CAST(EXISTS (
+        SELECT 1 FROM read_posts
+        WHERE read_posts.post_id = posts.id AND read_posts.user_id = feed_follows.user_id
+    ) AS BOOLEAN) AS is_read,
+    CAST(EXISTS (
+        SELECT 1 FROM saved_posts
+        WHERE saved_posts.post_id = posts.id AND saved_posts.user_id = feed_follows.user_id
+    ) AS BOOLEAN) AS is_saved
+FROM posts
+INNER JOIN feeds ON posts.feed_id = feeds.id
+INNER JOIN feed_follows ON posts.feed_id = feed_follows.feed_id
+WHERE
+    feed_follows.user_id = ?1
+    AND (
+        CAST(?2 AS BOOLEAN) = FALSE
+        OR NOT EXISTS (
+            SELECT 1 FROM read_posts
+            WHERE read_posts.post_id = posts.id AND read_posts.user_id = feed_follows.user_id
+        )
+    )
+    AND (?3 IS NULL OR posts.feed_id = ?3)
+    AND (?4 IS NULL OR feed_follows.category_id = ?4)
+ORDER BY posts.published_at DESC NULLS LAST, posts.id
+LIMIT CAST(?6 AS INTEGER) OFFSET CAST(?5 AS INTEGER)
+`
+
+type GetPostsForUserParams struct {
+	UserID     uuid.UUID
+	UnreadOnly bool
+	FeedID     interface{}
+	CategoryID interface{}
+	PageOffset int64
+	PageSize   int64
+}
+
+type GetPostsForUserRow struct {
+	ID          uuid.UUID
+	Title       sql.NullString
+	Url         string
+	Description sql.NullString
+	PublishedAt sql.NullTime
+	Author      sql.NullString
+	Categories  sql.NullString
+	FeedID      uuid.UUID
+	FeedName    string
+	IsRead      bool
+	IsSaved     bool
+}
+
+func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams) ([]GetPostsForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPostsForUser,
+		arg.UserID,
+		arg.UnreadOnly,
+		arg.FeedID,
+		arg.CategoryID,
+		arg.PageOffset,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPostsForUserRow
+	for rows.Next() {
+		var i GetPostsForUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Url,
+			&i.Description,
+			&i.PublishedAt,
+			&i.Author,
+			&i.Categories,
+			&i.FeedID,
+			&i.FeedName,
+			&i.IsRead,
+			&i.IsSaved,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const isPostPruned = `-- name: IsPostPruned :one
+SELECT CAST(EXISTS (
+    SELECT 1 FROM pruned_posts
+    WHERE url = ?
+) AS BOOLEAN) AS pruned
+`
+
+func (q *Queries) IsPostPruned(ctx context.Context, url string) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isPostPruned, url)
+	var pruned bool
+	err := row.Scan(&pruned)
+	return pruned, err
+}
+
+const markPostRead = `-- name: MarkPostRead :exec
+INSERT INTO read_posts (user_id, post_id, read_at)
+VALUES (?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT (user_id, post_id) DO NOTHING
+`
+
+type MarkPostReadParams struct {
+	UserID uuid.UUID
+	PostID uuid.UUID
+}
+
+func (q *Queries) MarkPostRead(ctx context.Context, arg MarkPostReadParams) error {
+	_, err := q.db.ExecContext(ctx, markPostRead, arg.UserID, arg.PostID)
+	return err
+}
+
+const markPostUnread = `-- name: MarkPostUnread :exec
+DELETE FROM read_posts
+WHERE user_id = ? AND post_id = ?
+`
+
+type MarkPostUnreadParams struct {
+	UserID uuid.UUID
+	PostID uuid.UUID
+}
+
+func (q *Queries) MarkPostUnread(ctx context.Context, arg MarkPostUnreadParams) error {
+	_, err := q.db.ExecContext(ctx, markPostUnread, arg.UserID, arg.PostID)
+	return err
+}
+
+const markPrunedPostSeen = `-- name: MarkPrunedPostSeen :exec
+UPDATE pruned_posts SET seen_at = CURRENT_TIMESTAMP
+WHERE feed_id = ? AND url = ?
+`
+
+type MarkPrunedPostSeenParams struct {
+	FeedID uuid.UUID
+	Url    string
+}
+
+func (q *Queries) MarkPrunedPostSeen(ctx context.Context, arg MarkPrunedPostSeenParams) error {
+	_, err := q.db.ExecContext(ctx, markPrunedPostSeen, arg.FeedID, arg.Url)
+	return err
+}
+
+const prunePostsBeyondNewest = `-- name: PrunePostsBeyondNewest :many
+DELETE FROM posts
+WHERE
+    (
+        SELECT count(*) FROM posts AS newer
+        WHERE
+            newer.feed_id = posts.feed_id
+            AND (
+                coalesce(newer.published_at, '') > coalesce(posts.published_at, '')
+                OR (
+                    coalesce(newer.published_at, '') = coalesce(posts.published_at, '')
+                    AND newer.created_at > posts.created_at
+                )
+            )
+    ) >= CAST(?1 AS INTEGER)
+    AND NOT EXISTS (
+        SELECT 1 FROM saved_posts
+        WHERE saved_posts.post_id = posts.id
+    )
+RETURNING feed_id, url
+`
+
+type PrunePostsBeyondNewestRow struct {
+	FeedID uuid.UUID
+	Url    string
+}
+
+func (q *Queries) PrunePostsBeyondNewest(ctx context.Context, keep int64) ([]PrunePostsBeyondNewestRow, error) {
+	rows, err := q.db.QueryContext(ctx, prunePostsBeyondNewest, keep)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PrunePostsBeyondNewestRow
+	for rows.Next() {
+		var i PrunePostsBeyondNewestRow
+		if err := rows.Scan(&i.FeedID, &i.Url); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const prunePostsOlderThan = `-- name: PrunePostsOlderThan :many
+DELETE FROM posts
+WHERE
+    coalesce(posts.published_at, posts.created_at) < ?1
+    AND NOT EXISTS (
+        SELECT 1 FROM saved_posts
+        WHERE saved_posts.post_id = posts.id
+    )
+RETURNING feed_id, url
+`
+
+type PrunePostsOlderThanRow struct {
+	FeedID uuid.UUID
+	Url    string
+}
+
+func (q *Queries) PrunePostsOlderThan(ctx context.Context, cutoff sql.NullTime) ([]PrunePostsOlderThanRow, error) {
+	rows, err := q.db.QueryContext(ctx, prunePostsOlderThan, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PrunePostsOlderThanRow
+	for rows.Next() {
+		var i PrunePostsOlderThanRow
+		if err := rows.Scan(&i.FeedID, &i.Url); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const savePost = `-- name: SavePost :exec
+INSERT INTO saved_posts (user_id, post_id, created_at)
+VALUES (?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT (user_id, post_id) DO NOTHING
+`
+
+type SavePostParams struct {
+	UserID uuid.UUID
+	PostID uuid.UUID
+}
+
+func (q *Queries) SavePost(ctx context.Context, arg SavePostParams) error {
+	_, err := q.db.ExecContext(ctx, savePost, arg.UserID, arg.PostID)
+	return err
+}
+
+const unsavePost = `-- name: UnsavePost :execrows
+DELETE FROM saved_posts
+WHERE user_id = ? AND post_id = ?
+`
+
+type UnsavePostParams struct {
+	UserID uuid.UUID
+	PostID uuid.UUID
+}
+
+func (q *Queries) UnsavePost(ctx context.Context, arg UnsavePostParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, unsavePost, arg.UserID, arg.PostID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,582 @@
+package sqlite
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	_ "modernc.org/sqlite"
+
+	"github.com/lmilojevicc/gator/internal/database"
+)
+
+// Open opens the SQLite database at path with foreign keys enforced, which
+// the ON DELETE CASCADE clauses of the schema rely on.
+func Open(path string) (*sql.DB, error) {
+	dsn := "file:" + path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_time_format=sqlite"
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("opening sqlite database: %w", err)
+	}
+
+	return db, nil
+}
+
+// Store adapts the SQLite queries to database.Querier. Times are written in
+// UTC so that the text timestamps SQLite stores compare correctly.
+type Store struct {
+	q *Queries
+}
+
+var _ database.Querier = (*Store)(nil)
+
+func NewStore(db DBTX) *Store {
+	return &Store{q: New(db)}
+}
+
+func (s *Store) WithTx(tx *sql.Tx) *Store {
+	return &Store{q: s.q.WithTx(tx)}
+}
+
+func (s *Store) ClearFeedCredentials(ctx context.Context, feedID uuid.UUID) error {
+	return s.q.ClearFeedCredentials(ctx, feedID)
+}
+
+func (s *Store) ClearFeedRedirect(ctx context.Context, id uuid.UUID) error {
+	return s.q.ClearFeedRedirect(ctx, id)
+}
+
+func (s *Store) CountAdmins(ctx context.Context) (int64, error) {
+	return s.q.CountAdmins(ctx)
+}
+
+func (s *Store) CountOtherFeedFollowers(ctx context.Context, arg database.CountOtherFeedFollowersParams) (int64, error) {
+	return s.q.CountOtherFeedFollowers(ctx, CountOtherFeedFollowersParams(arg))
+}
+
+func (s *Store) CreateCategory(ctx context.Context, arg database.CreateCategoryParams) (database.Category, error) {
+	category, err := s.q.CreateCategory(ctx, CreateCategoryParams(arg))
+	return database.Category(category), err
+}
+
+func (s *Store) CreateFeed(ctx context.Context, arg database.CreateFeedParams) (database.Feed, error) {
+	feed, err := s.q.CreateFeed(ctx, CreateFeedParams(arg))
+	return database.Feed(feed), err
+}
+
+func (s *Store) CreateFeedFetch(ctx context.Context, arg database.CreateFeedFetchParams) error {
+	return s.q.CreateFeedFetch(ctx, CreateFeedFetchParams(arg))
+}
+
+func (s *Store) CreateFeedFollow(ctx context.Context, arg database.CreateFeedFollowParams) (database.CreateFeedFollowRow, error) {
+	follow, err := s.q.CreateFeedFollow(ctx, CreateFeedFollowParams(arg))
+	if err != nil {
+		return database.CreateFeedFollowRow{}, err
+	}
+
+	row, err := s.q.GetFeedFollowByID(ctx, follow.ID)
+	return database.CreateFeedFollowRow(row), err
+}
+
+func (s *Store) CreateFeedHistory(ctx context.Context, arg database.CreateFeedHistoryParams) error {
+	return s.q.CreateFeedHistory(ctx, CreateFeedHistoryParams(arg))
+}
+
+func (s *Store) CreateFilterRule(ctx context.Context, arg database.CreateFilterRuleParams) (database.FilterRule, error) {
+	rule, err := s.q.CreateFilterRule(ctx, CreateFilterRuleParams(arg))
+	return database.FilterRule(rule), err
+}
+
+func (s *Store) CreateNotificationDelivery(ctx context.Context, arg database.CreateNotificationDeliveryParams) error {
+	arg.DeliveredAt.Time = arg.DeliveredAt.Time.UTC()
+	return s.q.CreateNotificationDelivery(ctx, CreateNotificationDeliveryParams(arg))
+}
+
+func (s *Store) CreateNotificationRule(ctx context.Context, arg database.CreateNotificationRuleParams) (database.NotificationRule, error) {
+	rule, err := s.q.CreateNotificationRule(ctx, CreateNotificationRuleParams(arg))
+	return database.NotificationRule(rule), err
+}
+
+func (s *Store) CreateNonAdminUser(ctx context.Context, arg database.CreateNonAdminUserParams) (database.User, error) {
+	user, err := s.q.CreateNonAdminUser(ctx, CreateNonAdminUserParams(arg))
+	return database.User(user), err
+}
+
+// CreatePosts inserts the posts one statement at a time; against a local
+// SQLite file there is no round trip to save.
+func (s *Store) CreatePosts(ctx context.Context, arg database.CreatePostsParams) ([]uuid.UUID, error) {
+	var created []uuid.UUID
+	for i := range arg.Ids {
+		// PostgreSQL skips pruned URLs in the insert itself.
+		pruned, err := s.q.IsPostPruned(ctx, arg.Urls[i])
+		if err != nil {
+			return created, err
+		}
+		if pruned {
+			continue
+		}
+
+		n, err := s.q.CreatePost(ctx, CreatePostParams{
+			ID:          arg.Ids[i],
+			Title:       arg.Titles[i],
+			Url:         arg.Urls[i],
+			Description: arg.Descriptions[i],
+			PublishedAt: sql.NullTime{Time: arg.PublishedAts[i].UTC(), Valid: !arg.PublishedAts[i].IsZero()},
+			FeedID:      arg.FeedID,
+			Author:      arg.Authors[i],
+			Categories:  arg.Categories[i],
+		})
+		if err != nil {
+			return created, err
+		}
+		if n > 0 {
+			created = append(created, arg.Ids[i])
+		}
+	}
+	return created, nil
+}
+
+func (s *Store) CreateSession(ctx context.Context, arg database.CreateSessionParams) error {
+	return s.q.CreateSession(ctx, CreateSessionParams(arg))
+}
+
+func (s *Store) CreateUser(ctx context.Context, arg database.CreateUserParams) (database.User, error) {
+	user, err := s.q.CreateUser(ctx, CreateUserParams(arg))
+	return database.User(user), err
+}
+
+func (s *Store) DeleteCategory(ctx context.Context, arg database.DeleteCategoryParams) (int64, error) {
+	return s.q.DeleteCategory(ctx, DeleteCategoryParams(arg))
+}
+
+func (s *Store) DeleteFeed(ctx context.Context, id uuid.UUID) error {
+	return s.q.DeleteFeed(ctx, id)
+}
+
+func (s *Store) DeleteFeedAuthorization(ctx context.Context, feedID uuid.UUID) error {
+	return s.q.DeleteFeedAuthorization(ctx, feedID)
+}
+
+func (s *Store) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	return s.q.DeleteUser(ctx, id)
+}
+
+func (s *Store) DeleteFilterRule(ctx context.Context, arg database.DeleteFilterRuleParams) (int64, error) {
+	return s.q.DeleteFilterRule(ctx, DeleteFilterRuleParams(arg))
+}
+
+func (s *Store) DeleteNotificationRule(ctx context.Context, arg database.DeleteNotificationRuleParams) (int64, error) {
+	return s.q.DeleteNotificationRule(ctx, DeleteNotificationRuleParams(arg))
+}
+
+func (s *Store) DeleteOtherSessions(ctx context.Context, arg database.DeleteOtherSessionsParams) error {
+	return s.q.DeleteOtherSessions(ctx, DeleteOtherSessionsParams(arg))
+}
+
+func (s *Store) DeleteSession(ctx context.Context, tokenHash string) error {
+	return s.q.DeleteSession(ctx, tokenHash)
+}
+
+func (s *Store) ExpirePrunedPosts(ctx context.Context) (int64, error) {
+	return s.q.ExpirePrunedPosts(ctx)
+}
+
+func (s *Store) GetAllFeeds(ctx context.Context) ([]database.Feed, error) {
+	feeds, err := s.q.GetAllFeeds(ctx)
+	return convert(feeds, func(f Feed) database.Feed { return database.Feed(f) }), err
+}
+
+func (s *Store) GetCategoriesForUser(ctx context.Context, userID uuid.UUID) ([]database.GetCategoriesForUserRow, error) {
+	rows, err := s.q.GetCategoriesForUser(ctx, userID)
+	return convert(rows, func(r GetCategoriesForUserRow) database.GetCategoriesForUserRow {
+		return database.GetCategoriesForUserRow(r)
+	}), err
+}
+
+func (s *Store) GetCategoryByName(ctx context.Context, arg database.GetCategoryByNameParams) (database.Category, error) {
+	category, err := s.q.GetCategoryByName(ctx, GetCategoryByNameParams(arg))
+	return database.Category(category), err
+}
+
+func (s *Store) GetFeedBandwidth(ctx context.Context, fetchedAt time.Time) ([]database.GetFeedBandwidthRow, error) {
+	rows, err := s.q.GetFeedBandwidth(ctx, fetchedAt.UTC())
+	return convert(rows, func(r GetFeedBandwidthRow) database.GetFeedBandwidthRow { return database.GetFeedBandwidthRow(r) }), err
+}
+
+func (s *Store) GetFeverFeeds(ctx context.Context, userID uuid.UUID) ([]database.GetFeverFeedsRow, error) {
+	rows, err := s.q.GetFeverFeeds(ctx, userID)
+	return convert(rows, func(r GetFeverFeedsRow) database.GetFeverFeedsRow { return database.GetFeverFeedsRow(r) }), err
+}
+
+func (s *Store) GetFeverItemStates(ctx context.Context, userID uuid.UUID) ([]database.GetFeverItemStatesRow, error) {
+	rows, err := s.q.GetFeverItemStates(ctx, userID)
+	return convert(rows, func(r GetFeverItemStatesRow) database.GetFeverItemStatesRow { return database.GetFeverItemStatesRow(r) }), err
+}
+
+func (s *Store) GetFeverItemsAfter(ctx context.Context, arg database.GetFeverItemsAfterParams) ([]database.GetFeverItemsAfterRow, error) {
+	rows, err := s.q.GetFeverItemsAfter(ctx, GetFeverItemsAfterParams{
+		UserID:   arg.UserID,
+		AfterSeq: arg.AfterSeq,
+		MaxItems: int64(arg.MaxItems),
+	})
+	return convert(rows, func(r GetFeverItemsAfterRow) database.GetFeverItemsAfterRow { return database.GetFeverItemsAfterRow(r) }), err
+}
+
+func (s *Store) GetFeverItemsBefore(ctx context.Context, arg database.GetFeverItemsBeforeParams) ([]database.GetFeverItemsBeforeRow, error) {
+	rows, err := s.q.GetFeverItemsBefore(ctx, GetFeverItemsBeforeParams{
+		UserID:    arg.UserID,
+		BeforeSeq: arg.BeforeSeq,
+		MaxItems:  int64(arg.MaxItems),
+	})
+	return convert(rows, func(r GetFeverItemsBeforeRow) database.GetFeverItemsBeforeRow {
+		return database.GetFeverItemsBeforeRow(r)
+	}), err
+}
+
+func (s *Store) GetDigestPosts(ctx context.Context, arg database.GetDigestPostsParams) ([]database.GetDigestPostsRow, error) {
+	rows, err := s.q.GetDigestPosts(ctx, GetDigestPostsParams{
+		UserID:      arg.UserID,
+		AddedAfter:  arg.AddedAfter.UTC(),
+		AddedBefore: arg.AddedBefore.UTC(),
+		AfterSeq:    arg.AfterSeq,
+		PageOffset:  int64(arg.PageOffset),
+		MaxPosts:    int64(arg.MaxPosts),
+	})
+	return convert(rows, func(r GetDigestPostsRow) database.GetDigestPostsRow { return database.GetDigestPostsRow(r) }), err
+}
+
+func (s *Store) GetDigestSubscribers(ctx context.Context) ([]database.User, error) {
+	users, err := s.q.GetDigestSubscribers(ctx)
+	return convert(users, func(u User) database.User { return database.User(u) }), err
+}
+
+func (s *Store) GetFeedByURL(ctx context.Context, url string) (database.Feed, error) {
+	feed, err := s.q.GetFeedByURL(ctx, url)
+	return database.Feed(feed), err
+}
+
+func (s *Store) GetFeedCredentials(ctx context.Context, feedID uuid.UUID) ([]database.FeedCredential, error) {
+	credentials, err := s.q.GetFeedCredentials(ctx, feedID)
+	return convert(credentials, func(c FeedCredential) database.FeedCredential { return database.FeedCredential(c) }), err
+}
+
+func (s *Store) GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) ([]database.GetFeedFollowsForUserRow, error) {
+	rows, err := s.q.GetFeedFollowsForUser(ctx, userID)
+	return convert(rows, func(r GetFeedFollowsForUserRow) database.GetFeedFollowsForUserRow {
+		return database.GetFeedFollowsForUserRow(r)
+	}), err
+}
+
+func (s *Store) GetFeedHistory(ctx context.Context, feedID uuid.UUID) ([]database.FeedHistory, error) {
+	history, err := s.q.GetFeedHistory(ctx, feedID)
+	return convert(history, func(h FeedHistory) database.FeedHistory { return database.FeedHistory(h) }), err
+}
+
+func (s *Store) GetFilterRulesForUser(ctx context.Context, userID uuid.UUID) ([]database.GetFilterRulesForUserRow, error) {
+	rows, err := s.q.GetFilterRulesForUser(ctx, userID)
+	return convert(rows, func(r GetFilterRulesForUserRow) database.GetFilterRulesForUserRow {
+		return database.GetFilterRulesForUserRow(r)
+	}), err
+}
+
+func (s *Store) GetNextFeedToFetch(ctx context.Context) (database.Feed, error) {
+	feed, err := s.q.GetNextFeedToFetch(ctx)
+	return database.Feed(feed), err
+}
+
+func (s *Store) GetNotificationDeliveries(ctx context.Context, arg database.GetNotificationDeliveriesParams) ([]database.GetNotificationDeliveriesRow, error) {
+	rows, err := s.q.GetNotificationDeliveries(ctx, GetNotificationDeliveriesParams{
+		UserID: arg.UserID,
+		Limit:  int64(arg.Limit),
+	})
+	return convert(rows, func(r GetNotificationDeliveriesRow) database.GetNotificationDeliveriesRow {
+		return database.GetNotificationDeliveriesRow(r)
+	}), err
+}
+
+func (s *Store) GetNotificationRulesForFeed(ctx context.Context, feedID uuid.UUID) ([]database.NotificationRule, error) {
+	rules, err := s.q.GetNotificationRulesForFeed(ctx, feedID)
+	return convert(rules, func(r NotificationRule) database.NotificationRule { return database.NotificationRule(r) }), err
+}
+
+func (s *Store) GetNotificationRulesForUser(ctx context.Context, userID uuid.UUID) ([]database.GetNotificationRulesForUserRow, error) {
+	rows, err := s.q.GetNotificationRulesForUser(ctx, userID)
+	return convert(rows, func(r GetNotificationRulesForUserRow) database.GetNotificationRulesForUserRow {
+		return database.GetNotificationRulesForUserRow(r)
+	}), err
+}
+
+func (s *Store) GetPostByURL(ctx context.Context, url string) (database.Post, error) {
+	post, err := s.q.GetPostByURL(ctx, url)
+	return database.Post(post), err
+}
+
+func (s *Store) GetPostByID(ctx context.Context, id uuid.UUID) (database.Post, error) {
+	post, err := s.q.GetPostByID(ctx, id)
+	return database.Post(post), err
+}
+
+func (s *Store) GetPostForUser(ctx context.Context, arg database.GetPostForUserParams) (database.GetPostForUserRow, error) {
+	post, err := s.q.GetPostForUser(ctx, GetPostForUserParams(arg))
+	return database.GetPostForUserRow(post), err
+}
+
+func (s *Store) GetPostsForUser(ctx context.Context, arg database.GetPostsForUserParams) ([]database.GetPostsForUserRow, error) {
+	rows, err := s.q.GetPostsForUser(ctx, GetPostsForUserParams{
+		UserID:     arg.UserID,
+		UnreadOnly: arg.UnreadOnly,
+		FeedID:     arg.FeedID,
+		CategoryID: arg.CategoryID,
+		PageOffset: int64(arg.PageOffset),
+		PageSize:   int64(arg.PageSize),
+	})
+	return convert(rows, func(r GetPostsForUserRow) database.GetPostsForUserRow { return database.GetPostsForUserRow(r) }), err
+}
+
+func (s *Store) GetPostsByUser(ctx context.Context, arg database.GetPostsByUserParams) ([]database.GetPostsByUserRow, error) {
+	rows, err := s.q.GetPostsByUser(ctx, GetPostsByUserParams{
+		UserID: arg.UserID,
+		Limit:  int64(arg.Limit),
+		Offset: int64(arg.Offset),
+	})
+	return convert(rows, func(r GetPostsByUserRow) database.GetPostsByUserRow { return database.GetPostsByUserRow(r) }), err
+}
+
+func (s *Store) GetUserByFeverKey(ctx context.Context, feverKeyHash sql.NullString) (database.User, error) {
+	user, err := s.q.GetUserByFeverKey(ctx, feverKeyHash)
+	return database.User(user), err
+}
+
+func (s *Store) GetUserByID(ctx context.Context, id uuid.UUID) (database.User, error) {
+	user, err := s.q.GetUserByID(ctx, id)
+	return database.User(user), err
+}
+
+func (s *Store) GetUserByName(ctx context.Context, name string) (database.User, error) {
+	user, err := s.q.GetUserByName(ctx, name)
+	return database.User(user), err
+}
+
+func (s *Store) GetUserBySession(ctx context.Context, tokenHash string) (database.User, error) {
+	user, err := s.q.GetUserBySession(ctx, tokenHash)
+	return database.User(user), err
+}
+
+func (s *Store) GetUsers(ctx context.Context) ([]database.User, error) {
+	users, err := s.q.GetUsers(ctx)
+	return convert(users, func(u User) database.User { return database.User(u) }), err
+}
+
+func (s *Store) MarkFeverPostsRead(ctx context.Context, arg database.MarkFeverPostsReadParams) error {
+	return s.q.MarkFeverPostsRead(ctx, MarkFeverPostsReadParams{
+		UserID:      arg.UserID,
+		FeedSeq:     arg.FeedSeq,
+		AddedBefore: arg.AddedBefore.UTC(),
+	})
+}
+
+func (s *Store) MarkFeedDead(ctx context.Context, id uuid.UUID) error {
+	return s.q.MarkFeedDead(ctx, id)
+}
+
+func (s *Store) MarkPostRead(ctx context.Context, arg database.MarkPostReadParams) error {
+	return s.q.MarkPostRead(ctx, MarkPostReadParams(arg))
+}
+
+func (s *Store) MarkPostUnread(ctx context.Context, arg database.MarkPostUnreadParams) error {
+	return s.q.MarkPostUnread(ctx, MarkPostUnreadParams(arg))
+}
+
+// MarkPrunedPostsSeen updates the pruned URLs one at a time, like CreatePosts.
+func (s *Store) MarkPrunedPostsSeen(ctx context.Context, arg database.MarkPrunedPostsSeenParams) error {
+	for _, url := range arg.Urls {
+		err := s.q.MarkPrunedPostSeen(ctx, MarkPrunedPostSeenParams{FeedID: arg.FeedID, Url: url})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) MarkFeedFetched(ctx context.Context, id uuid.UUID) error {
+	return s.q.MarkFeedFetched(ctx, id)
+}
+
+func (s *Store) PrunePostsBeyondNewest(ctx context.Context, keep int64) ([]database.PrunePostsBeyondNewestRow, error) {
+	deleted, err := s.q.PrunePostsBeyondNewest(ctx, keep)
+	if err != nil {
+		return nil, err
+	}
+
+	removed, err := s.tombstone(ctx, convert(deleted, func(r PrunePostsBeyondNewestRow) PrunePostsOlderThanRow {
+		return PrunePostsOlderThanRow(r)
+	}))
+	return convert(removed, func(r database.PrunePostsOlderThanRow) database.PrunePostsBeyondNewestRow {
+		return database.PrunePostsBeyondNewestRow(r)
+	}), err
+}
+
+func (s *Store) PrunePostsOlderThan(ctx context.Context, cutoff time.Time) ([]database.PrunePostsOlderThanRow, error) {
+	deleted, err := s.q.PrunePostsOlderThan(ctx, sql.NullTime{Time: cutoff.UTC(), Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.tombstone(ctx, deleted)
+}
+
+// tombstone records the posts returned by a DELETE ... RETURNING as pruned and
+// counts them per feed, both of which PostgreSQL does in the query itself.
+func (s *Store) tombstone(ctx context.Context, deleted []PrunePostsOlderThanRow) ([]database.PrunePostsOlderThanRow, error) {
+	if len(deleted) == 0 {
+		return nil, nil
+	}
+
+	for _, post := range deleted {
+		err := s.q.CreatePrunedPost(ctx, CreatePrunedPostParams{Url: post.Url, FeedID: post.FeedID})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	feeds, err := s.q.GetAllFeeds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[uuid.UUID]int64{}
+	for _, post := range deleted {
+		counts[post.FeedID]++
+	}
+
+	var rows []database.PrunePostsOlderThanRow
+	for _, feed := range feeds {
+		if counts[feed.ID] > 0 {
+			rows = append(rows, database.PrunePostsOlderThanRow{
+				FeedID:  feed.ID,
+				Name:    feed.Name,
+				Url:     feed.Url,
+				Removed: counts[feed.ID],
+			})
+		}
+	}
+	slices.SortStableFunc(rows, func(a, b database.PrunePostsOlderThanRow) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.Url, b.Url))
+	})
+	return rows, nil
+}
+
+func (s *Store) RecordFeedRedirect(ctx context.Context, arg database.RecordFeedRedirectParams) (int32, error) {
+	return s.q.RecordFeedRedirect(ctx, RecordFeedRedirectParams{
+		RedirectUrl: arg.RedirectUrl,
+		ID:          arg.ID,
+	})
+}
+
+func (s *Store) RenameCategory(ctx context.Context, arg database.RenameCategoryParams) (int64, error) {
+	return s.q.RenameCategory(ctx, RenameCategoryParams{
+		Name:   arg.Name,
+		ID:     arg.ID,
+		UserID: arg.UserID,
+	})
+}
+
+func (s *Store) RenameFeed(ctx context.Context, arg database.RenameFeedParams) (database.Feed, error) {
+	feed, err := s.q.RenameFeed(ctx, RenameFeedParams{
+		Name: arg.Name,
+		ID:   arg.ID,
+	})
+	return database.Feed(feed), err
+}
+
+func (s *Store) ResetUsers(ctx context.Context) error {
+	return s.q.ResetUsers(ctx)
+}
+
+func (s *Store) SavePost(ctx context.Context, arg database.SavePostParams) error {
+	return s.q.SavePost(ctx, SavePostParams(arg))
+}
+
+func (s *Store) SetFeedCredential(ctx context.Context, arg database.SetFeedCredentialParams) error {
+	return s.q.SetFeedCredential(ctx, SetFeedCredentialParams(arg))
+}
+
+func (s *Store) SetFollowCategory(ctx context.Context, arg database.SetFollowCategoryParams) (int64, error) {
+	return s.q.SetFollowCategory(ctx, SetFollowCategoryParams(arg))
+}
+
+func (s *Store) SetFollowTitle(ctx context.Context, arg database.SetFollowTitleParams) (int64, error) {
+	return s.q.SetFollowTitle(ctx, SetFollowTitleParams(arg))
+}
+
+func (s *Store) SetUserAdmin(ctx context.Context, arg database.SetUserAdminParams) error {
+	return s.q.SetUserAdmin(ctx, SetUserAdminParams{
+		IsAdmin: arg.IsAdmin,
+		ID:      arg.ID,
+	})
+}
+
+func (s *Store) SetUserDigest(ctx context.Context, arg database.SetUserDigestParams) error {
+	return s.q.SetUserDigest(ctx, SetUserDigestParams{
+		Email:           arg.Email,
+		DigestFrequency: arg.DigestFrequency,
+		ID:              arg.ID,
+	})
+}
+
+func (s *Store) SetUserFeverKey(ctx context.Context, arg database.SetUserFeverKeyParams) error {
+	return s.q.SetUserFeverKey(ctx, SetUserFeverKeyParams{
+		FeverKeyHash: arg.FeverKeyHash,
+		ID:           arg.ID,
+	})
+}
+
+func (s *Store) SetUserLastDigest(ctx context.Context, arg database.SetUserLastDigestParams) error {
+	arg.LastDigestAt.Time = arg.LastDigestAt.Time.UTC()
+	return s.q.SetUserLastDigest(ctx, SetUserLastDigestParams{
+		LastDigestAt:  arg.LastDigestAt,
+		LastDigestSeq: arg.LastDigestSeq,
+		ID:            arg.ID,
+	})
+}
+
+func (s *Store) SetUserPassword(ctx context.Context, arg database.SetUserPasswordParams) error {
+	return s.q.SetUserPassword(ctx, SetUserPasswordParams{
+		PasswordHash: arg.PasswordHash,
+		ID:           arg.ID,
+	})
+}
+
+func (s *Store) Unfollow(ctx context.Context, arg database.UnfollowParams) (database.FeedFollow, error) {
+	follow, err := s.q.Unfollow(ctx, UnfollowParams(arg))
+	return database.FeedFollow(follow), err
+}
+
+func (s *Store) UnsavePost(ctx context.Context, arg database.UnsavePostParams) (int64, error) {
+	return s.q.UnsavePost(ctx, UnsavePostParams(arg))
+}
+
+func (s *Store) UpdateFeedURL(ctx context.Context, arg database.UpdateFeedURLParams) (database.Feed, error) {
+	feed, err := s.q.UpdateFeedURL(ctx, UpdateFeedURLParams{
+		Url: arg.Url,
+		ID:  arg.ID,
+	})
+	return database.Feed(feed), err
+}
+
+func convert[From, To any](items []From, fn func(From) To) []To {
+	if items == nil {
+		return nil
+	}
+
+	converted := make([]To, len(items))
+	for i, item := range items {
+		converted[i] = fn(item)
+	}
+	return converted
+}
@@ -0,0 +1,115 @@
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lmilojevicc/gator/internal/config"
+	"github.com/lmilojevicc/gator/internal/database"
+	"github.com/lmilojevicc/gator/internal/database/sqlite"
+	"github.com/lmilojevicc/gator/internal/migrations"
+)
+
+// newStore returns a Store on a migrated database in a temporary directory.
+func newStore(t *testing.T) *sqlite.Store {
+	t.Helper()
+
+	db, err := sqlite.Open(t.TempDir() + "/gator.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	provider, err := migrations.NewProvider(db, config.DriverSQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := provider.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	return sqlite.NewStore(db)
+}
+
+func TestPrunePostsOlderThan(t *testing.T) {
+	ctx := context.Background()
+	st := newStore(t)
+
+	user, err := st.CreateUser(ctx, database.CreateUserParams{ID: uuid.New(), Name: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	feed, err := st.CreateFeed(ctx, database.CreateFeedParams{
+		ID:     uuid.New(),
+		Name:   "blog",
+		Url:    "https://example.com/rss",
+		UserID: user.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	_, err = st.CreatePosts(ctx, database.CreatePostsParams{
+		FeedID:       feed.ID,
+		Ids:          []uuid.UUID{uuid.New(), uuid.New(), uuid.New()},
+		Titles:       []string{"old", "new", "undated"},
+		Urls:         []string{"https://example.com/old", "https://example.com/new", "https://example.com/undated"},
+		Descriptions: []string{"", "", ""},
+		PublishedAts: []time.Time{now.AddDate(0, 0, -10), now.Add(-time.Hour), {}},
+		Authors:      []string{"", "", ""},
+		Categories:   []string{"", "", ""},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A cutoff in a non-UTC zone must compare like the same instant in UTC.
+	cutoff := now.AddDate(0, 0, -1).In(time.FixedZone("east", 14*60*60))
+	removed, err := st.PrunePostsOlderThan(ctx, cutoff)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []database.PrunePostsOlderThanRow{{FeedID: feed.ID, Name: "blog", Url: feed.Url, Removed: 1}}
+	if len(removed) != 1 || removed[0] != want[0] {
+		t.Errorf("removed = %+v, want %+v", removed, want)
+	}
+
+	// The pruned post is not inserted again when the feed still has it.
+	created, err := st.CreatePosts(ctx, database.CreatePostsParams{
+		FeedID:       feed.ID,
+		Ids:          []uuid.UUID{uuid.New()},
+		Titles:       []string{"old"},
+		Urls:         []string{"https://example.com/old"},
+		Descriptions: []string{""},
+		PublishedAts: []time.Time{now.AddDate(0, 0, -10)},
+		Authors:      []string{""},
+		Categories:   []string{""},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(created) != 0 {
+		t.Errorf("pruned post was inserted again")
+	}
+
+	// Nor is it forgotten while the latest fetch still has it.
+	err = st.CreateFeedFetch(ctx, database.CreateFeedFetchParams{ID: uuid.New(), FeedID: feed.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = st.MarkPrunedPostsSeen(ctx, database.MarkPrunedPostsSeenParams{
+		FeedID: feed.ID,
+		Urls:   []string{"https://example.com/old"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expired, err := st.ExpirePrunedPosts(ctx); err != nil || expired != 0 {
+		t.Errorf("ExpirePrunedPosts = %d, %v; want 0 while the post is in the feed", expired, err)
+	}
+}
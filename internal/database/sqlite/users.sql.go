@@ -0,0 +1,265 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: users.sql
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const countAdmins = `-- name: CountAdmins :one
+SELECT count(*) FROM users
+WHERE is_admin
+`
+
+func (q *Queries) CountAdmins(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countAdmins)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createNonAdminUser = `-- name: CreateNonAdminUser :one
+INSERT INTO users (id, created_at, updated_at, name, password_hash, is_admin)
+VALUES (?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP, ?, ?, false)
+RETURNING id, created_at, updated_at, name, password_hash, is_admin, fever_key_hash, email, digest_frequency, last_digest_at, last_digest_seq
+`
+
+type CreateNonAdminUserParams struct {
+	ID           uuid.UUID
+	Name         string
+	PasswordHash sql.NullString
+}
+
+func (q *Queries) CreateNonAdminUser(ctx context.Context, arg CreateNonAdminUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, createNonAdminUser, arg.ID, arg.Name, arg.PasswordHash)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.PasswordHash,
+		&i.IsAdmin,
+		&i.FeverKeyHash,
+		&i.Email,
+		&i.DigestFrequency,
+		&i.LastDigestAt,
+		&i.LastDigestSeq,
+	)
+	return i, err
+}
+
+const createUser = `-- name: CreateUser :one
+INSERT INTO users (id, created_at, updated_at, name, password_hash, is_admin)
+VALUES (?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP, ?, ?, NOT EXISTS (SELECT 1 FROM users))
+RETURNING id, created_at, updated_at, name, password_hash, is_admin, fever_key_hash, email, digest_frequency, last_digest_at, last_digest_seq
+`
+
+type CreateUserParams struct {
+	ID           uuid.UUID
+	Name         string
+	PasswordHash sql.NullString
+}
+
+func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, createUser, arg.ID, arg.Name, arg.PasswordHash)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.PasswordHash,
+		&i.IsAdmin,
+		&i.FeverKeyHash,
+		&i.Email,
+		&i.DigestFrequency,
+		&i.LastDigestAt,
+		&i.LastDigestSeq,
+	)
+	return i, err
+}
+
+const deleteUser = `-- name: DeleteUser :exec
+DELETE FROM users
+WHERE id = ?
+`
+
+func (q *Queries) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteUser, id)
+	return err
+}
+
+const getUserByFeverKey = `-- name: GetUserByFeverKey :one
+SELECT id, created_at, updated_at, name, password_hash, is_admin, fever_key_hash, email, digest_frequency, last_digest_at, last_digest_seq FROM users
+WHERE fever_key_hash = ?
+`
+
+func (q *Queries) GetUserByFeverKey(ctx context.Context, feverKeyHash sql.NullString) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByFeverKey, feverKeyHash)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.PasswordHash,
+		&i.IsAdmin,
+		&i.FeverKeyHash,
+		&i.Email,
+		&i.DigestFrequency,
+		&i.LastDigestAt,
+		&i.LastDigestSeq,
+	)
+	return i, err
+}
+
+const getUserByID = `-- name: GetUserByID :one
+SELECT id, created_at, updated_at, name, password_hash, is_admin, fever_key_hash, email, digest_frequency, last_digest_at, last_digest_seq FROM users
+WHERE id = ?
+`
+
+func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByID, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.PasswordHash,
+		&i.IsAdmin,
+		&i.FeverKeyHash,
+		&i.Email,
+		&i.DigestFrequency,
+		&i.LastDigestAt,
+		&i.LastDigestSeq,
+	)
+	return i, err
+}
+
+const getUserByName = `-- name: GetUserByName :one
+SELECT id, created_at, updated_at, name, password_hash, is_admin, fever_key_hash, email, digest_frequency, last_digest_at, last_digest_seq FROM users
+WHERE name = ?
+`
+
+func (q *Queries) GetUserByName(ctx context.Context, name string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByName, name)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.PasswordHash,
+		&i.IsAdmin,
+		&i.FeverKeyHash,
+		&i.Email,
+		&i.DigestFrequency,
+		&i.LastDigestAt,
+		&i.LastDigestSeq,
+	)
+	return i, err
+}
+
+const getUsers = `-- name: GetUsers :many
+SELECT id, created_at, updated_at, name, password_hash, is_admin, fever_key_hash, email, digest_frequency, last_digest_at, last_digest_seq FROM users
+`
+
+func (q *Queries) GetUsers(ctx context.Context) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, getUsers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Name,
+			&i.PasswordHash,
+			&i.IsAdmin,
+			&i.FeverKeyHash,
+			&i.Email,
+			&i.DigestFrequency,
+			&i.LastDigestAt,
+			&i.LastDigestSeq,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const resetUsers = `-- name: ResetUsers :exec
+DELETE FROM users
+`
+
+func (q *Queries) ResetUsers(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, resetUsers)
+	return err
+}
+
+const setUserAdmin = `-- name: SetUserAdmin :exec
+UPDATE users
+SET is_admin = ?1, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?2
+`
+
+type SetUserAdminParams struct {
+	IsAdmin bool
+	ID      uuid.UUID
+}
+
+func (q *Queries) SetUserAdmin(ctx context.Context, arg SetUserAdminParams) error {
+	_, err := q.db.ExecContext(ctx, setUserAdmin, arg.IsAdmin, arg.ID)
+	return err
+}
+
+const setUserFeverKey = `-- name: SetUserFeverKey :exec
+UPDATE users
+SET fever_key_hash = ?1, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?2
+`
+
+type SetUserFeverKeyParams struct {
+	FeverKeyHash sql.NullString
+	ID           uuid.UUID
+}
+
+func (q *Queries) SetUserFeverKey(ctx context.Context, arg SetUserFeverKeyParams) error {
+	_, err := q.db.ExecContext(ctx, setUserFeverKey, arg.FeverKeyHash, arg.ID)
+	return err
+}
+
+const setUserPassword = `-- name: SetUserPassword :exec
+UPDATE users
+SET password_hash = ?1, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?2
+`
+
+type SetUserPasswordParams struct {
+	PasswordHash sql.NullString
+	ID           uuid.UUID
+}
+
+func (q *Queries) SetUserPassword(ctx context.Context, arg SetUserPasswordParams) error {
+	_, err := q.db.ExecContext(ctx, setUserPassword, arg.PasswordHash, arg.ID)
+	return err
+}
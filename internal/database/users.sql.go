@@ -7,35 +7,121 @@ package database
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/google/uuid"
 )
 
+const countAdmins = `-- name: CountAdmins :one
+SELECT count(*) FROM users
+WHERE is_admin
+`
+
+func (q *Queries) CountAdmins(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countAdmins)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createNonAdminUser = `-- name: CreateNonAdminUser :one
+INSERT INTO users (id, created_at, updated_at, name, password_hash, is_admin)
+VALUES ($1, now(), now(), $2, $3, false)
+RETURNING id, created_at, updated_at, name, password_hash, is_admin, fever_key_hash, email, digest_frequency, last_digest_at, last_digest_seq
+`
+
+type CreateNonAdminUserParams struct {
+	ID           uuid.UUID
+	Name         string
+	PasswordHash sql.NullString
+}
+
+func (q *Queries) CreateNonAdminUser(ctx context.Context, arg CreateNonAdminUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, createNonAdminUser, arg.ID, arg.Name, arg.PasswordHash)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.PasswordHash,
+		&i.IsAdmin,
+		&i.FeverKeyHash,
+		&i.Email,
+		&i.DigestFrequency,
+		&i.LastDigestAt,
+		&i.LastDigestSeq,
+	)
+	return i, err
+}
+
 const createUser = `-- name: CreateUser :one
-INSERT INTO users (id, created_at, updated_at, name)
-VALUES ($1, now(), now(), $2)
-RETURNING id, created_at, updated_at, name
+INSERT INTO users (id, created_at, updated_at, name, password_hash, is_admin)
+VALUES ($1, now(), now(), $2, $3, NOT EXISTS (SELECT 1 FROM users))
+RETURNING id, created_at, updated_at, name, password_hash, is_admin, fever_key_hash, email, digest_frequency, last_digest_at, last_digest_seq
 `
 
 type CreateUserParams struct {
-	ID   uuid.UUID
-	Name string
+	ID           uuid.UUID
+	Name         string
+	PasswordHash sql.NullString
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, createUser, arg.ID, arg.Name)
+	row := q.db.QueryRowContext(ctx, createUser, arg.ID, arg.Name, arg.PasswordHash)
 	var i User
 	err := row.Scan(
 		&i.ID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Name,
+		&i.PasswordHash,
+		&i.IsAdmin,
+		&i.FeverKeyHash,
+		&i.Email,
+		&i.DigestFrequency,
+		&i.LastDigestAt,
+		&i.LastDigestSeq,
+	)
+	return i, err
+}
+
+const deleteUser = `-- name: DeleteUser :exec
+DELETE FROM users
+WHERE id = $1
+`
+
+func (q *Queries) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteUser, id)
+	return err
+}
+
+const getUserByFeverKey = `-- name: GetUserByFeverKey :one
+SELECT id, created_at, updated_at, name, password_hash, is_admin, fever_key_hash, email, digest_frequency, last_digest_at, last_digest_seq FROM users
+WHERE fever_key_hash = $1
+`
+
+func (q *Queries) GetUserByFeverKey(ctx context.Context, feverKeyHash sql.NullString) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByFeverKey, feverKeyHash)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.PasswordHash,
+		&i.IsAdmin,
+		&i.FeverKeyHash,
+		&i.Email,
+		&i.DigestFrequency,
+		&i.LastDigestAt,
+		&i.LastDigestSeq,
 	)
 	return i, err
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, created_at, updated_at, name FROM users
+SELECT id, created_at, updated_at, name, password_hash, is_admin, fever_key_hash, email, digest_frequency, last_digest_at, last_digest_seq FROM users
 WHERE id = $1
 `
 
@@ -47,12 +133,19 @@ func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Name,
+		&i.PasswordHash,
+		&i.IsAdmin,
+		&i.FeverKeyHash,
+		&i.Email,
+		&i.DigestFrequency,
+		&i.LastDigestAt,
+		&i.LastDigestSeq,
 	)
 	return i, err
 }
 
 const getUserByName = `-- name: GetUserByName :one
-SELECT id, created_at, updated_at, name FROM users
+SELECT id, created_at, updated_at, name, password_hash, is_admin, fever_key_hash, email, digest_frequency, last_digest_at, last_digest_seq FROM users
 WHERE name = $1
 `
 
@@ -64,12 +157,19 @@ func (q *Queries) GetUserByName(ctx context.Context, name string) (User, error)
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.Name,
+		&i.PasswordHash,
+		&i.IsAdmin,
+		&i.FeverKeyHash,
+		&i.Email,
+		&i.DigestFrequency,
+		&i.LastDigestAt,
+		&i.LastDigestSeq,
 	)
 	return i, err
 }
 
 const getUsers = `-- name: GetUsers :many
-SELECT id, created_at, updated_at, name FROM users
+SELECT id, created_at, updated_at, name, password_hash, is_admin, fever_key_hash, email, digest_frequency, last_digest_at, last_digest_seq FROM users
 `
 
 func (q *Queries) GetUsers(ctx context.Context) ([]User, error) {
@@ -86,6 +186,13 @@ func (q *Queries) GetUsers(ctx context.Context) ([]User, error) {
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.Name,
+			&i.PasswordHash,
+			&i.IsAdmin,
+			&i.FeverKeyHash,
+			&i.Email,
+			&i.DigestFrequency,
+			&i.LastDigestAt,
+			&i.LastDigestSeq,
 		); err != nil {
 			return nil, err
 		}
@@ -108,3 +215,51 @@ func (q *Queries) ResetUsers(ctx context.Context) error {
 	_, err := q.db.ExecContext(ctx, resetUsers)
 	return err
 }
+
+const setUserAdmin = `-- name: SetUserAdmin :exec
+UPDATE users
+SET is_admin = $2, updated_at = now()
+WHERE id = $1
+`
+
+type SetUserAdminParams struct {
+	ID      uuid.UUID
+	IsAdmin bool
+}
+
+func (q *Queries) SetUserAdmin(ctx context.Context, arg SetUserAdminParams) error {
+	_, err := q.db.ExecContext(ctx, setUserAdmin, arg.ID, arg.IsAdmin)
+	return err
+}
+
+const setUserFeverKey = `-- name: SetUserFeverKey :exec
+UPDATE users
+SET fever_key_hash = $2, updated_at = now()
+WHERE id = $1
+`
+
+type SetUserFeverKeyParams struct {
+	ID           uuid.UUID
+	FeverKeyHash sql.NullString
+}
+
+func (q *Queries) SetUserFeverKey(ctx context.Context, arg SetUserFeverKeyParams) error {
+	_, err := q.db.ExecContext(ctx, setUserFeverKey, arg.ID, arg.FeverKeyHash)
+	return err
+}
+
+const setUserPassword = `-- name: SetUserPassword :exec
+UPDATE users
+SET password_hash = $2, updated_at = now()
+WHERE id = $1
+`
+
+type SetUserPasswordParams struct {
+	ID           uuid.UUID
+	PasswordHash sql.NullString
+}
+
+func (q *Queries) SetUserPassword(ctx context.Context, arg SetUserPasswordParams) error {
+	_, err := q.db.ExecContext(ctx, setUserPassword, arg.ID, arg.PasswordHash)
+	return err
+}
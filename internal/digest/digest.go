@@ -0,0 +1,158 @@
+// Package digest mails users a summary of the posts added to the feeds they
+// follow since their previous digest.
+package digest
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lmilojevicc/gator/internal/database"
+	"github.com/lmilojevicc/gator/internal/filter"
+)
+
+// Frequencies a user can subscribe to.
+const (
+	Daily  = "daily"
+	Weekly = "weekly"
+)
+
+var Frequencies = []string{Daily, Weekly}
+
+// maxPosts caps the number of posts listed in one digest.
+const maxPosts = 500
+
+// slack lets a digest go out a little early, so that a cron job running at the
+// same time every day does not find the previous digest a few seconds short of
+// a day old.
+const slack = time.Hour
+
+type Digest struct {
+	User database.User
+	// Posts added after Since and up to Until are included. After a digest
+	// that was cut short, the posts added after its last one are included
+	// instead of those after Since.
+	Since time.Time
+	Until time.Time
+	Feeds []Feed
+	// Truncated is set when more than maxPosts posts passed the filter rules.
+	// LastSeq is then the seq of the last post included, where the next
+	// digest starts.
+	Truncated bool
+	LastSeq   int64
+}
+
+type Feed struct {
+	Name  string
+	Posts []Post
+}
+
+type Post struct {
+	Title       string
+	URL         string
+	PublishedAt time.Time
+}
+
+func period(frequency string) time.Duration {
+	if frequency == Weekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// Due reports whether user is subscribed and their previous digest, if any,
+// was sent at least a day or a week before now.
+func Due(user database.User, now time.Time) bool {
+	if !user.Email.Valid || !user.DigestFrequency.Valid {
+		return false
+	}
+	return !user.LastDigestAt.Valid || now.Sub(user.LastDigestAt.Time) >= period(user.DigestFrequency.String)-slack
+}
+
+// Build collects the posts added to the follows of user since their previous
+// digest, or during the last day or week for the first one, leaving out the
+// posts their filter rules hide.
+func Build(ctx context.Context, q database.Querier, user database.User, now time.Time) (Digest, error) {
+	d := Digest{
+		User:  user,
+		Since: now.Add(-period(user.DigestFrequency.String)),
+		Until: now,
+	}
+	if user.LastDigestAt.Valid {
+		d.Since = user.LastDigestAt.Time
+	}
+
+	f, err := filter.Load(ctx, q, user.ID)
+	if err != nil {
+		return Digest{}, err
+	}
+
+	params := database.GetDigestPostsParams{
+		UserID:      user.ID,
+		AddedAfter:  d.Since,
+		AddedBefore: d.Until,
+	}
+	if user.LastDigestSeq.Valid {
+		params.AddedAfter = time.Time{}
+		params.AfterSeq = user.LastDigestSeq.Int64
+	}
+	rows, err := filter.Page(f, 0, maxPosts+1, filter.FromDigestPost, func(offset, limit int) ([]database.GetDigestPostsRow, error) {
+		params.PageOffset = int32(offset)
+		params.MaxPosts = int32(limit)
+		return q.GetDigestPosts(ctx, params)
+	})
+	if err != nil {
+		return Digest{}, err
+	}
+	if len(rows) > maxPosts {
+		rows, d.Truncated = rows[:maxPosts], true
+		d.LastSeq = rows[len(rows)-1].Seq
+	}
+
+	// The rows are in the order the posts were added; the digest lists them
+	// by feed, newest first.
+	feeds := map[uuid.UUID]int{}
+	for _, row := range rows {
+		i, ok := feeds[row.FeedID]
+		if !ok {
+			i = len(d.Feeds)
+			feeds[row.FeedID] = i
+			d.Feeds = append(d.Feeds, Feed{Name: row.FeedName})
+		}
+		d.Feeds[i].Posts = append(d.Feeds[i].Posts, Post{
+			Title:       row.Title.String,
+			URL:         row.Url,
+			PublishedAt: row.PublishedAt.Time,
+		})
+	}
+	slices.SortStableFunc(d.Feeds, func(a, b Feed) int { return strings.Compare(a.Name, b.Name) })
+	for _, feed := range d.Feeds {
+		slices.SortStableFunc(feed.Posts, newestFirst)
+	}
+
+	return d, nil
+}
+
+// newestFirst orders posts by publication date, newest first, with undated
+// posts last.
+func newestFirst(a, b Post) int {
+	if a.PublishedAt.IsZero() != b.PublishedAt.IsZero() {
+		if a.PublishedAt.IsZero() {
+			return 1
+		}
+		return -1
+	}
+	return b.PublishedAt.Compare(a.PublishedAt)
+}
+
+// PostCount is the number of posts in the digest.
+func (d Digest) PostCount() int {
+	n := 0
+	for _, feed := range d.Feeds {
+		n += len(feed.Posts)
+	}
+	return n
+}
@@ -0,0 +1,294 @@
+package digest
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lmilojevicc/gator/internal/config"
+	"github.com/lmilojevicc/gator/internal/database"
+	"github.com/lmilojevicc/gator/internal/filter"
+	"github.com/lmilojevicc/gator/internal/smtptest"
+	"github.com/lmilojevicc/gator/internal/store/memory"
+)
+
+func subscriber(frequency string, last time.Time) database.User {
+	return database.User{
+		ID:              uuid.New(),
+		Name:            "alice",
+		Email:           sql.NullString{String: "alice@example.com", Valid: true},
+		DigestFrequency: sql.NullString{String: frequency, Valid: true},
+		LastDigestAt:    sql.NullTime{Time: last, Valid: !last.IsZero()},
+	}
+}
+
+func TestDue(t *testing.T) {
+	now := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		user database.User
+		want bool
+	}{
+		{"not subscribed", database.User{Email: sql.NullString{String: "a@example.com", Valid: true}}, false},
+		{"first digest", subscriber(Daily, time.Time{}), true},
+		{"daily, a day ago", subscriber(Daily, now.Add(-24*time.Hour)), true},
+		// A cron job at 08:00 should not skip a day because the previous
+		// digest went out at 08:00:05.
+		{"daily, almost a day ago", subscriber(Daily, now.Add(-24*time.Hour+5*time.Second)), true},
+		{"daily, this morning", subscriber(Daily, now.Add(-2*time.Hour)), false},
+		{"weekly, two days ago", subscriber(Weekly, now.Add(-48*time.Hour)), false},
+		{"weekly, a week ago", subscriber(Weekly, now.Add(-7*24*time.Hour)), true},
+	}
+	for _, tt := range tests {
+		if got := Due(tt.user, now); got != tt.want {
+			t.Errorf("%s: Due = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// setup stores two feeds followed by alice with posts added now.
+func setup(t *testing.T) (*memory.Store, database.User) {
+	t.Helper()
+	ctx := context.Background()
+
+	st := memory.New()
+	alice, err := st.CreateUser(ctx, database.CreateUserParams{ID: uuid.New(), Name: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = st.SetUserDigest(ctx, database.SetUserDigestParams{
+		ID:              alice.ID,
+		Email:           sql.NullString{String: "alice@example.com", Valid: true},
+		DigestFrequency: sql.NullString{String: Daily, Valid: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"Zig News", "Go Blog"} {
+		feed, err := st.CreateFeed(ctx, database.CreateFeedParams{
+			ID:     uuid.New(),
+			Name:   name,
+			Url:    "https://example.com/" + name,
+			UserID: alice.ID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := st.CreateFeedFollow(ctx, database.CreateFeedFollowParams{ID: uuid.New(), UserID: alice.ID, FeedID: feed.ID}); err != nil {
+			t.Fatal(err)
+		}
+		_, err = st.CreatePosts(ctx, database.CreatePostsParams{
+			FeedID:       feed.ID,
+			Ids:          []uuid.UUID{uuid.New(), uuid.New()},
+			Titles:       []string{name + " <one>", ""},
+			Urls:         []string{"https://example.com/" + name + "/1", "https://example.com/" + name + "/2"},
+			Descriptions: []string{"", ""},
+			PublishedAts: []time.Time{time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), {}},
+			Authors:      []string{"", ""},
+			Categories:   []string{"", ""},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	alice, err = st.GetUserByID(ctx, alice.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return st, alice
+}
+
+func TestBuild(t *testing.T) {
+	st, alice := setup(t)
+	now := time.Now().Add(time.Second)
+
+	d, err := Build(context.Background(), st, alice, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.PostCount() != 4 || len(d.Feeds) != 2 || d.Feeds[0].Name != "Go Blog" {
+		t.Fatalf("digest = %+v, want 4 posts of Go Blog and Zig News", d)
+	}
+	if d.Subject() != "Your daily gator digest: 4 new posts" {
+		t.Errorf("subject = %q", d.Subject())
+	}
+
+	// Posts hidden by the filter rules are left out.
+	_, err = st.CreateFilterRule(context.Background(), database.CreateFilterRuleParams{
+		ID:      uuid.New(),
+		UserID:  alice.ID,
+		Action:  filter.ActionExclude,
+		Kind:    filter.KindKeyword,
+		Pattern: "<one>",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err = Build(context.Background(), st, alice, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.PostCount() != 2 {
+		t.Errorf("filtered digest has %d posts, want 2", d.PostCount())
+	}
+
+	// Posts added before the previous digest are not repeated.
+	alice.LastDigestAt = sql.NullTime{Time: now, Valid: true}
+	d, err = Build(context.Background(), st, alice, now.Add(24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.PostCount() != 0 {
+		t.Errorf("digest after the previous one has %d posts, want 0", d.PostCount())
+	}
+}
+
+func TestBuildTruncated(t *testing.T) {
+	st, alice := setup(t)
+	ctx := context.Background()
+
+	// A single fetch adds all of these at once, which PostgreSQL stamps with
+	// the same created_at.
+	follows, err := st.GetFeedFollowsForUser(ctx, alice.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := database.CreatePostsParams{FeedID: follows[0].FeedID}
+	for range maxPosts + 1 {
+		params.Ids = append(params.Ids, uuid.New())
+		params.Titles = append(params.Titles, "more")
+		params.Urls = append(params.Urls, "https://example.com/"+uuid.NewString())
+		params.Descriptions = append(params.Descriptions, "")
+		params.PublishedAts = append(params.PublishedAts, time.Time{})
+		params.Authors = append(params.Authors, "")
+		params.Categories = append(params.Categories, "")
+	}
+	if _, err := st.CreatePosts(ctx, params); err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().Add(time.Second)
+
+	d, err := Build(ctx, st, alice, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.Truncated || d.PostCount() != maxPosts {
+		t.Fatalf("digest has %d posts, truncated = %v; want %d, truncated", d.PostCount(), d.Truncated, maxPosts)
+	}
+	text, err := d.Text()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(text, "follow in your next digest") {
+		t.Errorf("text does not say posts were left out:\n%s", text)
+	}
+
+	// The next digest, a day later, has the posts this one had no room for.
+	err = st.SetUserLastDigest(ctx, database.SetUserLastDigestParams{
+		ID:            alice.ID,
+		LastDigestAt:  sql.NullTime{Time: now, Valid: true},
+		LastDigestSeq: sql.NullInt64{Int64: d.LastSeq, Valid: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alice, err = st.GetUserByID(ctx, alice.ID); err != nil {
+		t.Fatal(err)
+	}
+	next, err := Build(ctx, st, alice, now.Add(24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.Truncated || d.PostCount()+next.PostCount() != maxPosts+5 {
+		t.Errorf("digests have %d and %d posts, want %d in all", d.PostCount(), next.PostCount(), maxPosts+5)
+	}
+}
+
+func TestSend(t *testing.T) {
+	st, alice := setup(t)
+	srv := smtptest.NewServer(t)
+	t.Setenv("SMTP_PASSWORD", "hunter2")
+
+	mailer, err := NewMailer(config.SMTPConfig{
+		Host:     srv.Host,
+		Port:     srv.Port,
+		Username: "gator",
+		Password: "env:SMTP_PASSWORD",
+		From:     "Gator <gator@example.com>",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := Build(context.Background(), st, alice, time.Now().Add(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mailer.Send(d); err != nil {
+		t.Fatal(err)
+	}
+
+	messages := srv.Messages()
+	if len(messages) != 1 {
+		t.Fatalf("server received %d messages, want 1", len(messages))
+	}
+	got := messages[0]
+	if got.From != "gator@example.com" || len(got.To) != 1 || got.To[0] != "alice@example.com" || got.Auth != "gator:hunter2" {
+		t.Errorf("envelope = %+v", got)
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(got.Data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject := msg.Header.Get("Subject"); subject != "Your daily gator digest: 4 new posts" {
+		t.Errorf("Subject = %q", subject)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q, %v", msg.Header.Get("Content-Type"), err)
+	}
+
+	bodies := map[string]string{}
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		// NextPart undoes the quoted-printable encoding.
+		body, _ := io.ReadAll(part)
+		mediaType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		bodies[mediaType] = strings.ReplaceAll(string(body), "\r\n", "\n")
+	}
+
+	text := bodies["text/plain"]
+	for _, want := range []string{"\nGo Blog\n- Go Blog <one> (2024-05-01 10:00)\n  https://example.com/Go Blog/1", "- https://example.com/Zig News/2\n"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text part does not contain %q:\n%s", want, text)
+		}
+	}
+	if html := bodies["text/html"]; !strings.Contains(html, "Go Blog &lt;one&gt;</a>") {
+		t.Errorf("html part does not escape the title:\n%s", html)
+	}
+}
+
+func TestNewMailerRequiresHost(t *testing.T) {
+	if _, err := NewMailer(config.SMTPConfig{From: "gator@example.com"}); err == nil {
+		t.Error("NewMailer without smtp.host succeeded")
+	}
+}
@@ -0,0 +1,110 @@
+package digest
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"time"
+
+	"github.com/lmilojevicc/gator/internal/config"
+	"github.com/lmilojevicc/gator/internal/secret"
+)
+
+// Mailer sends digests through the SMTP server of the config.
+type Mailer struct {
+	addr string
+	auth smtp.Auth
+	from *mail.Address
+}
+
+func NewMailer(cfg config.SMTPConfig) (*Mailer, error) {
+	if cfg.Host == "" {
+		return nil, fmt.Errorf("smtp.host is not configured")
+	}
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid smtp.from address %q: %w", cfg.From, err)
+	}
+
+	m := &Mailer{
+		addr: net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		from: from,
+	}
+	if cfg.Username != "" {
+		password, err := secret.Resolve(cfg.Password)
+		if err != nil {
+			return nil, fmt.Errorf("resolving smtp.password: %w", err)
+		}
+		m.auth = smtp.PlainAuth("", cfg.Username, password, cfg.Host)
+	}
+
+	return m, nil
+}
+
+// Send mails d to the email address of its user.
+func (m *Mailer) Send(d Digest) error {
+	msg, err := Message(m.from, d, time.Now())
+	if err != nil {
+		return err
+	}
+	return smtp.SendMail(m.addr, m.auth, m.from.Address, []string{d.User.Email.String}, msg)
+}
+
+// Message renders d as a multipart/alternative email with a plain text and an
+// HTML part, both quoted-printable.
+func Message(from *mail.Address, d Digest, date time.Time) ([]byte, error) {
+	text, err := d.Text()
+	if err != nil {
+		return nil, err
+	}
+	html, err := d.HTML()
+	if err != nil {
+		return nil, err
+	}
+
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", text},
+		{"text/html; charset=utf-8", html},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	to := mail.Address{Name: d.User.Name, Address: d.User.Email.String}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", from)
+	fmt.Fprintf(&msg, "To: %s\r\n", &to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", d.Subject()))
+	fmt.Fprintf(&msg, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%s\r\n", parts.Boundary())
+	fmt.Fprintf(&msg, "\r\n")
+	msg.Write(body.Bytes())
+
+	return msg.Bytes(), nil
+}
@@ -0,0 +1,59 @@
+package digest
+
+import (
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+//go:embed templates
+var templatesFS embed.FS
+
+var funcs = map[string]any{
+	"date": func(t time.Time) string {
+		return t.Format("2006-01-02 15:04")
+	},
+	"postTitle": func(p Post) string {
+		if p.Title == "" {
+			return p.URL
+		}
+		return p.Title
+	},
+}
+
+var (
+	textTemplate = texttemplate.Must(texttemplate.New("digest.txt").Funcs(funcs).ParseFS(templatesFS, "templates/digest.txt"))
+	htmlTemplate = htmltemplate.Must(htmltemplate.New("digest.html").Funcs(funcs).ParseFS(templatesFS, "templates/digest.html"))
+)
+
+// Subject is the subject line of the digest email, e.g. "Your daily gator
+// digest: 12 new posts".
+func (d Digest) Subject() string {
+	noun := "posts"
+	if d.PostCount() == 1 {
+		noun = "post"
+	}
+	return fmt.Sprintf("Your %s gator digest: %d new %s", d.User.DigestFrequency.String, d.PostCount(), noun)
+}
+
+// Text renders the plain text version of the digest.
+func (d Digest) Text() (string, error) {
+	var b strings.Builder
+	if err := textTemplate.Execute(&b, d); err != nil {
+		return "", fmt.Errorf("rendering text digest: %w", err)
+	}
+	return b.String(), nil
+}
+
+// HTML renders the HTML version of the digest. Titles and feed names are
+// escaped; descriptions are left out.
+func (d Digest) HTML() (string, error) {
+	var b strings.Builder
+	if err := htmlTemplate.Execute(&b, d); err != nil {
+		return "", fmt.Errorf("rendering html digest: %w", err)
+	}
+	return b.String(), nil
+}
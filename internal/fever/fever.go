@@ -0,0 +1,224 @@
+// Package fever implements the Fever API, which many mobile feed readers
+// (Reeder, Unread, FeedMe, ...) speak. Clients POST an api_key, the md5 of
+// "name:password", to /fever/?api along with query flags naming the sections
+// they want. Feeds and items are identified by their seq, since Fever ids are
+// integers.
+//
+// Every feed is in the group "All", and the feeds a user sorted into a
+// category are in a group of that name too.
+package fever
+
+import (
+	"context"
+	"crypto/md5"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lmilojevicc/gator/internal/auth"
+	"github.com/lmilojevicc/gator/internal/database"
+	"github.com/lmilojevicc/gator/internal/filter"
+	"github.com/lmilojevicc/gator/internal/store"
+)
+
+const (
+	apiVersion = 3
+
+	// allGroup holds every feed; 0 is Fever's own id for all items. The group
+	// of a category is numbered allGroup + its seq.
+	allGroup = 1
+
+	// maxItems is how many items Fever returns per request.
+	maxItems = 50
+)
+
+type Server struct {
+	store store.Store
+}
+
+func New(st store.Store) *Server {
+	return &Server{store: st}
+}
+
+// APIKey is the key a Fever client derives from the credentials it is given.
+// The user's fever_key_hash stores its auth.HashToken.
+func APIKey(name, password string) string {
+	sum := md5.Sum([]byte(name + ":" + password))
+	return hex.EncodeToString(sum[:])
+}
+
+// Handler returns the endpoint at /fever/. Clients differ on the trailing
+// slash, so both forms are served.
+func (srv *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/fever", srv.handle)
+	mux.HandleFunc("/fever/{$}", srv.handle)
+	return mux
+}
+
+// response is a Fever reply: the base fields plus one key per section asked
+// for.
+type response map[string]any
+
+func (srv *Server) handle(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+	if !r.Form.Has("api") {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	resp := response{"api_version": apiVersion, "auth": 0}
+
+	user, err := srv.store.GetUserByFeverKey(r.Context(), sql.NullString{
+		String: auth.HashToken(strings.ToLower(r.PostFormValue("api_key"))),
+		Valid:  true,
+	})
+	if err == sql.ErrNoRows {
+		writeJSON(w, resp)
+		return
+	}
+	if err != nil {
+		serverError(w, "getting user", err)
+		return
+	}
+	resp["auth"] = 1
+
+	if err := srv.respond(r, user, resp); err != nil {
+		serverError(w, "answering request", err)
+		return
+	}
+	writeJSON(w, resp)
+}
+
+// respond applies the mark request, if any, and fills in the sections the
+// client asked for.
+func (srv *Server) respond(r *http.Request, user database.User, resp response) error {
+	ctx := r.Context()
+
+	feeds, err := srv.store.GetFeverFeeds(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	var lastRefreshed time.Time
+	for _, feed := range feeds {
+		if feed.LastFetchedAt.Valid && feed.LastFetchedAt.Time.After(lastRefreshed) {
+			lastRefreshed = feed.LastFetchedAt.Time
+		}
+	}
+	resp["last_refreshed_on_time"] = unixTime(lastRefreshed)
+
+	// Fever answers a mark with the current ids of the state it changed.
+	var changed string
+	if r.Form.Has("mark") {
+		changed, err = srv.mark(ctx, user, feeds, r.Form)
+		if err != nil {
+			return err
+		}
+	}
+	wants := func(section string) bool {
+		return r.Form.Has(section) || section == changed
+	}
+
+	if wants("groups") || wants("feeds") {
+		categories, err := srv.store.GetCategoriesForUser(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+		if wants("groups") {
+			resp["groups"] = groupList(categories)
+		}
+		if wants("feeds") {
+			resp["feeds"] = feedList(feeds)
+		}
+		resp["feeds_groups"] = feedsGroups(feeds, categories)
+	}
+	if wants("favicons") {
+		resp["favicons"] = []struct{}{}
+	}
+	if wants("links") {
+		resp["links"] = []struct{}{}
+	}
+
+	if !wants("items") && !wants("unread_item_ids") && !wants("saved_item_ids") {
+		return nil
+	}
+
+	// Items hidden by the user's filter rules are left out of every section.
+	f, err := filter.Load(ctx, srv.store, user.ID)
+	if err != nil {
+		return err
+	}
+
+	if wants("items") {
+		items, err := srv.items(ctx, user, f, r.Form)
+		if err != nil {
+			return err
+		}
+		resp["items"] = items
+	}
+
+	states, err := srv.store.GetFeverItemStates(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+
+	total := 0
+	var unread, saved []string
+	for _, state := range states {
+		if !f.Allows(filter.FromFeverItemState(state)) {
+			continue
+		}
+		total++
+		if !state.IsRead {
+			unread = append(unread, strconv.FormatInt(state.Seq, 10))
+		}
+		if state.IsSaved {
+			saved = append(saved, strconv.FormatInt(state.Seq, 10))
+		}
+	}
+
+	if wants("items") {
+		resp["total_items"] = total
+	}
+	if wants("unread_item_ids") {
+		resp["unread_item_ids"] = strings.Join(unread, ",")
+	}
+	if wants("saved_item_ids") {
+		resp["saved_item_ids"] = strings.Join(saved, ",")
+	}
+
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("fever: writing response: %v", err)
+	}
+}
+
+// serverError logs err and answers 500 without leaking the details.
+func serverError(w http.ResponseWriter, doing string, err error) {
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	log.Printf("fever: %s: %v", doing, err)
+	http.Error(w, "internal server error", http.StatusInternalServerError)
+}
+
+// unixTime is t in seconds, or 0 for the zero time.
+func unixTime(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
@@ -0,0 +1,300 @@
+package fever
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lmilojevicc/gator/internal/auth"
+	"github.com/lmilojevicc/gator/internal/database"
+	"github.com/lmilojevicc/gator/internal/filter"
+	"github.com/lmilojevicc/gator/internal/store/memory"
+)
+
+type reply struct {
+	APIVersion    int          `json:"api_version"`
+	Auth          int          `json:"auth"`
+	Groups        []group      `json:"groups"`
+	FeedsGroups   []feedsGroup `json:"feeds_groups"`
+	Feeds         []feed       `json:"feeds"`
+	Items         []item       `json:"items"`
+	TotalItems    int          `json:"total_items"`
+	UnreadItemIDs *string      `json:"unread_item_ids"`
+	SavedItemIDs  *string      `json:"saved_item_ids"`
+}
+
+type client struct {
+	t   *testing.T
+	srv *httptest.Server
+	key string
+}
+
+// newClient serves a store where alice follows a feed with three posts and
+// has the Fever API enabled for password hunter2.
+func newClient(t *testing.T) (*client, *memory.Store, []uuid.UUID) {
+	t.Helper()
+	ctx := context.Background()
+
+	st := memory.New()
+	key := APIKey("alice", "hunter2")
+	alice, err := st.CreateUser(ctx, database.CreateUserParams{ID: uuid.New(), Name: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = st.SetUserFeverKey(ctx, database.SetUserFeverKeyParams{
+		ID:           alice.ID,
+		FeverKeyHash: sql.NullString{String: auth.HashToken(key), Valid: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	feed, err := st.CreateFeed(ctx, database.CreateFeedParams{
+		ID:     uuid.New(),
+		Name:   "blog",
+		Url:    "https://example.com/rss",
+		UserID: alice.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = st.CreateFeedFollow(ctx, database.CreateFeedFollowParams{
+		ID:     uuid.New(),
+		UserID: alice.ID,
+		FeedID: feed.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	_, err = st.CreatePosts(ctx, database.CreatePostsParams{
+		FeedID:       feed.ID,
+		Ids:          ids,
+		Titles:       []string{"one", "two", "three"},
+		Urls:         []string{"https://example.com/1", "https://example.com/2", "https://example.com/3"},
+		Descriptions: []string{"<p>1</p>", "", ""},
+		PublishedAts: []time.Time{time.Unix(1700000000, 0), {}, {}},
+		Authors:      []string{"", "", ""},
+		Categories:   []string{"", "", ""},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(New(st).Handler())
+	t.Cleanup(srv.Close)
+	return &client{t: t, srv: srv, key: key}, st, ids
+}
+
+// call POSTs the api_key to /fever/ with query, as Fever clients do.
+func (c *client) call(query string) reply {
+	c.t.Helper()
+
+	resp, err := c.srv.Client().PostForm(c.srv.URL+"/fever/?api&"+query, url.Values{"api_key": {c.key}})
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		c.t.Fatalf("%s: status %d", query, resp.StatusCode)
+	}
+
+	var r reply
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		c.t.Fatalf("%s: decoding response: %v", query, err)
+	}
+	return r
+}
+
+func TestAuth(t *testing.T) {
+	c, _, _ := newClient(t)
+
+	if r := c.call(""); r.APIVersion != 3 || r.Auth != 1 {
+		t.Errorf("reply = %+v, want api_version 3 and auth 1", r)
+	}
+
+	c.key = APIKey("alice", "wrong")
+	if r := c.call("feeds"); r.Auth != 0 || r.Feeds != nil {
+		t.Errorf("reply with a wrong key = %+v, want auth 0 and no feeds", r)
+	}
+}
+
+func TestFeedsAndGroups(t *testing.T) {
+	c, _, _ := newClient(t)
+
+	r := c.call("groups&feeds")
+	if len(r.Groups) != 1 || r.Groups[0].ID != allGroup {
+		t.Errorf("groups = %+v", r.Groups)
+	}
+	if len(r.Feeds) != 1 || r.Feeds[0].Title != "blog" || r.Feeds[0].URL != "https://example.com/rss" {
+		t.Fatalf("feeds = %+v", r.Feeds)
+	}
+	want := feedsGroup{GroupID: allGroup, FeedIDs: "1"}
+	if len(r.FeedsGroups) != 1 || r.FeedsGroups[0] != want || r.Feeds[0].ID != 1 {
+		t.Errorf("feeds_groups = %+v, feeds = %+v", r.FeedsGroups, r.Feeds)
+	}
+}
+
+func TestCategoryGroups(t *testing.T) {
+	c, st, _ := newClient(t)
+	ctx := context.Background()
+	alice := mustUser(t, st)
+
+	feed, err := st.GetFeedByURL(ctx, "https://example.com/rss")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// An empty category is listed as a group without feeds.
+	for _, name := range []string{"Empty", "Tech"} {
+		category, err := st.CreateCategory(ctx, database.CreateCategoryParams{ID: uuid.New(), UserID: alice.ID, Name: name})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name != "Tech" {
+			continue
+		}
+		_, err = st.SetFollowCategory(ctx, database.SetFollowCategoryParams{
+			CategoryID: uuid.NullUUID{UUID: category.ID, Valid: true},
+			UserID:     alice.ID,
+			FeedID:     feed.ID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := c.call("groups")
+	wantGroups := []group{{ID: allGroup, Title: "All"}, {ID: allGroup + 1, Title: "Empty"}, {ID: allGroup + 2, Title: "Tech"}}
+	if !slices.Equal(r.Groups, wantGroups) {
+		t.Errorf("groups = %+v, want %+v", r.Groups, wantGroups)
+	}
+	wantFeedsGroups := []feedsGroup{{GroupID: allGroup, FeedIDs: "1"}, {GroupID: allGroup + 2, FeedIDs: "1"}}
+	if !slices.Equal(r.FeedsGroups, wantFeedsGroups) {
+		t.Errorf("feeds_groups = %+v, want %+v", r.FeedsGroups, wantFeedsGroups)
+	}
+
+	r = c.call("mark=group&as=read&id=2")
+	if *r.UnreadItemIDs != "1,2,3" {
+		t.Errorf("after marking the empty group read: unread = %q", *r.UnreadItemIDs)
+	}
+	r = c.call("mark=group&as=read&id=3")
+	if *r.UnreadItemIDs != "" {
+		t.Errorf("after marking Tech read: unread = %q", *r.UnreadItemIDs)
+	}
+}
+
+func TestItems(t *testing.T) {
+	c, _, _ := newClient(t)
+
+	r := c.call("items")
+	if r.TotalItems != 3 || len(r.Items) != 3 {
+		t.Fatalf("total_items = %d, items = %+v", r.TotalItems, r.Items)
+	}
+	first := r.Items[0]
+	if first.ID != 1 || first.FeedID != 1 || first.Title != "one" || first.HTML != "<p>1</p>" || first.CreatedOnTime != 1700000000 {
+		t.Errorf("first item = %+v", first)
+	}
+
+	if r := c.call("items&since_id=1"); len(r.Items) != 2 || r.Items[0].ID != 2 {
+		t.Errorf("since_id=1: items = %+v", r.Items)
+	}
+	// max_id pages back from the newest.
+	if r := c.call("items&max_id=3"); len(r.Items) != 2 || r.Items[0].ID != 2 || r.Items[1].ID != 1 {
+		t.Errorf("max_id=3: items = %+v", r.Items)
+	}
+	if r := c.call("items&with_ids=3,1,42"); len(r.Items) != 2 || r.Items[0].ID != 3 || r.Items[1].ID != 1 {
+		t.Errorf("with_ids=3,1,42: items = %+v", r.Items)
+	}
+}
+
+func TestItemsFiltered(t *testing.T) {
+	c, st, _ := newClient(t)
+	ctx := context.Background()
+
+	alice, err := st.GetUserByName(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = st.CreateFilterRule(ctx, database.CreateFilterRuleParams{
+		ID:      uuid.New(),
+		UserID:  alice.ID,
+		Action:  filter.ActionExclude,
+		Kind:    filter.KindKeyword,
+		Pattern: "two",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := c.call("items&unread_item_ids")
+	if r.TotalItems != 2 || len(r.Items) != 2 || r.Items[1].ID != 3 || *r.UnreadItemIDs != "1,3" {
+		t.Errorf("total_items = %d, items = %+v, unread = %s; want item 2 hidden", r.TotalItems, r.Items, *r.UnreadItemIDs)
+	}
+	if r := c.call("items&since_id=1"); len(r.Items) != 1 || r.Items[0].ID != 3 {
+		t.Errorf("since_id=1: items = %+v", r.Items)
+	}
+	if r := c.call("items&with_ids=2"); len(r.Items) != 0 {
+		t.Errorf("with_ids=2: items = %+v", r.Items)
+	}
+}
+
+func TestMark(t *testing.T) {
+	c, st, ids := newClient(t)
+
+	r := c.call("unread_item_ids&saved_item_ids")
+	if *r.UnreadItemIDs != "1,2,3" || *r.SavedItemIDs != "" {
+		t.Fatalf("unread = %q, saved = %q", *r.UnreadItemIDs, *r.SavedItemIDs)
+	}
+
+	// A mark answers with the ids of the state it changed.
+	r = c.call("mark=item&as=read&id=2")
+	if r.UnreadItemIDs == nil || *r.UnreadItemIDs != "1,3" {
+		t.Errorf("after marking 2 read: unread = %v", r.UnreadItemIDs)
+	}
+	post, err := st.GetPostForUser(context.Background(), database.GetPostForUserParams{
+		ID:     ids[1],
+		UserID: mustUser(t, st).ID,
+	})
+	if err != nil || !post.IsRead {
+		t.Errorf("post 2 = %+v, %v, want read", post, err)
+	}
+
+	r = c.call("mark=item&as=saved&id=3")
+	if r.SavedItemIDs == nil || *r.SavedItemIDs != "3" {
+		t.Errorf("after saving 3: saved = %v", r.SavedItemIDs)
+	}
+
+	// Items added after before stay unread.
+	r = c.call("mark=feed&as=read&id=1&before=1")
+	if *r.UnreadItemIDs != "1,3" {
+		t.Errorf("after marking the feed read before 1970: unread = %q", *r.UnreadItemIDs)
+	}
+	r = c.call("mark=group&as=read&id=0")
+	if *r.UnreadItemIDs != "" {
+		t.Errorf("after marking everything read: unread = %q", *r.UnreadItemIDs)
+	}
+
+	r = c.call("mark=item&as=unread&id=1")
+	if *r.UnreadItemIDs != "1" {
+		t.Errorf("after marking 1 unread: unread = %q", *r.UnreadItemIDs)
+	}
+}
+
+func mustUser(t *testing.T, st *memory.Store) database.User {
+	t.Helper()
+
+	user, err := st.GetUserByName(context.Background(), "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
@@ -0,0 +1,308 @@
+package fever
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lmilojevicc/gator/internal/database"
+	"github.com/lmilojevicc/gator/internal/filter"
+)
+
+type group struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+}
+
+type feedsGroup struct {
+	GroupID int64  `json:"group_id"`
+	FeedIDs string `json:"feed_ids"`
+}
+
+type feed struct {
+	ID                int64  `json:"id"`
+	FaviconID         int    `json:"favicon_id"`
+	Title             string `json:"title"`
+	URL               string `json:"url"`
+	SiteURL           string `json:"site_url"`
+	IsSpark           int    `json:"is_spark"`
+	LastUpdatedOnTime int64  `json:"last_updated_on_time"`
+}
+
+type item struct {
+	ID            int64  `json:"id"`
+	FeedID        int64  `json:"feed_id"`
+	Title         string `json:"title"`
+	Author        string `json:"author"`
+	HTML          string `json:"html"`
+	URL           string `json:"url"`
+	IsSaved       int    `json:"is_saved"`
+	IsRead        int    `json:"is_read"`
+	CreatedOnTime int64  `json:"created_on_time"`
+}
+
+// categoryGroup is the group id of the category numbered seq.
+func categoryGroup(seq int64) int64 {
+	return allGroup + seq
+}
+
+func groupList(categories []database.GetCategoriesForUserRow) []group {
+	groups := []group{{ID: allGroup, Title: "All"}}
+	for _, c := range categories {
+		groups = append(groups, group{ID: categoryGroup(c.Seq), Title: c.Name})
+	}
+	return groups
+}
+
+func feedsGroups(feeds []database.GetFeverFeedsRow, categories []database.GetCategoriesForUserRow) []feedsGroup {
+	all := make([]string, 0, len(feeds))
+	byCategory := map[int64][]string{}
+	for _, f := range feeds {
+		id := strconv.FormatInt(f.Seq, 10)
+		all = append(all, id)
+		if f.CategorySeq.Valid {
+			byCategory[f.CategorySeq.Int64] = append(byCategory[f.CategorySeq.Int64], id)
+		}
+	}
+
+	groups := []feedsGroup{{GroupID: allGroup, FeedIDs: strings.Join(all, ",")}}
+	for _, c := range categories {
+		if ids := byCategory[c.Seq]; len(ids) > 0 {
+			groups = append(groups, feedsGroup{GroupID: categoryGroup(c.Seq), FeedIDs: strings.Join(ids, ",")})
+		}
+	}
+	return groups
+}
+
+// feedList converts the followed feeds. gator does not keep the site link of
+// a feed, so site_url is the feed URL too.
+func feedList(feeds []database.GetFeverFeedsRow) []feed {
+	list := make([]feed, 0, len(feeds))
+	for _, f := range feeds {
+		list = append(list, feed{
+			ID:                f.Seq,
+			Title:             f.Name,
+			URL:               f.Url,
+			SiteURL:           f.Url,
+			LastUpdatedOnTime: unixTime(f.LastFetchedAt.Time),
+		})
+	}
+	return list
+}
+
+// items answers the items section: up to 50 items passing f after since_id,
+// before max_id, or listed in with_ids. Without any of them the oldest items
+// are returned, as with since_id=0.
+func (srv *Server) items(ctx context.Context, user database.User, f *filter.Filter, form url.Values) ([]item, error) {
+	var rows []database.GetFeverItemsAfterRow
+
+	switch {
+	case form.Has("with_ids"):
+		for _, v := range strings.Split(form.Get("with_ids"), ",") {
+			seq, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+			if err != nil {
+				continue
+			}
+			row, err := srv.item(ctx, user, seq)
+			if err == sql.ErrNoRows {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			if !f.Allows(filter.FromFeverItem(row)) {
+				continue
+			}
+			rows = append(rows, row)
+			if len(rows) == maxItems {
+				break
+			}
+		}
+
+	case form.Has("max_id"):
+		maxID, _ := strconv.ParseInt(form.Get("max_id"), 10, 64)
+		var err error
+		rows, err = allowedItems(f, maxID, func(beforeSeq int64) ([]database.GetFeverItemsAfterRow, error) {
+			before, err := srv.store.GetFeverItemsBefore(ctx, database.GetFeverItemsBeforeParams{
+				UserID:    user.ID,
+				BeforeSeq: beforeSeq,
+				MaxItems:  maxItems,
+			})
+			rows := make([]database.GetFeverItemsAfterRow, 0, len(before))
+			for _, row := range before {
+				rows = append(rows, database.GetFeverItemsAfterRow(row))
+			}
+			return rows, err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		sinceID, _ := strconv.ParseInt(form.Get("since_id"), 10, 64)
+		var err error
+		rows, err = allowedItems(f, sinceID, func(afterSeq int64) ([]database.GetFeverItemsAfterRow, error) {
+			return srv.store.GetFeverItemsAfter(ctx, database.GetFeverItemsAfterParams{
+				UserID:   user.ID,
+				AfterSeq: afterSeq,
+				MaxItems: maxItems,
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	items := make([]item, 0, len(rows))
+	for _, row := range rows {
+		createdOn := row.CreatedAt
+		if row.PublishedAt.Valid {
+			createdOn = row.PublishedAt.Time
+		}
+		items = append(items, item{
+			ID:            row.Seq,
+			FeedID:        row.FeedSeq,
+			Title:         row.Title.String,
+			HTML:          row.Description.String,
+			URL:           row.Url,
+			IsSaved:       boolInt(row.IsSaved),
+			IsRead:        boolInt(row.IsRead),
+			CreatedOnTime: unixTime(createdOn),
+		})
+	}
+	return items, nil
+}
+
+// allowedItems returns up to maxItems rows passing f. fetch returns the next
+// maxItems rows after seq in the order of its query, and is called again from
+// the last row it returned until enough rows pass or the rows run out.
+func allowedItems(f *filter.Filter, seq int64, fetch func(seq int64) ([]database.GetFeverItemsAfterRow, error)) ([]database.GetFeverItemsAfterRow, error) {
+	var rows []database.GetFeverItemsAfterRow
+	for {
+		page, err := fetch(seq)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, row := range page {
+			if !f.Allows(filter.FromFeverItem(row)) {
+				continue
+			}
+			rows = append(rows, row)
+			if len(rows) == maxItems {
+				return rows, nil
+			}
+		}
+		if len(page) < maxItems {
+			return rows, nil
+		}
+		seq = page[len(page)-1].Seq
+	}
+}
+
+// item returns the item with the given seq, or sql.ErrNoRows if user does
+// not follow its feed.
+func (srv *Server) item(ctx context.Context, user database.User, seq int64) (database.GetFeverItemsAfterRow, error) {
+	rows, err := srv.store.GetFeverItemsAfter(ctx, database.GetFeverItemsAfterParams{
+		UserID:   user.ID,
+		AfterSeq: seq - 1,
+		MaxItems: 1,
+	})
+	if err != nil {
+		return database.GetFeverItemsAfterRow{}, err
+	}
+	if len(rows) == 0 || rows[0].Seq != seq {
+		return database.GetFeverItemsAfterRow{}, sql.ErrNoRows
+	}
+	return rows[0], nil
+}
+
+// mark applies mark=item|feed|group and returns the section listing the
+// state it changed. Unknown ids are ignored, like Fever does.
+func (srv *Server) mark(ctx context.Context, user database.User, feeds []database.GetFeverFeedsRow, form url.Values) (string, error) {
+	id, err := strconv.ParseInt(form.Get("id"), 10, 64)
+	if err != nil {
+		return "", nil
+	}
+	as := form.Get("as")
+
+	switch form.Get("mark") {
+	case "item":
+		row, err := srv.item(ctx, user, id)
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		return srv.markItem(ctx, user, row, as)
+
+	case "feed", "group":
+		if as != "read" {
+			return "", nil
+		}
+
+		// feedSeqs NULL marks every feed. -1 is the Sparks group, which
+		// gator has no feeds in, so it matches none.
+		feedSeqs := []sql.NullInt64{{}}
+		switch {
+		case form.Get("mark") == "feed":
+			feedSeqs = []sql.NullInt64{{Int64: id, Valid: true}}
+		case id > allGroup:
+			feedSeqs = nil
+			for _, f := range feeds {
+				if f.CategorySeq.Valid && categoryGroup(f.CategorySeq.Int64) == id {
+					feedSeqs = append(feedSeqs, sql.NullInt64{Int64: f.Seq, Valid: true})
+				}
+			}
+		case id != 0 && id != allGroup:
+			return "", nil
+		}
+
+		// before guards against marking items the client has not seen yet.
+		before := time.Now()
+		if v, err := strconv.ParseInt(form.Get("before"), 10, 64); err == nil {
+			before = time.Unix(v, 0)
+		}
+
+		for _, feedSeq := range feedSeqs {
+			err := srv.store.MarkFeverPostsRead(ctx, database.MarkFeverPostsReadParams{
+				UserID:      user.ID,
+				FeedSeq:     feedSeq,
+				AddedBefore: before,
+			})
+			if err != nil {
+				return "", fmt.Errorf("marking posts read: %w", err)
+			}
+		}
+		return "unread_item_ids", nil
+	}
+
+	return "", nil
+}
+
+func (srv *Server) markItem(ctx context.Context, user database.User, row database.GetFeverItemsAfterRow, as string) (string, error) {
+	switch as {
+	case "read":
+		return "unread_item_ids", srv.store.MarkPostRead(ctx, database.MarkPostReadParams{UserID: user.ID, PostID: row.ID})
+	case "unread":
+		return "unread_item_ids", srv.store.MarkPostUnread(ctx, database.MarkPostUnreadParams{UserID: user.ID, PostID: row.ID})
+	case "saved":
+		return "saved_item_ids", srv.store.SavePost(ctx, database.SavePostParams{UserID: user.ID, PostID: row.ID})
+	case "unsaved":
+		_, err := srv.store.UnsavePost(ctx, database.UnsavePostParams{UserID: user.ID, PostID: row.ID})
+		return "saved_item_ids", err
+	}
+	return "", nil
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,219 @@
+// Package filter decides which posts a user sees from their include and
+// exclude rules. A post is hidden when an exclude rule matches it, or when
+// include rules cover its feed and none of them matches.
+package filter
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/lmilojevicc/gator/internal/database"
+)
+
+// What a rule does with the posts it matches.
+const (
+	ActionInclude = "include"
+	ActionExclude = "exclude"
+)
+
+var Actions = []string{ActionInclude, ActionExclude}
+
+// What a rule matches on.
+const (
+	// KindKeyword matches the title or description, ignoring case.
+	KindKeyword = "keyword"
+	// KindRegex matches the title or description with a Go regular expression.
+	KindRegex = "regex"
+	// KindAuthor matches part of the author, ignoring case.
+	KindAuthor = "author"
+	// KindCategory matches one of the categories exactly, ignoring case.
+	KindCategory = "category"
+)
+
+var Kinds = []string{KindKeyword, KindRegex, KindAuthor, KindCategory}
+
+// Post is what rules are matched against.
+type Post struct {
+	FeedID      uuid.UUID
+	Title       string
+	Description string
+	Author      string
+	Categories  []string
+}
+
+// NewPost returns the Post for the columns of a stored post. categories is
+// the categories column, which holds one per line.
+func NewPost(feedID uuid.UUID, title, description, author, categories string) Post {
+	p := Post{FeedID: feedID, Title: title, Description: description, Author: author}
+	if categories != "" {
+		p.Categories = strings.Split(categories, "\n")
+	}
+	return p
+}
+
+// FromPostsForUser returns the Post for a row of GetPostsForUser.
+func FromPostsForUser(row database.GetPostsForUserRow) Post {
+	return NewPost(row.FeedID, row.Title.String, row.Description.String, row.Author.String, row.Categories.String)
+}
+
+// FromPostsByUser returns the Post for a row of GetPostsByUser.
+func FromPostsByUser(row database.GetPostsByUserRow) Post {
+	return NewPost(row.FeedID, row.Title.String, row.Description.String, row.Author.String, row.Categories.String)
+}
+
+// FromFeverItem returns the Post for a row of GetFeverItemsAfter.
+func FromFeverItem(row database.GetFeverItemsAfterRow) Post {
+	return NewPost(row.FeedID, row.Title.String, row.Description.String, row.Author.String, row.Categories.String)
+}
+
+// FromFeverItemState returns the Post for a row of GetFeverItemStates.
+func FromFeverItemState(row database.GetFeverItemStatesRow) Post {
+	return NewPost(row.FeedID, row.Title.String, row.Description.String, row.Author.String, row.Categories.String)
+}
+
+// FromDigestPost returns the Post for a row of GetDigestPosts.
+func FromDigestPost(row database.GetDigestPostsRow) Post {
+	return NewPost(row.FeedID, row.Title.String, row.Description.String, row.Author.String, row.Categories.String)
+}
+
+type rule struct {
+	feedID  uuid.NullUUID
+	include bool
+	match   func(Post) bool
+}
+
+// Filter is a compiled set of rules. The zero Filter allows every post.
+type Filter struct {
+	rules []rule
+}
+
+// New compiles the rules of a user.
+func New(rules []database.GetFilterRulesForUserRow) (*Filter, error) {
+	f := &Filter{}
+	for _, r := range rules {
+		match, err := Matcher(r.Kind, r.Pattern)
+		if err != nil {
+			return nil, err
+		}
+		f.rules = append(f.rules, rule{
+			feedID:  r.FeedID,
+			include: r.Action == ActionInclude,
+			match:   match,
+		})
+	}
+	return f, nil
+}
+
+// Load compiles the rules of the user with userID.
+func Load(ctx context.Context, q database.Querier, userID uuid.UUID) (*Filter, error) {
+	rules, err := q.GetFilterRulesForUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("getting filter rules: %w", err)
+	}
+	return New(rules)
+}
+
+// Matcher returns the function matching posts for a rule of kind with
+// pattern, or an error if pattern is not valid for kind.
+func Matcher(kind, pattern string) (func(Post) bool, error) {
+	if pattern == "" {
+		return nil, fmt.Errorf("empty %s pattern", kind)
+	}
+
+	switch kind {
+	case KindKeyword:
+		word := strings.ToLower(pattern)
+		return func(p Post) bool {
+			return strings.Contains(strings.ToLower(p.Title), word) ||
+				strings.Contains(strings.ToLower(p.Description), word)
+		}, nil
+	case KindRegex:
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex %q: %w", pattern, err)
+		}
+		return func(p Post) bool {
+			return re.MatchString(p.Title) || re.MatchString(p.Description)
+		}, nil
+	case KindAuthor:
+		author := strings.ToLower(pattern)
+		return func(p Post) bool {
+			return strings.Contains(strings.ToLower(p.Author), author)
+		}, nil
+	case KindCategory:
+		return func(p Post) bool {
+			for _, category := range p.Categories {
+				if strings.EqualFold(category, pattern) {
+					return true
+				}
+			}
+			return false
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown rule kind %q", kind)
+	}
+}
+
+// Allows reports whether p passes the rules.
+func (f *Filter) Allows(p Post) bool {
+	hasInclude, included := false, false
+	for _, r := range f.rules {
+		if r.feedID.Valid && r.feedID.UUID != p.FeedID {
+			continue
+		}
+		if r.include {
+			hasInclude = true
+			included = included || r.match(p)
+			continue
+		}
+		if r.match(p) {
+			return false
+		}
+	}
+	return !hasInclude || included
+}
+
+// Page returns up to limit of the rows f allows, after skipping the first
+// offset allowed ones, for queries that cannot filter by themselves. fetch
+// returns the unfiltered rows with a LIMIT and OFFSET and is called with
+// growing offsets until enough rows are allowed or the rows run out. post
+// returns what a row is matched against.
+func Page[T any](f *Filter, offset, limit int, post func(T) Post, fetch func(offset, limit int) ([]T, error)) ([]T, error) {
+	if limit < 1 {
+		return nil, nil
+	}
+	if len(f.rules) == 0 {
+		return fetch(offset, limit)
+	}
+	pageSize := max(limit, 50)
+
+	var rows []T
+	skipped := 0
+	for start := 0; ; start += pageSize {
+		page, err := fetch(start, pageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, row := range page {
+			if !f.Allows(post(row)) {
+				continue
+			}
+			if skipped < offset {
+				skipped++
+				continue
+			}
+			rows = append(rows, row)
+			if len(rows) == limit {
+				return rows, nil
+			}
+		}
+		if len(page) < pageSize {
+			return rows, nil
+		}
+	}
+}
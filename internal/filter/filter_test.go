@@ -0,0 +1,135 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/lmilojevicc/gator/internal/database"
+)
+
+func TestAllows(t *testing.T) {
+	hn, blog := uuid.New(), uuid.New()
+
+	posts := map[string]Post{
+		"go":     {FeedID: hn, Title: "Go 1.26 released", Categories: []string{"Programming"}},
+		"crypto": {FeedID: hn, Title: "Show HN: yet another coin", Description: "Built on the BLOCKCHAIN"},
+		"ads":    {FeedID: hn, Title: "Hiring", Author: "Jobs Bot <jobs@example.com>"},
+		"blog":   {FeedID: blog, Title: "What I ate today", Categories: []string{"food"}},
+	}
+
+	rule := func(feedID uuid.UUID, action, kind, pattern string) database.GetFilterRulesForUserRow {
+		return database.GetFilterRulesForUserRow{
+			FeedID:  uuid.NullUUID{UUID: feedID, Valid: feedID != uuid.Nil},
+			Action:  action,
+			Kind:    kind,
+			Pattern: pattern,
+		}
+	}
+
+	tests := []struct {
+		name  string
+		rules []database.GetFilterRulesForUserRow
+		want  []string
+	}{
+		{"no rules", nil, []string{"go", "crypto", "ads", "blog"}},
+		{
+			"global excludes",
+			[]database.GetFilterRulesForUserRow{
+				rule(uuid.Nil, ActionExclude, KindKeyword, "blockchain"),
+				rule(uuid.Nil, ActionExclude, KindAuthor, "jobs bot"),
+			},
+			[]string{"go", "blog"},
+		},
+		{
+			// Include rules of one feed leave the other feeds alone.
+			"include on one feed",
+			[]database.GetFilterRulesForUserRow{rule(hn, ActionInclude, KindRegex, `\bGo\b`)},
+			[]string{"go", "blog"},
+		},
+		{
+			"category",
+			[]database.GetFilterRulesForUserRow{rule(uuid.Nil, ActionInclude, KindCategory, "FOOD")},
+			[]string{"blog"},
+		},
+		{
+			"exclude beats include",
+			[]database.GetFilterRulesForUserRow{
+				rule(uuid.Nil, ActionInclude, KindKeyword, "go"),
+				rule(uuid.Nil, ActionExclude, KindCategory, "programming"),
+			},
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		f, err := New(tt.rules)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		want := map[string]bool{}
+		for _, name := range tt.want {
+			want[name] = true
+		}
+		for name, post := range posts {
+			if got := f.Allows(post); got != want[name] {
+				t.Errorf("%s: Allows(%s) = %v, want %v", tt.name, name, got, want[name])
+			}
+		}
+	}
+}
+
+func TestMatcherValidates(t *testing.T) {
+	for _, tt := range []struct{ kind, pattern string }{
+		{KindRegex, "(unclosed"},
+		{KindKeyword, ""},
+		{"title", "go"},
+	} {
+		if _, err := Matcher(tt.kind, tt.pattern); err == nil {
+			t.Errorf("Matcher(%q, %q) succeeded", tt.kind, tt.pattern)
+		}
+	}
+}
+
+func TestPage(t *testing.T) {
+	// Rows are numbers; odd ones are hidden by their title.
+	var all []int
+	for i := range 120 {
+		all = append(all, i)
+	}
+	post := func(n int) Post {
+		if n%2 == 1 {
+			return Post{Title: "odd"}
+		}
+		return Post{Title: "even"}
+	}
+	fetches := 0
+	fetch := func(offset, limit int) ([]int, error) {
+		fetches++
+		start := min(len(all), offset)
+		return all[start:min(len(all), start+limit)], nil
+	}
+
+	f, err := New([]database.GetFilterRulesForUserRow{{Action: ActionExclude, Kind: KindKeyword, Pattern: "odd"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The offset counts allowed rows, and paging goes on past hidden ones.
+	rows, err := Page(f, 20, 30, post, fetch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 30 || rows[0] != 40 || rows[29] != 98 {
+		t.Errorf("rows = %v, want 40 to 98", rows)
+	}
+	if rows, _ := Page(f, 55, 10, post, fetch); len(rows) != 5 || rows[4] != 118 {
+		t.Errorf("last page = %v, want 110 to 118", rows)
+	}
+
+	// Without rules the query pages by itself.
+	fetches = 0
+	if rows, _ := Page(&Filter{}, 20, 30, post, fetch); len(rows) != 30 || rows[0] != 20 || fetches != 1 {
+		t.Errorf("unfiltered rows = %v after %d fetches, want 20 to 49 in one", rows, fetches)
+	}
+}
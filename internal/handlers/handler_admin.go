@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/lmilojevicc/gator/internal/cli"
+	"github.com/lmilojevicc/gator/internal/database"
+	"github.com/lmilojevicc/gator/internal/state"
+)
+
+func HandlerPromote(s *state.State, cmd cli.Command, dbUser database.User) error {
+	return setAdmin(s, cmd, true)
+}
+
+func HandlerDemote(s *state.State, cmd cli.Command, dbUser database.User) error {
+	return setAdmin(s, cmd, false)
+}
+
+func setAdmin(s *state.State, cmd cli.Command, isAdmin bool) error {
+	if len(cmd.Arguments) != 1 {
+		return fmt.Errorf("usage: %s <username>", cmd.Name)
+	}
+
+	target, err := s.Store.GetUserByName(context.Background(), cmd.Arguments[0])
+	if err != nil {
+		return fmt.Errorf("getting user: %w", err)
+	}
+
+	if isAdmin && !target.PasswordHash.Valid {
+		return fmt.Errorf("%s has no password, anyone could log in as them", target.Name)
+	}
+
+	if !isAdmin && target.IsAdmin {
+		if err := checkNotLastAdmin(s, target); err != nil {
+			return err
+		}
+	}
+
+	err = s.Store.SetUserAdmin(context.Background(), database.SetUserAdminParams{
+		ID:      target.ID,
+		IsAdmin: isAdmin,
+	})
+	if err != nil {
+		return fmt.Errorf("updating user: %w", err)
+	}
+
+	if isAdmin {
+		fmt.Printf("%s is now an admin\n", target.Name)
+	} else {
+		fmt.Printf("%s is no longer an admin\n", target.Name)
+	}
+
+	return nil
+}
+
+func HandlerDeleteUser(s *state.State, cmd cli.Command, dbUser database.User) error {
+	flags := flag.NewFlagSet(cmd.Name, flag.ContinueOnError)
+	yes := flags.Bool("yes", false, "do not ask for confirmation")
+	if err := flags.Parse(cmd.Arguments); err != nil || flags.NArg() != 1 {
+		return fmt.Errorf("usage: %s [--yes] <username>", cmd.Name)
+	}
+
+	target, err := s.Store.GetUserByName(context.Background(), flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("getting user: %w", err)
+	}
+
+	if target.IsAdmin {
+		if err := checkNotLastAdmin(s, target); err != nil {
+			return err
+		}
+	}
+
+	if !*yes {
+		prompt := fmt.Sprintf("This deletes %s along with the feeds they added and everything they saved.", target.Name)
+		ok, err := confirmTyped(prompt, target.Name)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Aborted")
+			return nil
+		}
+	}
+
+	err = s.Store.DeleteUser(context.Background(), target.ID)
+	if err != nil {
+		return fmt.Errorf("deleting user: %w", err)
+	}
+
+	fmt.Printf("User %s deleted\n", target.Name)
+
+	return nil
+}
+
+// checkNotLastAdmin refuses to take away the admin role of the only admin
+// left, which would lock everyone out of the admin commands.
+func checkNotLastAdmin(s *state.State, target database.User) error {
+	admins, err := s.Store.CountAdmins(context.Background())
+	if err != nil {
+		return fmt.Errorf("counting admins: %w", err)
+	}
+
+	if admins <= 1 {
+		return fmt.Errorf("%s is the last admin, promote someone else first", target.Name)
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lmilojevicc/gator/internal/cli"
+)
+
+func TestFirstUserIsAdmin(t *testing.T) {
+	s := newTestState(t, nil)
+
+	alice := createUser(t, s, "alice")
+	bob := createUser(t, s, "bob")
+
+	if !alice.IsAdmin || bob.IsAdmin {
+		t.Errorf("alice admin = %v, bob admin = %v; want only alice", alice.IsAdmin, bob.IsAdmin)
+	}
+}
+
+func TestPromoteAndDemote(t *testing.T) {
+	s := newTestState(t, nil)
+	alice := createUser(t, s, "alice")
+	bob := createUser(t, s, "bob")
+	carol := createUser(t, s, "carol")
+	setPassword(t, s, bob)
+
+	if err := HandlerPromote(s, cli.Command{Name: "promote", Arguments: []string{carol.Name}}, alice); err == nil {
+		t.Error("promoting a user without a password succeeded")
+	}
+
+	if err := HandlerDemote(s, cli.Command{Name: "demote", Arguments: []string{"alice"}}, alice); err == nil {
+		t.Error("demoting the last admin succeeded")
+	}
+
+	if err := HandlerPromote(s, cli.Command{Name: "promote", Arguments: []string{"bob"}}, alice); err != nil {
+		t.Fatalf("promote: %v", err)
+	}
+	if err := HandlerDemote(s, cli.Command{Name: "demote", Arguments: []string{"alice"}}, alice); err != nil {
+		t.Fatalf("demote: %v", err)
+	}
+
+	for name, want := range map[string]bool{"alice": false, "bob": true} {
+		user, err := s.Store.GetUserByName(context.Background(), name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if user.IsAdmin != want {
+			t.Errorf("%s admin = %v, want %v", name, user.IsAdmin, want)
+		}
+	}
+}
+
+func TestDeleteUser(t *testing.T) {
+	s := newTestState(t, nil)
+	alice := createUser(t, s, "alice")
+	bob := createUser(t, s, "bob")
+
+	if err := HandlerAddFeed(s, cli.Command{Arguments: []string{"Bob's", "https://example.com/bob.xml"}}, bob); err != nil {
+		t.Fatal(err)
+	}
+
+	answerPrompts(t, "alice\n")
+	if err := HandlerDeleteUser(s, cli.Command{Name: "deleteuser", Arguments: []string{"bob"}}, alice); err != nil {
+		t.Fatalf("deleteuser: %v", err)
+	}
+	if _, err := s.Store.GetUserByName(context.Background(), "bob"); err != nil {
+		t.Error("bob was deleted although the confirmation did not match")
+	}
+
+	if err := HandlerDeleteUser(s, cli.Command{Name: "deleteuser", Arguments: []string{"alice"}}, alice); err == nil {
+		t.Error("deleting the last admin succeeded")
+	}
+
+	if err := HandlerDeleteUser(s, cli.Command{Name: "deleteuser", Arguments: []string{"--yes", "bob"}}, alice); err != nil {
+		t.Fatalf("deleteuser --yes: %v", err)
+	}
+	if _, err := s.Store.GetUserByName(context.Background(), "bob"); err == nil {
+		t.Error("bob still exists")
+	}
+	if _, err := s.Store.GetFeedByURL(context.Background(), "https://example.com/bob.xml"); err == nil {
+		t.Error("bob's feed was not deleted with him")
+	}
+}
+
+func TestReset(t *testing.T) {
+	s := newTestState(t, nil)
+	alice := createUser(t, s, "alice")
+
+	answerPrompts(t, "yes\n")
+	if err := HandlerReset(s, cli.Command{Name: "reset"}, alice); err != nil {
+		t.Fatalf("reset: %v", err)
+	}
+	if users, _ := s.Store.GetUsers(context.Background()); len(users) != 1 {
+		t.Fatal("reset ran without the typed confirmation")
+	}
+
+	answerPrompts(t, "reset\n")
+	if err := HandlerReset(s, cli.Command{Name: "reset"}, alice); err != nil {
+		t.Fatalf("reset: %v", err)
+	}
+	if users, _ := s.Store.GetUsers(context.Background()); len(users) != 0 {
+		t.Errorf("%d users left after reset", len(users))
+	}
+}
+
+func TestDeleteFeedAsAdmin(t *testing.T) {
+	s := newTestState(t, nil)
+	alice := createUser(t, s, "alice")
+	bob := createUser(t, s, "bob")
+	carol := createUser(t, s, "carol")
+
+	feedURL := "https://example.com/bob.xml"
+	if err := HandlerAddFeed(s, cli.Command{Arguments: []string{"Bob's", feedURL}}, bob); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := HandlerDeleteFeed(s, cli.Command{Name: "deletefeed", Arguments: []string{feedURL}}, carol); err == nil {
+		t.Error("a non-admin deleted someone else's feed")
+	}
+	if err := HandlerDeleteFeed(s, cli.Command{Name: "deletefeed", Arguments: []string{"--yes", feedURL}}, alice); err == nil {
+		t.Error("an admin without a password deleted someone else's feed")
+	}
+
+	alice = setPassword(t, s, alice)
+
+	if err := HandlerDeleteFeed(s, cli.Command{Name: "deletefeed", Arguments: []string{"--yes", feedURL}}, alice); err != nil {
+		t.Fatalf("deletefeed as admin: %v", err)
+	}
+	if _, err := s.Store.GetFeedByURL(context.Background(), feedURL); err == nil {
+		t.Error("feed still exists")
+	}
+}
+
+func TestMigrateDownNeedsAdmin(t *testing.T) {
+	s := newTestState(t, nil)
+	createUser(t, s, "alice")
+
+	if err := HandlerMigrate(s, cli.Command{Name: "migrate", Arguments: []string{"down"}}); err == nil {
+		t.Error("migrate down ran without an admin session")
+	}
+}
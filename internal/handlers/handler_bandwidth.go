@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lmilojevicc/gator/internal/cli"
+	"github.com/lmilojevicc/gator/internal/state"
+)
+
+func HandlerBandwidth(s *state.State, cmd cli.Command) error {
+	if len(cmd.Arguments) > 1 {
+		return fmt.Errorf("usage: %s [period]", cmd.Name)
+	}
+
+	period := 7 * 24 * time.Hour
+	if len(cmd.Arguments) == 1 {
+		parsed, err := time.ParseDuration(cmd.Arguments[0])
+		if err != nil {
+			return fmt.Errorf("invalid duration format (use 24h, 168h, etc...): %w", err)
+		}
+		period = parsed
+	}
+
+	rows, err := s.Store.GetFeedBandwidth(context.Background(), time.Now().Add(-period))
+	if err != nil {
+		return fmt.Errorf("getting feed bandwidth: %w", err)
+	}
+
+	if len(rows) == 0 {
+		fmt.Printf("No fetches recorded in the last %s\n", period)
+		return nil
+	}
+
+	var totalCompressed, totalUncompressed int64
+	for _, row := range rows {
+		fmt.Printf("* %s\n", row.Name)
+		fmt.Printf("  %d fetches, %s transferred, %s uncompressed\n",
+			row.Fetches, formatBytes(row.CompressedBytes), formatBytes(row.UncompressedBytes))
+		totalCompressed += row.CompressedBytes
+		totalUncompressed += row.UncompressedBytes
+	}
+
+	fmt.Printf("\nTotal: %s transferred, %s uncompressed\n", formatBytes(totalCompressed), formatBytes(totalUncompressed))
+
+	return nil
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/lmilojevicc/gator/internal/cli"
+	"github.com/lmilojevicc/gator/internal/database"
+	"github.com/lmilojevicc/gator/internal/state"
+)
+
+const categoryUsage = `usage:
+  %[1]s add <name>
+  %[1]s list
+  %[1]s rename <name> <new_name>
+  %[1]s remove <name>
+  %[1]s assign <feed_url> <name>
+  %[1]s unassign <feed_url>`
+
+// HandlerCategory manages the categories the logged in user sorts the feeds
+// they follow into. A feed is in at most one category.
+func HandlerCategory(s *state.State, cmd cli.Command, dbUser database.User) error {
+	if len(cmd.Arguments) == 0 {
+		return fmt.Errorf(categoryUsage, cmd.Name)
+	}
+
+	args := cmd.Arguments[1:]
+	switch {
+	case cmd.Arguments[0] == "add" && len(args) == 1:
+		return addCategory(s, dbUser, args[0])
+	case cmd.Arguments[0] == "list" && len(args) == 0:
+		return listCategories(s, dbUser)
+	case cmd.Arguments[0] == "rename" && len(args) == 2:
+		return renameCategory(s, dbUser, args[0], args[1])
+	case cmd.Arguments[0] == "remove" && len(args) == 1:
+		return removeCategory(s, dbUser, args[0])
+	case cmd.Arguments[0] == "assign" && len(args) == 2:
+		return assignCategory(s, dbUser, args[0], args[1])
+	case cmd.Arguments[0] == "unassign" && len(args) == 1:
+		return assignCategory(s, dbUser, args[0], "")
+	default:
+		return fmt.Errorf(categoryUsage, cmd.Name)
+	}
+}
+
+func addCategory(s *state.State, dbUser database.User, name string) error {
+	if name == "" {
+		return fmt.Errorf("category name cannot be empty")
+	}
+
+	category, err := s.Store.CreateCategory(context.Background(), database.CreateCategoryParams{
+		ID:     uuid.New(),
+		UserID: dbUser.ID,
+		Name:   name,
+	})
+	if err != nil {
+		return fmt.Errorf("creating category %q: %w", name, err)
+	}
+
+	fmt.Printf("Added category %q\n", category.Name)
+
+	return nil
+}
+
+func listCategories(s *state.State, dbUser database.User) error {
+	categories, err := s.Store.GetCategoriesForUser(context.Background(), dbUser.ID)
+	if err != nil {
+		return fmt.Errorf("getting categories: %w", err)
+	}
+
+	if len(categories) == 0 {
+		fmt.Println("You have no categories")
+		return nil
+	}
+
+	for _, category := range categories {
+		fmt.Printf("* %s (%d feeds)\n", category.Name, category.FeedCount)
+	}
+
+	return nil
+}
+
+func renameCategory(s *state.State, dbUser database.User, name, newName string) error {
+	if newName == "" {
+		return fmt.Errorf("category name cannot be empty")
+	}
+
+	category, err := categoryByName(s, dbUser, name)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Store.RenameCategory(context.Background(), database.RenameCategoryParams{
+		ID:     category.ID,
+		UserID: dbUser.ID,
+		Name:   newName,
+	})
+	if err != nil {
+		return fmt.Errorf("renaming category %q: %w", name, err)
+	}
+
+	fmt.Printf("Renamed category %q to %q\n", name, newName)
+
+	return nil
+}
+
+func removeCategory(s *state.State, dbUser database.User, name string) error {
+	category, err := categoryByName(s, dbUser, name)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Store.DeleteCategory(context.Background(), database.DeleteCategoryParams{
+		ID:     category.ID,
+		UserID: dbUser.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("removing category %q: %w", name, err)
+	}
+
+	fmt.Printf("Removed category %q, its feeds are still followed\n", name)
+
+	return nil
+}
+
+// assignCategory moves the followed feed at feedURL into the category name,
+// or out of its category if name is empty.
+func assignCategory(s *state.State, dbUser database.User, feedURL, name string) error {
+	dbFeed, err := followedFeed(s, dbUser, feedURL)
+	if err != nil {
+		return err
+	}
+
+	var categoryID uuid.NullUUID
+	if name != "" {
+		category, err := categoryByName(s, dbUser, name)
+		if err != nil {
+			return err
+		}
+		categoryID = uuid.NullUUID{UUID: category.ID, Valid: true}
+	}
+
+	_, err = s.Store.SetFollowCategory(context.Background(), database.SetFollowCategoryParams{
+		CategoryID: categoryID,
+		UserID:     dbUser.ID,
+		FeedID:     dbFeed.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("setting category of %q: %w", dbFeed.Name, err)
+	}
+
+	if name == "" {
+		fmt.Printf("%q is no longer in a category\n", dbFeed.Name)
+	} else {
+		fmt.Printf("%q is now in %q\n", dbFeed.Name, name)
+	}
+
+	return nil
+}
+
+func categoryByName(s *state.State, dbUser database.User, name string) (database.Category, error) {
+	category, err := s.Store.GetCategoryByName(context.Background(), database.GetCategoryByNameParams{
+		UserID: dbUser.ID,
+		Name:   name,
+	})
+	if err == sql.ErrNoRows {
+		return database.Category{}, fmt.Errorf("no category named %q", name)
+	}
+	if err != nil {
+		return database.Category{}, fmt.Errorf("getting category %q: %w", name, err)
+	}
+	return category, nil
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/lmilojevicc/gator/internal/cli"
+	"github.com/lmilojevicc/gator/internal/database"
+)
+
+func TestCategories(t *testing.T) {
+	goSrv := feedServer(t, http.StatusOK, testFeed)
+	hnSrv := feedServer(t, http.StatusOK, filterFeed)
+	s := newTestState(t, nil)
+
+	alice := createUser(t, s, "alice")
+	for _, feed := range [][]string{{"Test", goSrv.URL}, {"HN", hnSrv.URL}} {
+		if err := HandlerAddFeed(s, cli.Command{Name: "addfeed", Arguments: feed}, alice); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for range 2 {
+		if err := scrapeFeeds(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	categoryCmd := func(args ...string) error {
+		return HandlerCategory(s, cli.Command{Name: "category", Arguments: args}, alice)
+	}
+	groups := func() map[string][]string {
+		t.Helper()
+		follows, err := s.Store.GetFeedFollowsForUser(context.Background(), alice.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := map[string][]string{}
+		for _, group := range followGroups(follows) {
+			for _, follow := range group.follows {
+				got[group.category] = append(got[group.category], follow.FeedName)
+			}
+		}
+		return got
+	}
+
+	for _, args := range [][]string{{"add", "News"}, {"add", "Tech"}, {"assign", hnSrv.URL, "News"}, {"assign", goSrv.URL, "Tech"}} {
+		if err := categoryCmd(args...); err != nil {
+			t.Fatalf("category %v: %v", args, err)
+		}
+	}
+	for _, args := range [][]string{
+		{"add", "Tech"},
+		{"assign", hnSrv.URL, "Unknown"},
+		{"assign", "https://example.com/unknown", "Tech"},
+		{"rename", "Unknown", "Other"},
+		{"list", "extra"},
+	} {
+		if err := categoryCmd(args...); err == nil {
+			t.Errorf("category %v succeeded", args)
+		}
+	}
+
+	if got := groups(); len(got) != 2 || got["News"][0] != "HN" || got["Tech"][0] != "Test" {
+		t.Errorf("groups = %v, want HN in News and Test in Tech", got)
+	}
+
+	news, err := categoryByName(s, alice, "News")
+	if err != nil {
+		t.Fatal(err)
+	}
+	posts, err := filteredPosts(s, database.GetPostsForUserParams{
+		UserID:     alice.ID,
+		CategoryID: uuid.NullUUID{UUID: news.ID, Valid: true},
+	}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(posts) != 3 || posts[0].FeedName != "HN" {
+		t.Errorf("posts in News = %+v, want the 3 HN posts", posts)
+	}
+	if err := HandlerBrowse(s, cli.Command{Name: "browse", Arguments: []string{"--category", "Unknown"}}, alice); err == nil {
+		t.Error("browse of an unknown category succeeded")
+	}
+
+	// Renaming keeps the feeds; removing a category or unassigning a feed
+	// keeps following it.
+	for _, args := range [][]string{{"rename", "Tech", "Go"}, {"remove", "News"}} {
+		if err := categoryCmd(args...); err != nil {
+			t.Fatalf("category %v: %v", args, err)
+		}
+	}
+	if got := groups(); len(got) != 2 || got["Go"][0] != "Test" || got[""][0] != "HN" {
+		t.Errorf("groups = %v, want Test in Go and HN uncategorized", got)
+	}
+	if err := categoryCmd("unassign", goSrv.URL); err != nil {
+		t.Fatal(err)
+	}
+	if got := groups(); len(got) != 1 || len(got[""]) != 2 {
+		t.Errorf("groups = %v, want both feeds uncategorized", got)
+	}
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"net/mail"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/lmilojevicc/gator/internal/cli"
+	"github.com/lmilojevicc/gator/internal/database"
+	"github.com/lmilojevicc/gator/internal/digest"
+	"github.com/lmilojevicc/gator/internal/state"
+)
+
+// HandlerDigest mails every subscriber whose digest is due the posts added
+// since their previous one. It is meant to run from cron; --dry-run prints
+// the digests instead of sending them.
+func HandlerDigest(s *state.State, cmd cli.Command) error {
+	flags := flag.NewFlagSet(cmd.Name, flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "print the digests instead of sending them")
+	html := flags.Bool("html", false, "print the HTML version with --dry-run")
+	if err := flags.Parse(cmd.Arguments); err != nil || flags.NArg() != 0 {
+		return fmt.Errorf("usage: %s [--dry-run [--html]]", cmd.Name)
+	}
+
+	var mailer *digest.Mailer
+	if !*dryRun {
+		var err error
+		mailer, err = digest.NewMailer(s.Cfg.SMTP)
+		if err != nil {
+			return err
+		}
+	}
+
+	users, err := s.Store.GetDigestSubscribers(context.Background())
+	if err != nil {
+		return fmt.Errorf("getting digest subscribers: %w", err)
+	}
+
+	now := time.Now()
+	failed := 0
+	for _, user := range users {
+		if !digest.Due(user, now) {
+			continue
+		}
+
+		d, err := digest.Build(context.Background(), s.Store, user, now)
+		if err != nil {
+			return fmt.Errorf("building digest for %s: %w", user.Name, err)
+		}
+
+		if *dryRun {
+			if err := printDigest(d, *html); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Without new posts nothing is sent, but the next digest still
+		// waits a full day or week.
+		if d.PostCount() > 0 {
+			if err := mailer.Send(d); err != nil {
+				fmt.Fprintf(os.Stderr, "Error sending digest to %s: %v\n", user.Name, err)
+				failed++
+				continue
+			}
+			fmt.Printf("Sent %s digest with %d posts to %s\n", user.DigestFrequency.String, d.PostCount(), user.Email.String)
+		}
+
+		// A digest that was cut short leaves the seq of its last post for
+		// the next one to start after.
+		err = s.Store.SetUserLastDigest(context.Background(), database.SetUserLastDigestParams{
+			ID:            user.ID,
+			LastDigestAt:  sql.NullTime{Time: now, Valid: true},
+			LastDigestSeq: sql.NullInt64{Int64: d.LastSeq, Valid: d.Truncated},
+		})
+		if err != nil {
+			return fmt.Errorf("recording digest for %s: %w", user.Name, err)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d digests could not be sent", failed)
+	}
+
+	return nil
+}
+
+func printDigest(d digest.Digest, html bool) error {
+	fmt.Printf("To: %s <%s>\n", d.User.Name, d.User.Email.String)
+	if d.PostCount() == 0 {
+		fmt.Print("No new posts, nothing would be sent\n\n")
+		return nil
+	}
+	fmt.Printf("Subject: %s\n\n", d.Subject())
+
+	render := d.Text
+	if html {
+		render = d.HTML
+	}
+	body, err := render()
+	if err != nil {
+		return err
+	}
+	fmt.Println(body)
+
+	return nil
+}
+
+// HandlerSetDigest subscribes the logged in user to a daily or weekly digest
+// sent to email.
+func HandlerSetDigest(s *state.State, cmd cli.Command, dbUser database.User) error {
+	if len(cmd.Arguments) != 2 || !slices.Contains(digest.Frequencies, cmd.Arguments[0]) {
+		return fmt.Errorf("usage: %s <daily|weekly> <email>", cmd.Name)
+	}
+
+	frequency := cmd.Arguments[0]
+	addr, err := mail.ParseAddress(cmd.Arguments[1])
+	if err != nil {
+		return fmt.Errorf("invalid email address %q", cmd.Arguments[1])
+	}
+
+	err = s.Store.SetUserDigest(context.Background(), database.SetUserDigestParams{
+		ID:              dbUser.ID,
+		Email:           sql.NullString{String: addr.Address, Valid: true},
+		DigestFrequency: sql.NullString{String: frequency, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("setting digest: %w", err)
+	}
+
+	fmt.Printf("You will get a %s digest at %s\n", frequency, addr.Address)
+
+	return nil
+}
+
+// HandlerClearDigest unsubscribes the logged in user from the digest.
+func HandlerClearDigest(s *state.State, cmd cli.Command, dbUser database.User) error {
+	if len(cmd.Arguments) != 0 {
+		return fmt.Errorf("usage: %s", cmd.Name)
+	}
+
+	err := s.Store.SetUserDigest(context.Background(), database.SetUserDigestParams{
+		ID:    dbUser.ID,
+		Email: dbUser.Email,
+	})
+	if err != nil {
+		return fmt.Errorf("clearing digest: %w", err)
+	}
+
+	fmt.Println("Digest turned off")
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/lmilojevicc/gator/internal/cli"
+	"github.com/lmilojevicc/gator/internal/config"
+	"github.com/lmilojevicc/gator/internal/smtptest"
+)
+
+func TestDigest(t *testing.T) {
+	srv := feedServer(t, http.StatusOK, testFeed)
+	s := newTestState(t, srv)
+	smtp := smtptest.NewServer(t)
+	s.Cfg.SMTP = config.SMTPConfig{Host: smtp.Host, Port: smtp.Port, From: "gator@example.com"}
+
+	alice := createUser(t, s, "alice")
+	if err := HandlerAddFeed(s, cli.Command{Name: "addfeed", Arguments: []string{"Test", srv.URL}}, alice); err != nil {
+		t.Fatal(err)
+	}
+	if err := scrapeFeeds(s); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, args := range [][]string{{"hourly", "alice@example.com"}, {"daily", "not an address"}} {
+		if err := HandlerSetDigest(s, cli.Command{Name: "setdigest", Arguments: args}, alice); err == nil {
+			t.Errorf("setdigest %v succeeded", args)
+		}
+	}
+	if err := HandlerSetDigest(s, cli.Command{Name: "setdigest", Arguments: []string{"daily", "Alice <alice@example.com>"}}, alice); err != nil {
+		t.Fatal(err)
+	}
+
+	digestCmd := cli.Command{Name: "digest"}
+	if err := HandlerDigest(s, cli.Command{Name: "digest", Arguments: []string{"--dry-run"}}); err != nil {
+		t.Fatal(err)
+	}
+	if len(smtp.Messages()) != 0 {
+		t.Fatal("dry run sent a message")
+	}
+
+	if err := HandlerDigest(s, digestCmd); err != nil {
+		t.Fatal(err)
+	}
+	messages := smtp.Messages()
+	if len(messages) != 1 || messages[0].To[0] != "alice@example.com" {
+		t.Fatalf("messages = %+v, want one to alice", messages)
+	}
+
+	// The next digest is due tomorrow.
+	if err := HandlerDigest(s, digestCmd); err != nil {
+		t.Fatal(err)
+	}
+	if len(smtp.Messages()) != 1 {
+		t.Error("digest was sent twice on the same day")
+	}
+
+	user, err := s.Store.GetUserByID(context.Background(), alice.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !user.LastDigestAt.Valid {
+		t.Error("last_digest_at was not recorded")
+	}
+
+	if err := HandlerClearDigest(s, cli.Command{Name: "cleardigest"}, user); err != nil {
+		t.Fatal(err)
+	}
+	subscribers, err := s.Store.GetDigestSubscribers(context.Background())
+	if err != nil || len(subscribers) != 0 {
+		t.Errorf("subscribers after cleardigest = %+v, %v", subscribers, err)
+	}
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/lmilojevicc/gator/internal/auth"
+	"github.com/lmilojevicc/gator/internal/cli"
+	"github.com/lmilojevicc/gator/internal/database"
+	"github.com/lmilojevicc/gator/internal/state"
+)
+
+func HandlerRenameFeed(s *state.State, cmd cli.Command, dbUser database.User) error {
+	if len(cmd.Arguments) != 2 {
+		return fmt.Errorf("usage: %s <url> <new_name>", cmd.Name)
+	}
+
+	feedURL := cmd.Arguments[0]
+	newName := cmd.Arguments[1]
+
+	dbFeed, err := s.Store.GetFeedByURL(context.Background(), feedURL)
+	if err != nil {
+		return fmt.Errorf("getting feed by url: %w", err)
+	}
+
+	if err := checkFeedOwner(dbFeed, dbUser, "rename"); err != nil {
+		return err
+	}
+
+	renamedFeed, err := s.Store.RenameFeed(context.Background(), database.RenameFeedParams{
+		ID:   dbFeed.ID,
+		Name: newName,
+	})
+	if err != nil {
+		return fmt.Errorf("renaming feed: %w", err)
+	}
+
+	fmt.Printf("Feed %q renamed to %q\n", dbFeed.Name, renamedFeed.Name)
+
+	return nil
+}
+
+func HandlerSetFeedURL(s *state.State, cmd cli.Command, dbUser database.User) error {
+	if len(cmd.Arguments) != 2 {
+		return fmt.Errorf("usage: %s <url> <new_url>", cmd.Name)
+	}
+
+	feedURL := cmd.Arguments[0]
+	newURL := cmd.Arguments[1]
+
+	dbFeed, err := s.Store.GetFeedByURL(context.Background(), feedURL)
+	if err != nil {
+		return fmt.Errorf("getting feed by url: %w", err)
+	}
+
+	if err := checkFeedOwner(dbFeed, dbUser, "re-point"); err != nil {
+		return err
+	}
+
+	auth, err := feedAuth(s.Store, dbFeed.ID, s.Cfg.AllowedSecrets)
+	if err != nil {
+		return err
+	}
+
+	resolvedURL, err := s.Fetcher.ResolveURL(context.Background(), newURL, auth)
+	if err != nil {
+		return fmt.Errorf("resolving %s: %w", newURL, err)
+	}
+	if resolvedURL != newURL {
+		fmt.Printf("%s permanently redirects to %s\n", newURL, resolvedURL)
+	}
+
+	updatedFeed, err := s.Store.UpdateFeedURL(context.Background(), database.UpdateFeedURLParams{
+		ID:  dbFeed.ID,
+		Url: resolvedURL,
+	})
+	if err != nil {
+		return fmt.Errorf("updating feed url: %w", err)
+	}
+
+	err = s.Store.CreateFeedHistory(context.Background(), database.CreateFeedHistoryParams{
+		ID:     uuid.New(),
+		FeedID: dbFeed.ID,
+		Event:  "url_changed",
+		Detail: fmt.Sprintf("%s -> %s set by %s", dbFeed.Url, updatedFeed.Url, dbUser.Name),
+	})
+	if err != nil {
+		return fmt.Errorf("recording feed history: %w", err)
+	}
+
+	fmt.Printf("Feed %q now points to %s\n", updatedFeed.Name, updatedFeed.Url)
+
+	return nil
+}
+
+func HandlerDeleteFeed(s *state.State, cmd cli.Command, dbUser database.User) error {
+	flags := flag.NewFlagSet(cmd.Name, flag.ContinueOnError)
+	yes := flags.Bool("yes", false, "do not ask for confirmation")
+	if err := flags.Parse(cmd.Arguments); err != nil || flags.NArg() != 1 {
+		return fmt.Errorf("usage: %s [--yes] <url>", cmd.Name)
+	}
+
+	feedURL := flags.Arg(0)
+
+	dbFeed, err := s.Store.GetFeedByURL(context.Background(), feedURL)
+	if err != nil {
+		return fmt.Errorf("getting feed by url: %w", err)
+	}
+
+	if err := checkFeedOwner(dbFeed, dbUser, "delete"); err != nil {
+		return err
+	}
+
+	followers, err := s.Store.CountOtherFeedFollowers(context.Background(), database.CountOtherFeedFollowersParams{
+		FeedID: dbFeed.ID,
+		UserID: dbUser.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("counting feed followers: %w", err)
+	}
+
+	if followers > 0 && !*yes {
+		ok, err := confirm(fmt.Sprintf("%q is followed by %d other user(s). Delete it anyway?", dbFeed.Name, followers))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Aborted")
+			return nil
+		}
+	}
+
+	err = s.Store.DeleteFeed(context.Background(), dbFeed.ID)
+	if err != nil {
+		return fmt.Errorf("deleting feed: %w", err)
+	}
+
+	fmt.Printf("Feed %q deleted\n", dbFeed.Name)
+
+	return nil
+}
+
+// checkFeedOwner refuses changes to a feed, which every follower sees, from
+// anyone but the user who added it or an admin.
+func checkFeedOwner(dbFeed database.Feed, dbUser database.User, action string) error {
+	if dbFeed.UserID != dbUser.ID && !auth.IsAdmin(dbUser) {
+		return fmt.Errorf("only the user who added %q or an admin can %s it", dbFeed.Name, action)
+	}
+	return nil
+}
+
+func HandlerFeedHistory(s *state.State, cmd cli.Command) error {
+	if len(cmd.Arguments) != 1 {
+		return fmt.Errorf("usage: %s <url>", cmd.Name)
+	}
+
+	feedURL := cmd.Arguments[0]
+
+	dbFeed, err := s.Store.GetFeedByURL(context.Background(), feedURL)
+	if err != nil {
+		return fmt.Errorf("getting feed by url: %w", err)
+	}
+
+	history, err := s.Store.GetFeedHistory(context.Background(), dbFeed.ID)
+	if err != nil {
+		return fmt.Errorf("getting feed history: %w", err)
+	}
+
+	if len(history) == 0 {
+		fmt.Printf("No history recorded for %q\n", dbFeed.Name)
+		return nil
+	}
+
+	for _, entry := range history {
+		fmt.Printf("%s  %-12s %s\n", entry.CreatedAt.Format("2006-01-02 15:04"), entry.Event, entry.Detail)
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/lmilojevicc/gator/internal/cli"
+	"github.com/lmilojevicc/gator/internal/database"
+	"github.com/lmilojevicc/gator/internal/rss"
+	"github.com/lmilojevicc/gator/internal/secret"
+	"github.com/lmilojevicc/gator/internal/state"
+)
+
+const feedAuthUsage = `usage:
+  %[1]s <url>
+  %[1]s <url> basic <username> <secret_ref>
+  %[1]s <url> bearer <secret_ref>
+  %[1]s <url> header <name> <secret_ref>
+secret_ref is env:NAME or file:/path/to/secret and must match allowed_secrets`
+
+func HandlerFeedAuth(s *state.State, cmd cli.Command, dbUser database.User) error {
+	if len(cmd.Arguments) == 0 {
+		return fmt.Errorf(feedAuthUsage, cmd.Name)
+	}
+
+	dbFeed, err := s.Store.GetFeedByURL(context.Background(), cmd.Arguments[0])
+	if err != nil {
+		return fmt.Errorf("getting feed by url: %w", err)
+	}
+
+	if len(cmd.Arguments) == 1 {
+		return printFeedCredentials(s, dbFeed)
+	}
+
+	if dbFeed.UserID != dbUser.ID {
+		return fmt.Errorf("only the user who added %q can change its credentials", dbFeed.Name)
+	}
+
+	params := database.SetFeedCredentialParams{
+		ID:     uuid.New(),
+		FeedID: dbFeed.ID,
+		Kind:   cmd.Arguments[1],
+	}
+
+	args := cmd.Arguments[2:]
+	switch {
+	case params.Kind == "basic" && len(args) == 2:
+		params.Username = sql.NullString{String: args[0], Valid: true}
+		params.SecretRef = args[1]
+	case params.Kind == "bearer" && len(args) == 1:
+		params.SecretRef = args[0]
+	case params.Kind == "header" && len(args) == 2:
+		params.HeaderName = http.CanonicalHeaderKey(args[0])
+		params.SecretRef = args[1]
+	default:
+		return fmt.Errorf(feedAuthUsage, cmd.Name)
+	}
+
+	if err := secret.Allowed(params.SecretRef, s.Cfg.AllowedSecrets); err != nil {
+		return err
+	}
+
+	if params.Kind != "header" {
+		err = s.Store.DeleteFeedAuthorization(context.Background(), dbFeed.ID)
+		if err != nil {
+			return fmt.Errorf("replacing feed authorization: %w", err)
+		}
+	}
+
+	err = s.Store.SetFeedCredential(context.Background(), params)
+	if err != nil {
+		return fmt.Errorf("setting feed credential: %w", err)
+	}
+
+	fmt.Printf("Credentials for %q updated\n", dbFeed.Name)
+
+	return nil
+}
+
+func HandlerClearFeedAuth(s *state.State, cmd cli.Command, dbUser database.User) error {
+	if len(cmd.Arguments) != 1 {
+		return fmt.Errorf("usage: %s <url>", cmd.Name)
+	}
+
+	dbFeed, err := s.Store.GetFeedByURL(context.Background(), cmd.Arguments[0])
+	if err != nil {
+		return fmt.Errorf("getting feed by url: %w", err)
+	}
+
+	if dbFeed.UserID != dbUser.ID {
+		return fmt.Errorf("only the user who added %q can change its credentials", dbFeed.Name)
+	}
+
+	err = s.Store.ClearFeedCredentials(context.Background(), dbFeed.ID)
+	if err != nil {
+		return fmt.Errorf("clearing feed credentials: %w", err)
+	}
+
+	fmt.Printf("Credentials for %q cleared\n", dbFeed.Name)
+
+	return nil
+}
+
+func printFeedCredentials(s *state.State, dbFeed database.Feed) error {
+	credentials, err := s.Store.GetFeedCredentials(context.Background(), dbFeed.ID)
+	if err != nil {
+		return fmt.Errorf("getting feed credentials: %w", err)
+	}
+
+	if len(credentials) == 0 {
+		fmt.Printf("No credentials set for %q\n", dbFeed.Name)
+		return nil
+	}
+
+	for _, credential := range credentials {
+		switch credential.Kind {
+		case "basic":
+			fmt.Printf("* basic\t%s:%s\n", credential.Username.String, credential.SecretRef)
+		case "header":
+			fmt.Printf("* header\t%s: %s\n", credential.HeaderName, credential.SecretRef)
+		default:
+			fmt.Printf("* %s\t%s\n", credential.Kind, credential.SecretRef)
+		}
+	}
+
+	return nil
+}
+
+// feedAuth resolves the stored credentials of a feed into an rss.Auth, or nil
+// when the feed has none. References outside allowed are not resolved, so
+// credentials stored before the allowlist changed cannot read arbitrary
+// secrets.
+func feedAuth(q database.Querier, feedID uuid.UUID, allowed []string) (*rss.Auth, error) {
+	credentials, err := q.GetFeedCredentials(context.Background(), feedID)
+	if err != nil {
+		return nil, fmt.Errorf("getting feed credentials: %w", err)
+	}
+
+	if len(credentials) == 0 {
+		return nil, nil
+	}
+
+	auth := &rss.Auth{Header: http.Header{}}
+	for _, credential := range credentials {
+		if err := secret.Allowed(credential.SecretRef, allowed); err != nil {
+			return nil, fmt.Errorf("resolving %s credential: %w", credential.Kind, err)
+		}
+		value, err := secret.Resolve(credential.SecretRef)
+		if err != nil {
+			return nil, fmt.Errorf("resolving %s credential: %w", credential.Kind, err)
+		}
+
+		switch credential.Kind {
+		case "basic":
+			auth.Username = credential.Username.String
+			auth.Password = value
+		case "bearer":
+			auth.BearerToken = value
+		case "header":
+			auth.Header.Set(credential.HeaderName, value)
+		}
+	}
+
+	return auth, nil
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/lmilojevicc/gator/internal/cli"
+)
+
+func TestFeedAuthAllowedSecrets(t *testing.T) {
+	s := newTestState(t, nil)
+	alice := createUser(t, s, "alice")
+
+	feedURL := "https://example.com/private.xml"
+	if err := HandlerAddFeed(s, cli.Command{Arguments: []string{"Private", feedURL}}, alice); err != nil {
+		t.Fatal(err)
+	}
+
+	s.Cfg.AllowedSecrets = []string{"env:GATOR_FEED_*", "file:/etc/gator/*"}
+	for _, ref := range []string{"env:HOME", "file:/etc/shadow", "file:/etc/gator/../shadow"} {
+		cmd := cli.Command{Name: "feedauth", Arguments: []string{feedURL, "bearer", ref}}
+		if err := HandlerFeedAuth(s, cmd, alice); err == nil {
+			t.Errorf("feedauth accepted %s", ref)
+		}
+	}
+
+	cmd := cli.Command{Name: "feedauth", Arguments: []string{feedURL, "bearer", "env:GATOR_FEED_TOKEN"}}
+	if err := HandlerFeedAuth(s, cmd, alice); err != nil {
+		t.Errorf("feedauth with an allowed reference: %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/lmilojevicc/gator/internal/cli"
+)
+
+func TestFeedChangesNeedOwner(t *testing.T) {
+	srv := feedServer(t, http.StatusOK, testFeed)
+	s := newTestState(t, srv)
+	alice := setPassword(t, s, createUser(t, s, "alice"))
+	bob := createUser(t, s, "bob")
+	carol := createUser(t, s, "carol")
+
+	feedURL := "https://example.com/bob.xml"
+	if err := HandlerAddFeed(s, cli.Command{Arguments: []string{"Bob's", feedURL}}, bob); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := HandlerRenameFeed(s, cli.Command{Name: "renamefeed", Arguments: []string{feedURL, "Carol's"}}, carol); err == nil {
+		t.Error("a non-admin renamed someone else's feed")
+	}
+	if err := HandlerSetFeedURL(s, cli.Command{Name: "setfeedurl", Arguments: []string{feedURL, srv.URL}}, carol); err == nil {
+		t.Error("a non-admin re-pointed someone else's feed")
+	}
+	feed, err := s.Store.GetFeedByURL(context.Background(), feedURL)
+	if err != nil || feed.Name != "Bob's" {
+		t.Fatalf("feed after refused changes = %+v, %v", feed, err)
+	}
+
+	// The owner and an admin may.
+	if err := HandlerRenameFeed(s, cli.Command{Name: "renamefeed", Arguments: []string{feedURL, "Bob's blog"}}, bob); err != nil {
+		t.Errorf("renamefeed as owner: %v", err)
+	}
+	if err := HandlerSetFeedURL(s, cli.Command{Name: "setfeedurl", Arguments: []string{feedURL, srv.URL}}, alice); err != nil {
+		t.Errorf("setfeedurl as admin: %v", err)
+	}
+	if feed, err := s.Store.GetFeedByURL(context.Background(), srv.URL); err != nil || feed.Name != "Bob's blog" {
+		t.Errorf("feed after owner and admin changes = %+v, %v", feed, err)
+	}
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lmilojevicc/gator/internal/auth"
+	"github.com/lmilojevicc/gator/internal/cli"
+	"github.com/lmilojevicc/gator/internal/database"
+	"github.com/lmilojevicc/gator/internal/fever"
+	"github.com/lmilojevicc/gator/internal/state"
+)
+
+// HandlerFever enables or disables the Fever API for the logged in user.
+// Fever clients send md5("name:password"), so the key is derived from the
+// account password and has to be enabled again after passwd.
+func HandlerFever(s *state.State, cmd cli.Command, dbUser database.User) error {
+	if len(cmd.Arguments) != 1 {
+		return fmt.Errorf("usage: %s <enable|disable>", cmd.Name)
+	}
+
+	var keyHash sql.NullString
+	switch cmd.Arguments[0] {
+	case "enable":
+		if !dbUser.PasswordHash.Valid {
+			return auth.ErrNoPassword
+		}
+
+		password, err := readPassword("Password: ")
+		if err != nil {
+			return err
+		}
+		if err := auth.CheckPassword(dbUser.PasswordHash.String, password); err != nil {
+			return err
+		}
+
+		keyHash = sql.NullString{String: auth.HashToken(fever.APIKey(dbUser.Name, password)), Valid: true}
+	case "disable":
+	default:
+		return fmt.Errorf("usage: %s <enable|disable>", cmd.Name)
+	}
+
+	err := s.Store.SetUserFeverKey(context.Background(), database.SetUserFeverKeyParams{
+		ID:           dbUser.ID,
+		FeverKeyHash: keyHash,
+	})
+	if err != nil {
+		return fmt.Errorf("setting fever key: %w", err)
+	}
+
+	if keyHash.Valid {
+		fmt.Printf("Fever API enabled, log in to /fever/ on the serve address as %s with your password\n", dbUser.Name)
+	} else {
+		fmt.Println("Fever API disabled")
+	}
+
+	return nil
+}
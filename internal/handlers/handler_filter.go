@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/lmilojevicc/gator/internal/cli"
+	"github.com/lmilojevicc/gator/internal/database"
+	"github.com/lmilojevicc/gator/internal/filter"
+	"github.com/lmilojevicc/gator/internal/state"
+)
+
+const filterUsage = `usage:
+  %[1]s add [--feed <feed_url>] <include|exclude> <keyword|regex|author|category> <pattern>
+  %[1]s list
+  %[1]s remove <rule_id>`
+
+// HandlerFilter manages the include and exclude rules of the logged in user,
+// which browse and notifications apply.
+func HandlerFilter(s *state.State, cmd cli.Command, dbUser database.User) error {
+	if len(cmd.Arguments) == 0 {
+		return fmt.Errorf(filterUsage, cmd.Name)
+	}
+
+	args := cmd.Arguments[1:]
+	switch cmd.Arguments[0] {
+	case "add":
+		return addFilterRule(s, cmd, dbUser, args)
+	case "list":
+		if len(args) != 0 {
+			return fmt.Errorf(filterUsage, cmd.Name)
+		}
+		return listFilterRules(s, dbUser)
+	case "remove":
+		if len(args) != 1 {
+			return fmt.Errorf(filterUsage, cmd.Name)
+		}
+		return removeFilterRule(s, dbUser, args[0])
+	default:
+		return fmt.Errorf(filterUsage, cmd.Name)
+	}
+}
+
+func addFilterRule(s *state.State, cmd cli.Command, dbUser database.User, args []string) error {
+	flags := flag.NewFlagSet(cmd.Name+" add", flag.ContinueOnError)
+	feedURL := flags.String("feed", "", "only apply the rule to this feed")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 3 {
+		return fmt.Errorf(filterUsage, cmd.Name)
+	}
+
+	action, kind, pattern := flags.Arg(0), flags.Arg(1), flags.Arg(2)
+	if !slices.Contains(filter.Actions, action) || !slices.Contains(filter.Kinds, kind) {
+		return fmt.Errorf(filterUsage, cmd.Name)
+	}
+	if _, err := filter.Matcher(kind, pattern); err != nil {
+		return err
+	}
+
+	params := database.CreateFilterRuleParams{
+		ID:      uuid.New(),
+		UserID:  dbUser.ID,
+		Action:  action,
+		Kind:    kind,
+		Pattern: pattern,
+	}
+
+	if *feedURL != "" {
+		dbFeed, err := followedFeed(s, dbUser, *feedURL)
+		if err != nil {
+			return err
+		}
+		params.FeedID = uuid.NullUUID{UUID: dbFeed.ID, Valid: true}
+	}
+
+	rule, err := s.Store.CreateFilterRule(context.Background(), params)
+	if err != nil {
+		return fmt.Errorf("creating filter rule: %w", err)
+	}
+
+	fmt.Printf("Added filter rule %s\n", rule.ID)
+
+	return nil
+}
+
+func listFilterRules(s *state.State, dbUser database.User) error {
+	rules, err := s.Store.GetFilterRulesForUser(context.Background(), dbUser.ID)
+	if err != nil {
+		return fmt.Errorf("getting filter rules: %w", err)
+	}
+
+	if len(rules) == 0 {
+		fmt.Println("You have no filter rules")
+		return nil
+	}
+
+	for _, rule := range rules {
+		feed := "all feeds"
+		if rule.FeedName.Valid {
+			feed = fmt.Sprintf("%q", rule.FeedName.String)
+		}
+		fmt.Printf("* %s\n", rule.ID)
+		fmt.Printf("  %s posts whose %s matches %q in %s\n", rule.Action, rule.Kind, rule.Pattern, feed)
+	}
+
+	return nil
+}
+
+func removeFilterRule(s *state.State, dbUser database.User, ruleID string) error {
+	id, err := uuid.Parse(ruleID)
+	if err != nil {
+		return fmt.Errorf("invalid rule id %q", ruleID)
+	}
+
+	removed, err := s.Store.DeleteFilterRule(context.Background(), database.DeleteFilterRuleParams{
+		ID:     id,
+		UserID: dbUser.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("deleting filter rule: %w", err)
+	}
+	if removed == 0 {
+		return fmt.Errorf("you have no filter rule %s", id)
+	}
+
+	fmt.Printf("Removed filter rule %s\n", id)
+
+	return nil
+}
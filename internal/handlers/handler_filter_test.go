@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/lmilojevicc/gator/internal/cli"
+	"github.com/lmilojevicc/gator/internal/database"
+)
+
+const filterFeed = `<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/"><channel>
+<title>HN</title>
+<item><title>Go 1.26 released</title><link>https://example.com/go</link><pubDate>2024-05-01T10:00:00Z</pubDate><category>Programming</category></item>
+<item><title>Coin launch</title><link>https://example.com/coin</link><pubDate>2024-05-01T09:00:00Z</pubDate><dc:creator>Shill</dc:creator></item>
+<item><title>Rust 2.0</title><link>https://example.com/rust</link><pubDate>2024-05-01T08:00:00Z</pubDate><category>programming</category></item>
+</channel></rss>`
+
+func TestFilterBrowse(t *testing.T) {
+	srv := feedServer(t, http.StatusOK, filterFeed)
+	s := newTestState(t, srv)
+
+	alice := createUser(t, s, "alice")
+	if err := HandlerAddFeed(s, cli.Command{Name: "addfeed", Arguments: []string{"HN", srv.URL}}, alice); err != nil {
+		t.Fatal(err)
+	}
+	if err := scrapeFeeds(s); err != nil {
+		t.Fatal(err)
+	}
+
+	filterCmd := func(args ...string) error {
+		return HandlerFilter(s, cli.Command{Name: "filter", Arguments: args}, alice)
+	}
+	browsed := func() []string {
+		t.Helper()
+		posts, err := filteredPosts(s, database.GetPostsForUserParams{UserID: alice.ID}, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var titles []string
+		for _, post := range posts {
+			titles = append(titles, post.Title.String)
+		}
+		return titles
+	}
+
+	for _, args := range [][]string{
+		{"add", "exclude", "regex", "(unclosed"},
+		{"add", "hide", "keyword", "coin"},
+		{"add", "--feed", "https://example.com/unknown", "exclude", "keyword", "coin"},
+	} {
+		if err := filterCmd(args...); err == nil {
+			t.Errorf("filter %v succeeded", args)
+		}
+	}
+
+	// The author comes from <dc:creator>; the excluded post does not count
+	// towards the limit.
+	if err := filterCmd("add", "exclude", "author", "shill"); err != nil {
+		t.Fatal(err)
+	}
+	if got := browsed(); len(got) != 2 || got[0] != "Go 1.26 released" || got[1] != "Rust 2.0" {
+		t.Errorf("browse = %q, want the Go and Rust posts", got)
+	}
+
+	if err := filterCmd("add", "--feed", srv.URL, "include", "regex", "^Go "); err != nil {
+		t.Fatal(err)
+	}
+	if got := browsed(); len(got) != 1 || got[0] != "Go 1.26 released" {
+		t.Errorf("browse = %q, want only the Go post", got)
+	}
+
+	rules, err := s.Store.GetFilterRulesForUser(context.Background(), alice.ID)
+	if err != nil || len(rules) != 2 || rules[1].FeedName.String != "HN" {
+		t.Fatalf("rules = %+v, %v", rules, err)
+	}
+	for _, rule := range rules {
+		if err := filterCmd("remove", rule.ID.String()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := browsed(); len(got) != 2 || got[1] != "Coin launch" {
+		t.Errorf("browse without rules = %q", got)
+	}
+}
@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/google/uuid"
 
@@ -12,34 +15,56 @@ import (
 	"github.com/lmilojevicc/gator/internal/state"
 )
 
+// HandlerFollow follows every feed named by the arguments, each a URL, a
+// name or the start of an ID.
 func HandlerFollow(s *state.State, cmd cli.Command, dbUser database.User) error {
-	if len(cmd.Arguments) != 1 {
-		return fmt.Errorf("usage: %s <url>", cmd.Name)
+	if len(cmd.Arguments) == 0 {
+		return fmt.Errorf("usage: %s <feed_url|feed_name|feed_id>...", cmd.Name)
 	}
 
-	feedURL := cmd.Arguments[0]
-
-	dbFeed, err := s.Queries.GetFeedByURL(context.Background(), feedURL)
+	dbFeeds, err := s.Store.GetAllFeeds(context.Background())
 	if err != nil {
-		return fmt.Errorf("getting feed: %w", err)
+		return fmt.Errorf("getting feeds: %w", err)
+	}
+	follows, err := s.Store.GetFeedFollowsForUser(context.Background(), dbUser.ID)
+	if err != nil {
+		return fmt.Errorf("getting feeds followed by user: %w", err)
 	}
 
-	dbFeedFollow, err := s.Queries.CreateFeedFollow(context.Background(), database.CreateFeedFollowParams{
-		ID:     uuid.New(),
-		UserID: dbUser.ID,
-		FeedID: dbFeed.ID,
-	})
+	choices := make([]feedChoice, 0, len(dbFeeds))
+	for _, feed := range dbFeeds {
+		choices = append(choices, feedChoice{feed: feed})
+	}
+	// Every argument is resolved before anything is followed, so a typo does
+	// not leave the command half done.
+	toFollow, err := resolveFeeds(cmd.Arguments, choices)
 	if err != nil {
-		return fmt.Errorf("creating feed following: %w", err)
+		return err
 	}
 
-	fmt.Printf("User %s is now following %q", dbFeedFollow.UserName, dbFeedFollow.FeedName)
+	for _, dbFeed := range toFollow {
+		if slices.ContainsFunc(follows, func(f database.GetFeedFollowsForUserRow) bool { return f.FeedID == dbFeed.ID }) {
+			fmt.Printf("You already follow %q\n", dbFeed.Name)
+			continue
+		}
+
+		dbFeedFollow, err := s.Store.CreateFeedFollow(context.Background(), database.CreateFeedFollowParams{
+			ID:     uuid.New(),
+			UserID: dbUser.ID,
+			FeedID: dbFeed.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("creating feed following: %w", err)
+		}
+
+		fmt.Printf("User %s is now following %q\n", dbFeedFollow.UserName, dbFeedFollow.FeedName)
+	}
 
 	return nil
 }
 
 func HandlerFollowing(s *state.State, cmd cli.Command, dbUser database.User) error {
-	dbFeedsFollowed, err := s.Queries.GetFeedFollowsForUser(context.Background(), dbUser.ID)
+	dbFeedsFollowed, err := s.Store.GetFeedFollowsForUser(context.Background(), dbUser.ID)
 	if err != nil {
 		return fmt.Errorf("getting feeds followed by user: %w", err)
 	}
@@ -50,36 +75,262 @@ func HandlerFollowing(s *state.State, cmd cli.Command, dbUser database.User) err
 	}
 
 	fmt.Printf("You are currently following:\n")
-	for _, feed := range dbFeedsFollowed {
-		fmt.Printf("  * %q\n", feed.FeedName)
+	groups := followGroups(dbFeedsFollowed)
+	for _, group := range groups {
+		// Without categories the follows are a flat list, as they used to be.
+		if len(groups) > 1 || group.category != "" {
+			fmt.Printf("%s:\n", cmp.Or(group.category, "Uncategorized"))
+		}
+		for _, feed := range group.follows {
+			fmt.Printf("  * %q\n", feed.FeedName)
+		}
 	}
 
 	return nil
 }
 
+type followGroup struct {
+	// category is empty for the follows not in a category.
+	category string
+	follows  []database.GetFeedFollowsForUserRow
+}
+
+// followGroups groups follows by category, sorted by name, with the follows
+// without a category last. Feeds are sorted by name within a group.
+func followGroups(follows []database.GetFeedFollowsForUserRow) []followGroup {
+	follows = slices.Clone(follows)
+	slices.SortFunc(follows, func(a, b database.GetFeedFollowsForUserRow) int {
+		if a.CategoryName.Valid != b.CategoryName.Valid {
+			if a.CategoryName.Valid {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Or(
+			strings.Compare(a.CategoryName.String, b.CategoryName.String),
+			strings.Compare(a.FeedName, b.FeedName),
+		)
+	})
+
+	var groups []followGroup
+	for _, follow := range follows {
+		if len(groups) == 0 || groups[len(groups)-1].category != follow.CategoryName.String {
+			groups = append(groups, followGroup{category: follow.CategoryName.String})
+		}
+		last := &groups[len(groups)-1]
+		last.follows = append(last.follows, follow)
+	}
+	return groups
+}
+
+// HandlerUnfollow unfollows every feed named by the arguments, each a URL, a
+// name, the user's title for it or the start of an ID.
 func HandlerUnfollow(s *state.State, cmd cli.Command, dbUser database.User) error {
+	if len(cmd.Arguments) == 0 {
+		return fmt.Errorf("usage: %s <feed_url|feed_name|feed_id>...", cmd.Name)
+	}
+
+	dbFeeds, err := s.Store.GetAllFeeds(context.Background())
+	if err != nil {
+		return fmt.Errorf("getting feeds: %w", err)
+	}
+	follows, err := s.Store.GetFeedFollowsForUser(context.Background(), dbUser.ID)
+	if err != nil {
+		return fmt.Errorf("getting feeds followed by user: %w", err)
+	}
+
+	var choices []feedChoice
+	for _, follow := range follows {
+		i := slices.IndexFunc(dbFeeds, func(f database.Feed) bool { return f.ID == follow.FeedID })
+		if i >= 0 {
+			choices = append(choices, feedChoice{feed: dbFeeds[i], title: follow.Title.String})
+		}
+	}
+	toUnfollow, err := resolveFeeds(cmd.Arguments, choices)
+	if err != nil {
+		return err
+	}
+
+	for _, dbFeed := range toUnfollow {
+		_, err = s.Store.Unfollow(context.Background(), database.UnfollowParams{
+			FeedID: dbFeed.ID,
+			UserID: dbUser.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("unfollowing feed: %w", err)
+		}
+
+		fmt.Printf("You have successfully unfollowed %q\n", dbFeed.Name)
+	}
+
+	return nil
+}
+
+// feedChoice is a feed an argument of follow or unfollow can refer to.
+type feedChoice struct {
+	feed database.Feed
+	// title is the user's own title for the feed, if any.
+	title string
+}
+
+func (c feedChoice) String() string {
+	return fmt.Sprintf("%s  %s  %s", c.feed.ID.String()[:8], c.feed.Name, c.feed.Url)
+}
+
+// resolveFeeds returns the feed each argument refers to, without duplicates.
+// When an argument matches several feeds, the user picks one if stdin is a
+// terminal, and when it only matches part of a name, the user confirms it;
+// otherwise it is an error listing the candidates.
+func resolveFeeds(args []string, choices []feedChoice) ([]database.Feed, error) {
+	var feeds []database.Feed
+	for _, arg := range args {
+		matches, partial := matchFeeds(arg, choices)
+
+		var match feedChoice
+		switch {
+		case len(matches) == 0:
+			return nil, fmt.Errorf("no feed matches %q", arg)
+		case len(matches) == 1 && !partial:
+			match = matches[0]
+		case len(matches) == 1 && stdinIsTerminal():
+			ok, err := confirm(fmt.Sprintf("%q matches part of %s, use it?", arg, matches[0]))
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				return nil, fmt.Errorf("%q was not confirmed", arg)
+			}
+			match = matches[0]
+		case len(matches) == 1:
+			return nil, fmt.Errorf("%q only matches part of a name, use the feed's name, URL or ID:\n  %s", arg, matches[0])
+		case stdinIsTerminal():
+			options := make([]string, len(matches))
+			for i, m := range matches {
+				options[i] = m.String()
+			}
+			i, err := choose(fmt.Sprintf("%q matches %d feeds:", arg, len(matches)), options)
+			if err != nil {
+				return nil, err
+			}
+			match = matches[i]
+		default:
+			var list strings.Builder
+			for _, m := range matches {
+				fmt.Fprintf(&list, "\n  %s", m)
+			}
+			return nil, fmt.Errorf("%q matches %d feeds, use its URL or ID:%s", arg, len(matches), list.String())
+		}
+
+		if !slices.ContainsFunc(feeds, func(f database.Feed) bool { return f.ID == match.feed.ID }) {
+			feeds = append(feeds, match.feed)
+		}
+	}
+	return feeds, nil
+}
+
+// matchFeeds returns the choices arg refers to, trying in turn: the feed with
+// that URL, the feeds with that name or title ignoring case, the feeds whose
+// ID starts with arg, and the feeds whose name or title contains arg ignoring
+// case. partial is set for the last kind, which the user has to confirm.
+func matchFeeds(arg string, choices []feedChoice) (matches []feedChoice, partial bool) {
+	matching := func(match func(feedChoice) bool) []feedChoice {
+		var matches []feedChoice
+		for _, c := range choices {
+			if match(c) {
+				matches = append(matches, c)
+			}
+		}
+		return matches
+	}
+
+	if m := matching(func(c feedChoice) bool { return c.feed.Url == arg }); len(m) > 0 {
+		return m, false
+	}
+	if m := matching(func(c feedChoice) bool {
+		return strings.EqualFold(c.feed.Name, arg) || strings.EqualFold(c.title, arg)
+	}); len(m) > 0 {
+		return m, false
+	}
+	if isIDPrefix(arg) {
+		prefix := strings.ToLower(arg)
+		if m := matching(func(c feedChoice) bool { return strings.HasPrefix(c.feed.ID.String(), prefix) }); len(m) > 0 {
+			return m, false
+		}
+	}
+	word := strings.ToLower(arg)
+	return matching(func(c feedChoice) bool {
+		return strings.Contains(strings.ToLower(c.feed.Name), word) ||
+			strings.Contains(strings.ToLower(c.title), word)
+	}), true
+}
+
+// isIDPrefix reports whether arg can be the start of a UUID. Shorter prefixes
+// than 4 characters are more likely part of a name.
+func isIDPrefix(arg string) bool {
+	if len(arg) < 4 {
+		return false
+	}
+	return strings.Trim(strings.ToLower(arg), "0123456789abcdef-") == ""
+}
+
+// HandlerSetFeedTitle gives a feed the logged in user follows a title of their
+// own, which following, browse, digests and exports show instead of its name.
+// Other followers still see the feed's name.
+func HandlerSetFeedTitle(s *state.State, cmd cli.Command, dbUser database.User) error {
+	if len(cmd.Arguments) != 2 || cmd.Arguments[1] == "" {
+		return fmt.Errorf("usage: %s <feed_url> <title>", cmd.Name)
+	}
+
+	return setFollowTitle(s, dbUser, cmd.Arguments[0], cmd.Arguments[1])
+}
+
+// HandlerClearFeedTitle shows a feed under its own name again.
+func HandlerClearFeedTitle(s *state.State, cmd cli.Command, dbUser database.User) error {
 	if len(cmd.Arguments) != 1 {
-		return fmt.Errorf("usage: %s <feed_name>", cmd.Name)
+		return fmt.Errorf("usage: %s <feed_url>", cmd.Name)
 	}
 
-	feedURL := cmd.Arguments[0]
-	dbFeed, err := s.Queries.GetFeedByURL(context.Background(), feedURL)
+	return setFollowTitle(s, dbUser, cmd.Arguments[0], "")
+}
+
+func setFollowTitle(s *state.State, dbUser database.User, feedURL, title string) error {
+	dbFeed, err := followedFeed(s, dbUser, feedURL)
 	if err != nil {
-		return fmt.Errorf("getting feed by url: %w", err)
+		return err
 	}
 
-	_, err = s.Queries.Unfollow(context.Background(), database.UnfollowParams{
-		FeedID: dbFeed.ID,
+	_, err = s.Store.SetFollowTitle(context.Background(), database.SetFollowTitleParams{
+		Title:  sql.NullString{String: title, Valid: title != ""},
 		UserID: dbUser.ID,
+		FeedID: dbFeed.ID,
 	})
-	if err == sql.ErrNoRows {
-		return fmt.Errorf("no feed with url: %s", feedURL)
-	}
 	if err != nil {
-		return fmt.Errorf("unfollowing feed: %w", err)
+		return fmt.Errorf("setting title of %q: %w", dbFeed.Name, err)
 	}
 
-	fmt.Printf("You have successfully unfollowed %q", dbFeed.Name)
+	if title == "" {
+		fmt.Printf("%q is shown under its own name again\n", dbFeed.Name)
+	} else {
+		fmt.Printf("%q is now shown to you as %q\n", dbFeed.Name, title)
+	}
 
 	return nil
 }
+
+// followedFeed looks up the feed at feedURL and checks that dbUser follows it.
+func followedFeed(s *state.State, dbUser database.User, feedURL string) (database.Feed, error) {
+	dbFeed, err := s.Store.GetFeedByURL(context.Background(), feedURL)
+	if err != nil {
+		return database.Feed{}, fmt.Errorf("getting feed by url: %w", err)
+	}
+
+	follows, err := s.Store.GetFeedFollowsForUser(context.Background(), dbUser.ID)
+	if err != nil {
+		return database.Feed{}, fmt.Errorf("getting feeds followed by user: %w", err)
+	}
+	if !slices.ContainsFunc(follows, func(f database.GetFeedFollowsForUserRow) bool { return f.FeedID == dbFeed.ID }) {
+		return database.Feed{}, fmt.Errorf("you do not follow %q", dbFeed.Name)
+	}
+
+	return dbFeed, nil
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/lmilojevicc/gator/internal/cli"
+	"github.com/lmilojevicc/gator/internal/database"
+)
+
+func TestFeedTitle(t *testing.T) {
+	srv := feedServer(t, http.StatusOK, testFeed)
+	s := newTestState(t, srv)
+
+	alice := createUser(t, s, "alice")
+	bob := createUser(t, s, "bob")
+	if err := HandlerAddFeed(s, cli.Command{Name: "addfeed", Arguments: []string{"Test", srv.URL}}, alice); err != nil {
+		t.Fatal(err)
+	}
+	if err := scrapeFeeds(s); err != nil {
+		t.Fatal(err)
+	}
+
+	setTitle := func(user database.User, args ...string) error {
+		return HandlerSetFeedTitle(s, cli.Command{Name: "setfeedtitle", Arguments: args}, user)
+	}
+	// names returns the feed name user sees in following and browse.
+	names := func(user database.User) (following, browse string) {
+		t.Helper()
+		follows, err := s.Store.GetFeedFollowsForUser(context.Background(), user.ID)
+		if err != nil || len(follows) != 1 {
+			t.Fatalf("follows of %s = %+v, %v", user.Name, follows, err)
+		}
+		posts, err := filteredPosts(s, database.GetPostsForUserParams{UserID: user.ID}, 1)
+		if err != nil || len(posts) != 1 {
+			t.Fatalf("posts of %s = %+v, %v", user.Name, posts, err)
+		}
+		return follows[0].FeedName, posts[0].FeedName
+	}
+
+	// bob has to follow the feed to give it a title.
+	if err := setTitle(bob, srv.URL, "Mine"); err == nil {
+		t.Error("setfeedtitle of a feed bob does not follow succeeded")
+	}
+	if err := HandlerFollow(s, cli.Command{Name: "follow", Arguments: []string{srv.URL}}, bob); err != nil {
+		t.Fatal(err)
+	}
+	if err := setTitle(alice, srv.URL, ""); err == nil {
+		t.Error("setfeedtitle with an empty title succeeded")
+	}
+
+	if err := setTitle(alice, srv.URL, "Alice's test feed"); err != nil {
+		t.Fatal(err)
+	}
+	if following, browse := names(alice); following != "Alice's test feed" || browse != "Alice's test feed" {
+		t.Errorf("alice sees %q and %q, want her title", following, browse)
+	}
+	if following, browse := names(bob); following != "Test" || browse != "Test" {
+		t.Errorf("bob sees %q and %q, want the feed name", following, browse)
+	}
+
+	if err := HandlerClearFeedTitle(s, cli.Command{Name: "clearfeedtitle", Arguments: []string{srv.URL}}, alice); err != nil {
+		t.Fatal(err)
+	}
+	if following, browse := names(alice); following != "Test" || browse != "Test" {
+		t.Errorf("after clearing alice sees %q and %q, want the feed name", following, browse)
+	}
+}
+
+func TestFollowByName(t *testing.T) {
+	original := stdinIsTerminal
+	t.Cleanup(func() { stdinIsTerminal = original })
+	stdinIsTerminal = func() bool { return false }
+
+	s := newTestState(t, nil)
+	alice := createUser(t, s, "alice")
+	bob := createUser(t, s, "bob")
+
+	feeds := map[string]database.Feed{}
+	for _, name := range []string{"Go Blog", "Go News", "Rust Blog"} {
+		feed, err := s.Store.CreateFeed(context.Background(), database.CreateFeedParams{
+			ID:     uuid.New(),
+			Name:   name,
+			Url:    "https://example.com/" + strings.ReplaceAll(strings.ToLower(name), " ", "-"),
+			UserID: alice.ID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		feeds[name] = feed
+	}
+
+	follow := func(args ...string) error {
+		return HandlerFollow(s, cli.Command{Name: "follow", Arguments: args}, bob)
+	}
+	unfollow := func(args ...string) error {
+		return HandlerUnfollow(s, cli.Command{Name: "unfollow", Arguments: args}, bob)
+	}
+	following := func() []string {
+		t.Helper()
+		follows, err := s.Store.GetFeedFollowsForUser(context.Background(), bob.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, f := range follows {
+			names = append(names, f.FeedName)
+		}
+		slices.Sort(names)
+		return names
+	}
+
+	// "go" is part of two names; without a terminal the error lists both.
+	err := follow("rust blog", "go")
+	if err == nil || !strings.Contains(err.Error(), "https://example.com/go-blog") || !strings.Contains(err.Error(), "https://example.com/go-news") {
+		t.Errorf("follow of an ambiguous name: %v, want the candidates", err)
+	}
+	if got := following(); len(got) != 0 {
+		t.Errorf("after a failed follow bob follows %q, want nothing", got)
+	}
+	if err := follow("unknown"); err == nil {
+		t.Error("follow of an unknown feed succeeded")
+	}
+	// Part of a single name needs confirming, which takes a terminal.
+	err = follow("rust")
+	if err == nil || !strings.Contains(err.Error(), "https://example.com/rust-blog") {
+		t.Errorf("follow of part of a name: %v, want the candidate", err)
+	}
+
+	// By name ignoring case, by ID prefix and by URL, with duplicates.
+	err = follow("rust blog", feeds["Go News"].ID.String()[:8], "https://example.com/go-blog", "RUST BLOG")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := following(); !slices.Equal(got, []string{"Go Blog", "Go News", "Rust Blog"}) {
+		t.Errorf("bob follows %q", got)
+	}
+	if err := follow("Go Blog"); err != nil {
+		t.Errorf("following a feed again: %v", err)
+	}
+
+	// A user's own title names the feed too, and a terminal lets bob pick.
+	if err := HandlerSetFeedTitle(s, cli.Command{Name: "setfeedtitle", Arguments: []string{"https://example.com/rust-blog", "Crabs"}}, bob); err != nil {
+		t.Fatal(err)
+	}
+	stdinIsTerminal = func() bool { return true }
+	answerPrompts(t, "2\n")
+	if err := unfollow("crabs", "go"); err != nil {
+		t.Fatal(err)
+	}
+	if got := following(); len(got) != 1 {
+		t.Errorf("after unfollowing two feeds bob follows %q", got)
+	}
+
+	// On a terminal, part of a name is used once confirmed.
+	answerPrompts(t, "n\n")
+	if err := follow("rus"); err == nil {
+		t.Error("follow of a declined partial match succeeded")
+	}
+	answerPrompts(t, "y\n")
+	if err := follow("rus"); err != nil {
+		t.Fatal(err)
+	}
+	if got := following(); !slices.Contains(got, "Rust Blog") {
+		t.Errorf("after a confirmed partial match bob follows %q", got)
+	}
+}
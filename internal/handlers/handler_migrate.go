@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lmilojevicc/gator/internal/cli"
+	"github.com/lmilojevicc/gator/internal/database"
+	"github.com/lmilojevicc/gator/internal/middleware"
+	"github.com/lmilojevicc/gator/internal/migrations"
+	"github.com/lmilojevicc/gator/internal/state"
+)
+
+func HandlerMigrate(s *state.State, cmd cli.Command) error {
+	if len(cmd.Arguments) != 1 {
+		return fmt.Errorf("usage: %s up|down|status", cmd.Name)
+	}
+
+	// Going down drops data, so unlike up it is for admins only.
+	if cmd.Arguments[0] == "down" {
+		return middleware.Admin(func(s *state.State, cmd cli.Command, dbUser database.User) error {
+			return migrate(s, cmd)
+		})(s, cmd)
+	}
+
+	return migrate(s, cmd)
+}
+
+func migrate(s *state.State, cmd cli.Command) error {
+	driver, _ := s.Cfg.Database()
+	provider, err := migrations.NewProvider(s.Conn, driver)
+	if err != nil {
+		return err
+	}
+
+	switch cmd.Arguments[0] {
+	case "up":
+		results, err := provider.Up(context.Background())
+		for _, result := range results {
+			fmt.Println(result)
+		}
+		if err != nil {
+			return fmt.Errorf("migrating up: %w", err)
+		}
+		if len(results) == 0 {
+			fmt.Println("Database schema is up to date")
+		}
+	case "down":
+		result, err := provider.Down(context.Background())
+		if result != nil {
+			fmt.Println(result)
+		}
+		if err != nil {
+			return fmt.Errorf("migrating down: %w", err)
+		}
+	case "status":
+		statuses, err := provider.Status(context.Background())
+		if err != nil {
+			return fmt.Errorf("getting migration status: %w", err)
+		}
+		for _, status := range statuses {
+			appliedAt := "Pending"
+			if !status.AppliedAt.IsZero() {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%-19s  %s\n", appliedAt, status.Source.Path)
+		}
+	default:
+		return fmt.Errorf("usage: %s up|down|status", cmd.Name)
+	}
+
+	return nil
+}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/lmilojevicc/gator/internal/cli"
+	"github.com/lmilojevicc/gator/internal/database"
+	"github.com/lmilojevicc/gator/internal/notify"
+	"github.com/lmilojevicc/gator/internal/state"
+)
+
+const notifyUsage = `usage:
+  %[1]s add [--feed <feed_url>] [--keyword <word>] [--format slack|mattermost|discord|json] <webhook_url>
+  %[1]s list
+  %[1]s remove <rule_id>
+  %[1]s log [--limit <n>]`
+
+// HandlerNotify manages the notification rules of the logged in user. agg
+// POSTs new posts to the webhook of every matching rule.
+func HandlerNotify(s *state.State, cmd cli.Command, dbUser database.User) error {
+	if len(cmd.Arguments) == 0 {
+		return fmt.Errorf(notifyUsage, cmd.Name)
+	}
+
+	args := cmd.Arguments[1:]
+	switch cmd.Arguments[0] {
+	case "add":
+		return addNotificationRule(s, cmd, dbUser, args)
+	case "list":
+		if len(args) != 0 {
+			return fmt.Errorf(notifyUsage, cmd.Name)
+		}
+		return listNotificationRules(s, dbUser)
+	case "remove":
+		if len(args) != 1 {
+			return fmt.Errorf(notifyUsage, cmd.Name)
+		}
+		return removeNotificationRule(s, dbUser, args[0])
+	case "log":
+		return printNotificationLog(s, cmd, dbUser, args)
+	default:
+		return fmt.Errorf(notifyUsage, cmd.Name)
+	}
+}
+
+func addNotificationRule(s *state.State, cmd cli.Command, dbUser database.User, args []string) error {
+	flags := flag.NewFlagSet(cmd.Name+" add", flag.ContinueOnError)
+	feedURL := flags.String("feed", "", "only notify about posts from this feed")
+	keyword := flags.String("keyword", "", "only notify about posts containing this word")
+	format := flags.String("format", notify.FormatSlack, "payload format")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return fmt.Errorf(notifyUsage, cmd.Name)
+	}
+
+	webhookURL := flags.Arg(0)
+	if err := s.Notifier.CheckWebhookURL(webhookURL); err != nil {
+		return err
+	}
+	if !slices.Contains(notify.Formats, *format) {
+		return fmt.Errorf("unknown format %q, use one of %s", *format, strings.Join(notify.Formats, ", "))
+	}
+
+	params := database.CreateNotificationRuleParams{
+		ID:         uuid.New(),
+		UserID:     dbUser.ID,
+		Keyword:    sql.NullString{String: *keyword, Valid: *keyword != ""},
+		WebhookUrl: webhookURL,
+		Format:     *format,
+	}
+
+	if *feedURL != "" {
+		dbFeed, err := followedFeed(s, dbUser, *feedURL)
+		if err != nil {
+			return err
+		}
+		params.FeedID = uuid.NullUUID{UUID: dbFeed.ID, Valid: true}
+	}
+
+	rule, err := s.Store.CreateNotificationRule(context.Background(), params)
+	if err != nil {
+		return fmt.Errorf("creating notification rule: %w", err)
+	}
+
+	fmt.Printf("Added notification rule %s\n", rule.ID)
+
+	return nil
+}
+
+func listNotificationRules(s *state.State, dbUser database.User) error {
+	rules, err := s.Store.GetNotificationRulesForUser(context.Background(), dbUser.ID)
+	if err != nil {
+		return fmt.Errorf("getting notification rules: %w", err)
+	}
+
+	if len(rules) == 0 {
+		fmt.Println("You have no notification rules")
+		return nil
+	}
+
+	for _, rule := range rules {
+		feed := "all followed feeds"
+		if rule.FeedName.Valid {
+			feed = fmt.Sprintf("%q", rule.FeedName.String)
+		}
+		fmt.Printf("* %s\n", rule.ID)
+		fmt.Printf("  Webhook:\t%s (%s)\n", rule.WebhookUrl, rule.Format)
+		fmt.Printf("  Feeds:\t%s\n", feed)
+		if rule.Keyword.Valid {
+			fmt.Printf("  Keyword:\t%q\n", rule.Keyword.String)
+		}
+	}
+
+	return nil
+}
+
+func removeNotificationRule(s *state.State, dbUser database.User, ruleID string) error {
+	id, err := uuid.Parse(ruleID)
+	if err != nil {
+		return fmt.Errorf("invalid rule id %q", ruleID)
+	}
+
+	removed, err := s.Store.DeleteNotificationRule(context.Background(), database.DeleteNotificationRuleParams{
+		ID:     id,
+		UserID: dbUser.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("deleting notification rule: %w", err)
+	}
+	if removed == 0 {
+		return fmt.Errorf("you have no notification rule %s", id)
+	}
+
+	fmt.Printf("Removed notification rule %s\n", id)
+
+	return nil
+}
+
+func printNotificationLog(s *state.State, cmd cli.Command, dbUser database.User, args []string) error {
+	flags := flag.NewFlagSet(cmd.Name+" log", flag.ContinueOnError)
+	limit := flags.Int("limit", 20, "number of deliveries to show")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 0 || *limit < 1 {
+		return fmt.Errorf(notifyUsage, cmd.Name)
+	}
+
+	deliveries, err := s.Store.GetNotificationDeliveries(context.Background(), database.GetNotificationDeliveriesParams{
+		UserID: dbUser.ID,
+		Limit:  int32(*limit),
+	})
+	if err != nil {
+		return fmt.Errorf("getting notification deliveries: %w", err)
+	}
+
+	if len(deliveries) == 0 {
+		fmt.Println("No notifications have been sent")
+		return nil
+	}
+
+	for _, delivery := range deliveries {
+		outcome := "delivered"
+		if delivery.Error.Valid {
+			outcome = "failed: " + delivery.Error.String
+		}
+		fmt.Printf("%s  %q  %d posts -> %s  %s after %d attempts\n",
+			delivery.CreatedAt.Format("2006-01-02 15:04:05"), delivery.FeedName, delivery.PostCount,
+			delivery.WebhookUrl, outcome, delivery.Attempts)
+	}
+
+	return nil
+}
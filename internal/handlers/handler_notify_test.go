@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lmilojevicc/gator/internal/cli"
+	"github.com/lmilojevicc/gator/internal/config"
+	"github.com/lmilojevicc/gator/internal/database"
+	"github.com/lmilojevicc/gator/internal/middleware"
+)
+
+func TestNotifyOnNewPosts(t *testing.T) {
+	srv := feedServer(t, http.StatusOK, testFeed)
+	s := newTestState(t, srv)
+
+	var payloads []map[string]any
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		json.NewDecoder(r.Body).Decode(&payload)
+		payloads = append(payloads, payload)
+	}))
+	t.Cleanup(hook.Close)
+
+	alice := createUser(t, s, "alice")
+	if err := HandlerLogin(s, cli.Command{Name: "login", Arguments: []string{"alice"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := HandlerAddFeed(s, cli.Command{Name: "addfeed", Arguments: []string{"Test", srv.URL}}, alice); err != nil {
+		t.Fatal(err)
+	}
+
+	notifyCmd := middleware.LoggedIn(HandlerNotify)
+	if err := notifyCmd(s, cli.Command{Name: "notify", Arguments: []string{"add", "--format", "irc", hook.URL}}); err == nil {
+		t.Error("notify add with an unknown format succeeded")
+	}
+	if err := notifyCmd(s, cli.Command{Name: "notify", Arguments: []string{"add", "--feed", srv.URL, "--keyword", "new", hook.URL}}); err != nil {
+		t.Fatalf("notify add: %v", err)
+	}
+
+	if err := scrapeFeeds(s); err != nil {
+		t.Fatal(err)
+	}
+	s.Notifier.Wait()
+	// Only the post titled "New" matches; the undated one was skipped.
+	if len(payloads) != 1 || payloads[0]["text"] != "*1 new post from Test*\n- <https://example.com/new|New>" {
+		t.Fatalf("webhook received %v", payloads)
+	}
+
+	// The second fetch finds nothing new and notifies nobody.
+	if err := scrapeFeeds(s); err != nil {
+		t.Fatal(err)
+	}
+	s.Notifier.Wait()
+	if len(payloads) != 1 {
+		t.Errorf("webhook called %d times, want once", len(payloads))
+	}
+
+	// Pruned posts are not new when the feed still lists them.
+	if err := prunePosts(s, config.RetentionConfig{MaxAge: "1h"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := scrapeFeeds(s); err != nil {
+		t.Fatal(err)
+	}
+	s.Notifier.Wait()
+	if len(payloads) != 1 {
+		t.Errorf("webhook called %d times after pruning, want once", len(payloads))
+	}
+
+	log, err := s.Store.GetNotificationDeliveries(context.Background(), database.GetNotificationDeliveriesParams{
+		UserID: alice.ID,
+		Limit:  10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(log) != 1 || !log[0].DeliveredAt.Valid || log[0].FeedName != "Test" {
+		t.Errorf("delivery log = %+v", log)
+	}
+
+	rules, err := s.Store.GetNotificationRulesForUser(context.Background(), alice.ID)
+	if err != nil || len(rules) != 1 {
+		t.Fatalf("rules = %+v, %v", rules, err)
+	}
+	remove := cli.Command{Name: "notify", Arguments: []string{"remove", rules[0].ID.String()}}
+	if err := notifyCmd(s, remove); err != nil {
+		t.Fatalf("notify remove: %v", err)
+	}
+	if err := notifyCmd(s, remove); err == nil {
+		t.Error("removing the rule twice succeeded")
+	}
+}
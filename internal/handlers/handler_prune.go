@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"cmp"
+	"context"
+	"flag"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lmilojevicc/gator/internal/cli"
+	"github.com/lmilojevicc/gator/internal/config"
+	"github.com/lmilojevicc/gator/internal/database"
+	"github.com/lmilojevicc/gator/internal/state"
+	"github.com/lmilojevicc/gator/internal/store"
+)
+
+func HandlerPrune(s *state.State, cmd cli.Command, dbUser database.User) error {
+	flags := flag.NewFlagSet(cmd.Name, flag.ContinueOnError)
+	maxAge := flags.String("max-age", s.Cfg.Retention.MaxAge, "remove posts older than this duration (e.g. 720h)")
+	keep := flags.Int("keep", s.Cfg.Retention.MaxPostsPerFeed, "keep only the newest N posts of every feed")
+	if err := flags.Parse(cmd.Arguments); err != nil {
+		return fmt.Errorf("usage: %s [--max-age <duration>] [--keep <n>]", cmd.Name)
+	}
+
+	retention := config.RetentionConfig{
+		MaxAge:          *maxAge,
+		MaxPostsPerFeed: *keep,
+	}
+	if retention.MaxAge == "" && retention.MaxPostsPerFeed <= 0 {
+		return fmt.Errorf("nothing to prune: set --max-age or --keep, or retention in the config")
+	}
+
+	return prunePosts(s, retention)
+}
+
+// prunedFeed counts the posts removed from one feed.
+type prunedFeed struct {
+	name    string
+	url     string
+	removed int64
+}
+
+// prunePosts removes posts outside the retention policy, never touching saved
+// posts, and reports how many were removed per feed. When run by agg
+// (retention.Auto), it says nothing if nothing was removed.
+func prunePosts(s *state.State, retention config.RetentionConfig) error {
+	removed := map[uuid.UUID]*prunedFeed{}
+	count := func(feedID uuid.UUID, name, url string, n int64) {
+		if removed[feedID] == nil {
+			removed[feedID] = &prunedFeed{name: name, url: url}
+		}
+		removed[feedID].removed += n
+	}
+
+	var maxAge time.Duration
+	if retention.MaxAge != "" {
+		var err error
+		maxAge, err = time.ParseDuration(retention.MaxAge)
+		if err != nil {
+			return fmt.Errorf("invalid max age (use 720h, 2160h, etc...): %w", err)
+		}
+	}
+
+	// Pruned posts are remembered so agg does not insert them again, which
+	// must happen together with the delete.
+	err := s.Store.InTx(context.Background(), func(tx store.Store) error {
+		if retention.MaxAge != "" {
+			rows, err := tx.PrunePostsOlderThan(context.Background(), time.Now().Add(-maxAge))
+			if err != nil {
+				return fmt.Errorf("pruning old posts: %w", err)
+			}
+			for _, row := range rows {
+				count(row.FeedID, row.Name, row.Url, row.Removed)
+			}
+		}
+
+		if retention.MaxPostsPerFeed > 0 {
+			rows, err := tx.PrunePostsBeyondNewest(context.Background(), int64(retention.MaxPostsPerFeed))
+			if err != nil {
+				return fmt.Errorf("pruning posts beyond newest %d: %w", retention.MaxPostsPerFeed, err)
+			}
+			for _, row := range rows {
+				count(row.FeedID, row.Name, row.Url, row.Removed)
+			}
+		}
+
+		// Pruned URLs that were not in the latest fetch of their feed will
+		// not be inserted again, so there is no need to remember them.
+		if _, err := tx.ExpirePrunedPosts(context.Background()); err != nil {
+			return fmt.Errorf("expiring pruned posts: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(removed) == 0 && retention.Auto {
+		return nil
+	}
+	printPruned(slices.Collect(maps.Values(removed)))
+
+	return nil
+}
+
+func printPruned(removed []*prunedFeed) {
+	if len(removed) == 0 {
+		fmt.Println("No posts to prune")
+		return
+	}
+
+	slices.SortFunc(removed, func(a, b *prunedFeed) int {
+		return cmp.Or(cmp.Compare(a.name, b.name), cmp.Compare(a.url, b.url))
+	})
+
+	var total int64
+	for _, feed := range removed {
+		fmt.Printf("* %s (%s): %d posts removed\n", feed.name, feed.url, feed.removed)
+		total += feed.removed
+	}
+	fmt.Printf("Removed %d posts in total\n", total)
+}
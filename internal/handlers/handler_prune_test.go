@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/lmilojevicc/gator/internal/cli"
+	"github.com/lmilojevicc/gator/internal/config"
+)
+
+func TestPrunedPostsStayDeleted(t *testing.T) {
+	srv := feedServer(t, http.StatusOK, testFeed)
+	s := newTestState(t, srv)
+	alice := createUser(t, s, "alice")
+
+	if err := HandlerAddFeed(s, cli.Command{Arguments: []string{"Test", srv.URL}}, alice); err != nil {
+		t.Fatal(err)
+	}
+	if err := scrapeFeeds(s); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := prunePosts(s, config.RetentionConfig{MaxPostsPerFeed: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The old post is still in the feed, but agg must not bring it back.
+	if err := scrapeFeeds(s); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Store.GetPostByURL(context.Background(), "https://example.com/old"); err == nil {
+		t.Error("pruned post was inserted again by the next fetch")
+	}
+	if _, err := s.Store.GetPostByURL(context.Background(), "https://example.com/new"); err != nil {
+		t.Errorf("kept post: %v", err)
+	}
+}
+
+func TestPrunedPostsExpire(t *testing.T) {
+	srv := feedServer(t, http.StatusOK, testFeed)
+	s := newTestState(t, srv)
+	alice := createUser(t, s, "alice")
+
+	if err := HandlerAddFeed(s, cli.Command{Arguments: []string{"Test", srv.URL}}, alice); err != nil {
+		t.Fatal(err)
+	}
+	if err := scrapeFeeds(s); err != nil {
+		t.Fatal(err)
+	}
+	retention := config.RetentionConfig{MaxPostsPerFeed: 1}
+	if err := prunePosts(s, retention); err != nil {
+		t.Fatal(err)
+	}
+
+	// The old post drops out of the feed, so the next prune forgets it and it
+	// is new again should it come back.
+	srv.Config.Handler = feedServer(t, http.StatusOK, strings.Replace(testFeed, "https://example.com/old", "https://example.com/other", 1)).Config.Handler
+	if err := scrapeFeeds(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := prunePosts(s, retention); err != nil {
+		t.Fatal(err)
+	}
+
+	srv.Config.Handler = feedServer(t, http.StatusOK, testFeed).Config.Handler
+	if err := scrapeFeeds(s); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Store.GetPostByURL(context.Background(), "https://example.com/old"); err != nil {
+		t.Errorf("post that left the feed was still remembered as pruned: %v", err)
+	}
+}
+
+func TestPrunePostsOutput(t *testing.T) {
+	srv := feedServer(t, http.StatusOK, testFeed)
+	other := feedServer(t, http.StatusOK, strings.ReplaceAll(testFeed, "example.com", "example.org"))
+	s := newTestState(t, srv)
+	alice := createUser(t, s, "alice")
+
+	for _, url := range []string{srv.URL, other.URL} {
+		if err := HandlerAddFeed(s, cli.Command{Arguments: []string{"Same", url}}, alice); err != nil {
+			t.Fatal(err)
+		}
+		if err := scrapeFeeds(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	retention := config.RetentionConfig{MaxPostsPerFeed: 1, Auto: true}
+	out := captureStdout(t, func() {
+		if err := prunePosts(s, retention); err != nil {
+			t.Fatal(err)
+		}
+	})
+	for _, url := range []string{srv.URL, other.URL} {
+		if want := fmt.Sprintf("* Same (%s): 1 posts removed\n", url); !strings.Contains(out, want) {
+			t.Errorf("output %q does not contain %q", out, want)
+		}
+	}
+
+	// agg prunes on every tick and only speaks up when it removed something.
+	out = captureStdout(t, func() {
+		if err := prunePosts(s, retention); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if out != "" {
+		t.Errorf("auto prune with nothing to remove printed %q", out)
+	}
+}
+
+// captureStdout returns what f printed to standard output.
+func captureStdout(t *testing.T, f func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	f()
+
+	w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
@@ -3,17 +3,24 @@ package handlers
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/lmilojevicc/gator/internal/cli"
 	"github.com/lmilojevicc/gator/internal/database"
+	"github.com/lmilojevicc/gator/internal/filter"
+	"github.com/lmilojevicc/gator/internal/notify"
 	"github.com/lmilojevicc/gator/internal/rss"
 	"github.com/lmilojevicc/gator/internal/state"
+	"github.com/lmilojevicc/gator/internal/store"
 )
 
 func HandlerAggregate(s *state.State, cmd cli.Command) error {
@@ -34,6 +41,11 @@ func HandlerAggregate(s *state.State, cmd cli.Command) error {
 		if err := scrapeFeeds(s); err != nil {
 			fmt.Fprintf(os.Stderr, "Error scraping feeds: %v\n", err)
 		}
+		if s.Cfg.Retention.Auto {
+			if err := prunePosts(s, s.Cfg.Retention); err != nil {
+				fmt.Fprintf(os.Stderr, "Error pruning posts: %v\n", err)
+			}
+		}
 	}
 }
 
@@ -45,7 +57,7 @@ func HandlerAddFeed(s *state.State, cmd cli.Command, dbUser database.User) error
 	feedName := cmd.Arguments[0]
 	feedURL := cmd.Arguments[1]
 
-	createdFeed, err := s.Queries.CreateFeed(context.Background(), database.CreateFeedParams{
+	createdFeed, err := s.Store.CreateFeed(context.Background(), database.CreateFeedParams{
 		ID:     uuid.New(),
 		Name:   feedName,
 		Url:    feedURL,
@@ -55,7 +67,7 @@ func HandlerAddFeed(s *state.State, cmd cli.Command, dbUser database.User) error
 		return fmt.Errorf("creating feed: %w", err)
 	}
 
-	_, err = s.Queries.CreateFeedFollow(context.Background(), database.CreateFeedFollowParams{
+	_, err = s.Store.CreateFeedFollow(context.Background(), database.CreateFeedFollowParams{
 		ID:     uuid.New(),
 		UserID: dbUser.ID,
 		FeedID: createdFeed.ID,
@@ -68,76 +80,291 @@ func HandlerAddFeed(s *state.State, cmd cli.Command, dbUser database.User) error
 }
 
 func HandlerFeeds(s *state.State, cmd cli.Command) error {
-	feeds, err := s.Queries.GetAllFeeds(context.Background())
+	feeds, err := s.Store.GetAllFeeds(context.Background())
 	if err != nil {
 		return fmt.Errorf("getting feeds: %w", err)
 	}
 
 	for _, feed := range feeds {
+		fmt.Printf("* ID:\t%s\n", feed.ID)
 		fmt.Printf("* Name:\t%s\n", feed.Name)
 		fmt.Printf("* URL:\t%s\n", feed.Url)
-		user, err := s.Queries.GetUserByID(context.Background(), feed.UserID)
+		user, err := s.Store.GetUserByID(context.Background(), feed.UserID)
 		if err != nil {
 			return fmt.Errorf("getting user: %w", err)
 		}
 		fmt.Printf("* User:\t%s\n", user.Name)
+		if feed.DeadAt.Valid {
+			fmt.Printf("* Dead:\tsince %s\n", feed.DeadAt.Time.Format("2006-01-02"))
+		}
 	}
 
 	return nil
 }
 
 func scrapeFeeds(s *state.State) error {
-	tx, err := s.Conn.BeginTx(context.Background(), nil)
+	feed, err := claimNextFeed(s)
 	if err != nil {
-		return fmt.Errorf("beginning transaction: %w", err)
+		return err
 	}
-	defer tx.Rollback()
 
-	qtx := s.Queries.WithTx(tx)
-
-	nextFeedToFetch, err := qtx.GetNextFeedToFetch(context.Background())
-	if err == sql.ErrNoRows {
-		return fmt.Errorf("no feeds to fetch: %w", err)
+	var newPosts []notify.Post
+	err = s.Store.InTx(context.Background(), func(qtx store.Store) error {
+		var err error
+		newPosts, err = scrapeFeed(s, qtx, feed)
+		return err
+	})
+	if err != nil {
+		return recordFetchFailure(s, feed, err)
 	}
+
+	// Webhooks are called in the background once the posts are committed, so
+	// that slow or retried deliveries hold up neither the transaction nor the
+	// next fetch.
+	s.Notifier.Send(feed, newPosts, reportDeliveries)
+
+	return nil
+}
+
+func reportDeliveries(deliveries []notify.Delivery, err error) {
 	if err != nil {
-		return fmt.Errorf("getting next feed to fetch: %w", err)
+		fmt.Fprintf(os.Stderr, "Error sending notifications: %v\n", err)
 	}
+	for _, delivery := range deliveries {
+		if delivery.Err != nil {
+			fmt.Printf("Warning: notifying %s failed after %d attempts: %v\n",
+				delivery.Rule.WebhookUrl, delivery.Attempts, delivery.Err)
+		}
+	}
+}
+
+// claimNextFeed returns the feed due next and marks it fetched in a
+// transaction of its own, so that a feed whose fetch fails goes to the back of
+// the queue instead of being picked again on every tick.
+func claimNextFeed(s *state.State) (database.Feed, error) {
+	var feed database.Feed
+	err := s.Store.InTx(context.Background(), func(qtx store.Store) error {
+		var err error
+		feed, err = qtx.GetNextFeedToFetch(context.Background())
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("no feeds to fetch: %w", err)
+		}
+		if err != nil {
+			return fmt.Errorf("getting next feed to fetch: %w", err)
+		}
+
+		err = qtx.MarkFeedFetched(context.Background(), feed.ID)
+		if err != nil {
+			return fmt.Errorf("marking feed fetched: %w", err)
+		}
+		return nil
+	})
+	return feed, err
+}
 
-	err = qtx.MarkFeedFetched(context.Background(), nextFeedToFetch.ID)
+// recordFetchFailure adds fetchErr to the history of feed and returns it.
+func recordFetchFailure(s *state.State, feed database.Feed, fetchErr error) error {
+	err := s.Store.CreateFeedHistory(context.Background(), database.CreateFeedHistoryParams{
+		ID:     uuid.New(),
+		FeedID: feed.ID,
+		Event:  "fetch_failed",
+		Detail: fetchErr.Error(),
+	})
 	if err != nil {
-		return fmt.Errorf("marking feed fetched: %w", err)
+		return errors.Join(fetchErr, fmt.Errorf("recording feed history: %w", err))
 	}
+	return fetchErr
+}
 
-	feed, err := rss.FetchFeed(context.Background(), nextFeedToFetch.Url)
+// scrapeFeed fetches nextFeedToFetch and returns the posts that were new.
+func scrapeFeed(s *state.State, qtx store.Store, nextFeedToFetch database.Feed) ([]notify.Post, error) {
+	auth, err := feedAuth(qtx, nextFeedToFetch.ID, s.Cfg.AllowedSecrets)
 	if err != nil {
-		return fmt.Errorf("fetching feed: %w", err)
+		return nil, err
 	}
 
-	for _, item := range feed.Channel.Items {
+	posts := database.CreatePostsParams{FeedID: nextFeedToFetch.ID}
+	collectPost := func(item rss.RSSItem) error {
 		publishedAt, err := parsePubDate(item.PubDate)
 		if err != nil {
 			fmt.Printf("Warning: skipping item %q with bad date: %v\n", item.Title, err)
-			continue
+			return nil
 		}
 
-		_, err = qtx.CreatePost(context.Background(), database.CreatePostParams{
-			ID:          uuid.New(),
-			Title:       sql.NullString{String: item.Title, Valid: item.Title != ""},
-			Url:         item.Link,
-			Description: sql.NullString{String: item.Description, Valid: item.Description != ""},
-			PublishedAt: sql.NullTime{Time: publishedAt, Valid: !publishedAt.IsZero()},
-			FeedID:      nextFeedToFetch.ID,
-		})
+		posts.Ids = append(posts.Ids, uuid.New())
+		posts.Titles = append(posts.Titles, item.Title)
+		posts.Urls = append(posts.Urls, item.Link)
+		posts.Descriptions = append(posts.Descriptions, item.Description)
+		posts.PublishedAts = append(posts.PublishedAts, publishedAt)
+		posts.Authors = append(posts.Authors, item.Author)
+		posts.Categories = append(posts.Categories, strings.Join(item.Categories, "\n"))
 
-		if err == sql.ErrNoRows {
+		return nil
+	}
+
+	result, err := s.Fetcher.FetchFeed(context.Background(), nextFeedToFetch.Url, auth, collectPost)
+	if errors.Is(err, rss.ErrGone) {
+		return nil, markFeedDead(qtx, nextFeedToFetch)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("fetching feed: %w", err)
+	}
+
+	if result.Truncated {
+		fmt.Printf("Warning: %q has more than %d items, the rest were skipped\n", nextFeedToFetch.Name, result.Items)
+	}
+
+	err = qtx.CreateFeedFetch(context.Background(), database.CreateFeedFetchParams{
+		ID:                uuid.New(),
+		FeedID:            nextFeedToFetch.ID,
+		ContentEncoding:   result.ContentEncoding,
+		CompressedBytes:   result.CompressedBytes,
+		UncompressedBytes: result.UncompressedBytes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("recording feed fetch: %w", err)
+	}
+
+	// Pruned URLs that are still in the feed stay pruned, the others are
+	// forgotten on the next prune.
+	err = qtx.MarkPrunedPostsSeen(context.Background(), database.MarkPrunedPostsSeenParams{
+		FeedID: nextFeedToFetch.ID,
+		Urls:   posts.Urls,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marking pruned posts seen: %w", err)
+	}
+
+	err = trackRedirect(qtx, nextFeedToFetch, result.PermanentURL, s.Cfg.RedirectThreshold)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(posts.Ids) == 0 {
+		return nil, nil
+	}
+
+	created, err := qtx.CreatePosts(context.Background(), posts)
+	if err != nil {
+		return nil, fmt.Errorf("creating posts: %w", err)
+	}
+	fmt.Printf("%d new posts from %q\n", len(created), nextFeedToFetch.Name)
+
+	return createdPosts(posts, created), nil
+}
+
+// createdPosts picks the posts with the created IDs out of params. Those are
+// new even across pruning, since CreatePosts skips the URLs of pruned posts.
+func createdPosts(params database.CreatePostsParams, created []uuid.UUID) []notify.Post {
+	var posts []notify.Post
+	for i, id := range params.Ids {
+		if !slices.Contains(created, id) {
 			continue
 		}
+		posts = append(posts, notify.Post{
+			Title:       params.Titles[i],
+			URL:         params.Urls[i],
+			Description: params.Descriptions[i],
+			PublishedAt: params.PublishedAts[i],
+			Author:      params.Authors[i],
+			Categories:  params.Categories[i],
+		})
+	}
+	return posts
+}
+
+func markFeedDead(qtx database.Querier, feed database.Feed) error {
+	err := qtx.MarkFeedDead(context.Background(), feed.ID)
+	if err != nil {
+		return fmt.Errorf("marking feed dead: %w", err)
+	}
+
+	err = qtx.CreateFeedHistory(context.Background(), database.CreateFeedHistoryParams{
+		ID:     uuid.New(),
+		FeedID: feed.ID,
+		Event:  "gone",
+		Detail: fmt.Sprintf("%s returned 410 Gone, feed will no longer be fetched", feed.Url),
+	})
+	if err != nil {
+		return fmt.Errorf("recording feed history: %w", err)
+	}
+
+	fmt.Printf("Feed %q is gone and will no longer be fetched\n", feed.Name)
+
+	return nil
+}
+
+// trackRedirect counts consecutive permanent redirects of feed to the same
+// URL and moves the feed there once the count reaches threshold.
+func trackRedirect(qtx database.Querier, feed database.Feed, permanentURL string, threshold int) error {
+	if permanentURL == "" {
+		if !feed.RedirectUrl.Valid {
+			return nil
+		}
+		err := qtx.ClearFeedRedirect(context.Background(), feed.ID)
 		if err != nil {
-			return fmt.Errorf("creating post: %w", err)
+			return fmt.Errorf("clearing feed redirect: %w", err)
 		}
+		return nil
+	}
+
+	count, err := qtx.RecordFeedRedirect(context.Background(), database.RecordFeedRedirectParams{
+		ID:          feed.ID,
+		RedirectUrl: sql.NullString{String: permanentURL, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("recording feed redirect: %w", err)
+	}
+
+	if int(count) < threshold {
+		return nil
 	}
 
-	return tx.Commit()
+	// Another feed already has the new URL, so this one cannot move there.
+	// The redirect stays recorded and the feed is fetched through it; the
+	// conflict is recorded once for the owner to resolve.
+	other, err := qtx.GetFeedByURL(context.Background(), permanentURL)
+	if err == nil && other.ID != feed.ID {
+		if int(count) != threshold {
+			return nil
+		}
+		err = qtx.CreateFeedHistory(context.Background(), database.CreateFeedHistoryParams{
+			ID:     uuid.New(),
+			FeedID: feed.ID,
+			Event:  "redirect_conflict",
+			Detail: fmt.Sprintf("%s redirects to %s, which is already feed %q", feed.Url, permanentURL, other.Name),
+		})
+		if err != nil {
+			return fmt.Errorf("recording feed history: %w", err)
+		}
+		fmt.Printf("Warning: feed %q redirects to %s, which is already feed %q\n", feed.Name, permanentURL, other.Name)
+		return nil
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("getting feed by url: %w", err)
+	}
+
+	_, err = qtx.UpdateFeedURL(context.Background(), database.UpdateFeedURLParams{
+		ID:  feed.ID,
+		Url: permanentURL,
+	})
+	if err != nil {
+		return fmt.Errorf("updating feed url: %w", err)
+	}
+
+	err = qtx.CreateFeedHistory(context.Background(), database.CreateFeedHistoryParams{
+		ID:     uuid.New(),
+		FeedID: feed.ID,
+		Event:  "url_changed",
+		Detail: fmt.Sprintf("%s -> %s after %d permanent redirects", feed.Url, permanentURL, count),
+	})
+	if err != nil {
+		return fmt.Errorf("recording feed history: %w", err)
+	}
+
+	fmt.Printf("Feed %q moved to %s\n", feed.Name, permanentURL)
+
+	return nil
 }
 
 func parsePubDate(dateStr string) (time.Time, error) {
@@ -160,22 +387,34 @@ func parsePubDate(dateStr string) (time.Time, error) {
 }
 
 func HandlerBrowse(s *state.State, cmd cli.Command, user database.User) error {
-	limit := int32(2)
+	flags := flag.NewFlagSet(cmd.Name, flag.ContinueOnError)
+	category := flags.String("category", "", "only show posts from the feeds in this category")
+	if err := flags.Parse(cmd.Arguments); err != nil || flags.NArg() > 1 {
+		return fmt.Errorf("usage: %s [--category <name>] [limit]", cmd.Name)
+	}
+
+	limit := 2
 
-	if len(cmd.Arguments) == 1 {
-		parsed, err := strconv.Atoi(cmd.Arguments[0])
+	if flags.NArg() == 1 {
+		parsed, err := strconv.Atoi(flags.Arg(0))
 		if err != nil {
 			return fmt.Errorf("invalid limit: %w", err)
 		}
-		limit = int32(parsed)
+		limit = parsed
 	}
 
-	dbPosts, err := s.Queries.GetPostsByUser(context.Background(), database.GetPostsByUserParams{
-		UserID: user.ID,
-		Limit:  limit,
-	})
+	params := database.GetPostsForUserParams{UserID: user.ID}
+	if *category != "" {
+		dbCategory, err := categoryByName(s, user, *category)
+		if err != nil {
+			return err
+		}
+		params.CategoryID = uuid.NullUUID{UUID: dbCategory.ID, Valid: true}
+	}
+
+	dbPosts, err := filteredPosts(s, params, limit)
 	if err != nil {
-		return fmt.Errorf("getting posts: %w", err)
+		return err
 	}
 
 	for _, post := range dbPosts {
@@ -191,3 +430,21 @@ func HandlerBrowse(s *state.State, cmd cli.Command, user database.User) error {
 
 	return nil
 }
+
+// filteredPosts returns the newest limit posts matching params that pass the
+// filter rules of the user. The page of params is ignored.
+func filteredPosts(s *state.State, params database.GetPostsForUserParams, limit int) ([]database.GetPostsForUserRow, error) {
+	f, err := filter.Load(context.Background(), s.Store, params.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	posts, err := filter.Page(f, 0, limit, filter.FromPostsForUser, func(offset, limit int) ([]database.GetPostsForUserRow, error) {
+		params.PageOffset, params.PageSize = int32(offset), int32(limit)
+		return s.Store.GetPostsForUser(context.Background(), params)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getting posts: %w", err)
+	}
+	return posts, nil
+}
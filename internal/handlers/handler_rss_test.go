@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/lmilojevicc/gator/internal/cli"
+	"github.com/lmilojevicc/gator/internal/database"
+	"github.com/lmilojevicc/gator/internal/middleware"
+)
+
+func TestParsePubDate(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Time
+		wantErr bool
+	}{
+		{input: "Mon, 02 Jan 2006 15:04:05 MST", want: time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{input: "Mon, 02 Jan 2006 15:04:05 -0700", want: time.Date(2006, 1, 2, 22, 4, 5, 0, time.UTC)},
+		{input: "2006-01-02T15:04:05+02:00", want: time.Date(2006, 1, 2, 13, 4, 5, 0, time.UTC)},
+		{input: "2006-01-02T15:04:05Z", want: time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{input: "Mon, 2 Jan 2006 15:04:05 MST", want: time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{input: "02 Jan 2006 15:04:05 MST", want: time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{input: "", wantErr: true},
+		{input: "yesterday", wantErr: true},
+		{input: "2006-01-02", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parsePubDate(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parsePubDate(%q) = %v, want an error", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parsePubDate(%q): %v", tt.input, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parsePubDate(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddFeedFollowAggBrowse(t *testing.T) {
+	srv := feedServer(t, http.StatusOK, testFeed)
+	s := newTestState(t, srv)
+	ctx := context.Background()
+
+	createUser(t, s, "alice")
+	bob := createUser(t, s, "bob")
+
+	if err := HandlerLogin(s, cli.Command{Name: "login", Arguments: []string{"alice"}}); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	addFeed := middleware.LoggedIn(HandlerAddFeed)
+	if err := addFeed(s, cli.Command{Name: "addfeed", Arguments: []string{"Test", srv.URL}}); err != nil {
+		t.Fatalf("addfeed: %v", err)
+	}
+
+	if err := HandlerFollow(s, cli.Command{Name: "follow", Arguments: []string{srv.URL}}, bob); err != nil {
+		t.Fatalf("follow: %v", err)
+	}
+
+	if err := scrapeFeeds(s); err != nil {
+		t.Fatalf("scraping feeds: %v", err)
+	}
+
+	posts, err := s.Store.GetPostsByUser(ctx, database.GetPostsByUserParams{UserID: bob.ID, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The undated item is skipped and the newest post comes first.
+	if len(posts) != 2 || posts[0].Title.String != "New" || posts[1].Title.String != "Old" {
+		t.Fatalf("posts = %+v, want New and Old", posts)
+	}
+
+	if err := HandlerBrowse(s, cli.Command{Name: "browse", Arguments: []string{"1"}}, bob); err != nil {
+		t.Fatalf("browse: %v", err)
+	}
+
+	// A second fetch of the same feed must not duplicate posts.
+	if err := scrapeFeeds(s); err != nil {
+		t.Fatalf("scraping feeds again: %v", err)
+	}
+	posts, err = s.Store.GetPostsByUser(ctx, database.GetPostsByUserParams{UserID: bob.ID, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(posts) != 2 {
+		t.Errorf("got %d posts after refetch, want 2", len(posts))
+	}
+
+	if err := HandlerUnfollow(s, cli.Command{Name: "unfollow", Arguments: []string{srv.URL}}, bob); err != nil {
+		t.Fatalf("unfollow: %v", err)
+	}
+	posts, err = s.Store.GetPostsByUser(ctx, database.GetPostsByUserParams{UserID: bob.ID, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(posts) != 0 {
+		t.Errorf("got %d posts after unfollowing, want none", len(posts))
+	}
+}
+
+func TestScrapeFeedsGone(t *testing.T) {
+	srv := feedServer(t, http.StatusGone, "")
+	s := newTestState(t, srv)
+	alice := createUser(t, s, "alice")
+
+	if err := HandlerAddFeed(s, cli.Command{Arguments: []string{"Gone", srv.URL}}, alice); err != nil {
+		t.Fatal(err)
+	}
+	if err := scrapeFeeds(s); err != nil {
+		t.Fatalf("scraping feeds: %v", err)
+	}
+
+	feed, err := s.Store.GetFeedByURL(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !feed.DeadAt.Valid {
+		t.Error("feed was not marked dead")
+	}
+
+	history, err := s.Store.GetFeedHistory(context.Background(), feed.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 || history[0].Event != "gone" {
+		t.Errorf("history = %+v, want one gone event", history)
+	}
+
+	// Dead feeds are no longer fetched.
+	if err := scrapeFeeds(s); err == nil {
+		t.Error("expected no feeds to fetch")
+	}
+}
+
+func TestScrapeFeedsFetchError(t *testing.T) {
+	srv := feedServer(t, http.StatusInternalServerError, "")
+	working := feedServer(t, http.StatusOK, testFeed)
+	s := newTestState(t, srv)
+	alice := createUser(t, s, "alice")
+
+	if err := HandlerAddFeed(s, cli.Command{Arguments: []string{"Broken", srv.URL}}, alice); err != nil {
+		t.Fatal(err)
+	}
+	if err := HandlerAddFeed(s, cli.Command{Arguments: []string{"Working", working.URL}}, alice); err != nil {
+		t.Fatal(err)
+	}
+	if err := scrapeFeeds(s); err == nil {
+		t.Fatal("expected a fetch error")
+	}
+
+	feed, err := s.Store.GetFeedByURL(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !feed.LastFetchedAt.Valid {
+		t.Error("last_fetched_at was not updated by a failed fetch")
+	}
+	history, err := s.Store.GetFeedHistory(context.Background(), feed.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 || history[0].Event != "fetch_failed" {
+		t.Errorf("history = %+v, want one fetch_failed event", history)
+	}
+
+	// The broken feed goes to the back of the queue.
+	if err := scrapeFeeds(s); err != nil {
+		t.Fatalf("second scrape: %v", err)
+	}
+	feed, err = s.Store.GetFeedByURL(context.Background(), working.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !feed.LastFetchedAt.Valid {
+		t.Error("the working feed was not fetched after the broken one")
+	}
+}
+
+func TestScrapeFeedsFollowsPermanentRedirects(t *testing.T) {
+	target := feedServer(t, http.StatusOK, testFeed)
+	srv := feedServer(t, http.StatusOK, "")
+	srv.Config.Handler = http.RedirectHandler(target.URL, http.StatusMovedPermanently)
+
+	s := newTestState(t, srv)
+	s.Cfg.RedirectThreshold = 2
+	alice := createUser(t, s, "alice")
+
+	if err := HandlerAddFeed(s, cli.Command{Arguments: []string{"Moved", srv.URL}}, alice); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range 2 {
+		if err := scrapeFeeds(s); err != nil {
+			t.Fatalf("scrape %d: %v", i, err)
+		}
+	}
+
+	if _, err := s.Store.GetFeedByURL(context.Background(), target.URL); err != nil {
+		t.Errorf("feed was not moved to %s: %v", target.URL, err)
+	}
+}
+
+func TestScrapeFeedsRedirectToExistingFeed(t *testing.T) {
+	target := feedServer(t, http.StatusOK, testFeed)
+	srv := feedServer(t, http.StatusOK, "")
+	srv.Config.Handler = http.RedirectHandler(target.URL, http.StatusMovedPermanently)
+
+	s := newTestState(t, srv)
+	s.Cfg.RedirectThreshold = 2
+	alice := createUser(t, s, "alice")
+
+	if err := HandlerAddFeed(s, cli.Command{Arguments: []string{"Moved", srv.URL}}, alice); err != nil {
+		t.Fatal(err)
+	}
+	if err := HandlerAddFeed(s, cli.Command{Arguments: []string{"Target", target.URL}}, alice); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range 6 {
+		if err := scrapeFeeds(s); err != nil {
+			t.Fatalf("scrape %d: %v", i, err)
+		}
+	}
+
+	feed, err := s.Store.GetFeedByURL(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatalf("feed left its URL although the target is taken: %v", err)
+	}
+	history, err := s.Store.GetFeedHistory(context.Background(), feed.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 1 || history[0].Event != "redirect_conflict" {
+		t.Errorf("history = %+v, want one redirect_conflict", history)
+	}
+}
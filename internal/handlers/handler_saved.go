@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lmilojevicc/gator/internal/cli"
+	"github.com/lmilojevicc/gator/internal/database"
+	"github.com/lmilojevicc/gator/internal/state"
+)
+
+func HandlerSave(s *state.State, cmd cli.Command, dbUser database.User) error {
+	if len(cmd.Arguments) != 1 {
+		return fmt.Errorf("usage: %s <post_url>", cmd.Name)
+	}
+
+	dbPost, err := s.Store.GetPostByURL(context.Background(), cmd.Arguments[0])
+	if err != nil {
+		return fmt.Errorf("getting post by url: %w", err)
+	}
+
+	err = s.Store.SavePost(context.Background(), database.SavePostParams{
+		UserID: dbUser.ID,
+		PostID: dbPost.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("saving post: %w", err)
+	}
+
+	fmt.Printf("Saved %q, it will never be pruned\n", dbPost.Title.String)
+
+	return nil
+}
+
+func HandlerUnsave(s *state.State, cmd cli.Command, dbUser database.User) error {
+	if len(cmd.Arguments) != 1 {
+		return fmt.Errorf("usage: %s <post_url>", cmd.Name)
+	}
+
+	dbPost, err := s.Store.GetPostByURL(context.Background(), cmd.Arguments[0])
+	if err != nil {
+		return fmt.Errorf("getting post by url: %w", err)
+	}
+
+	unsaved, err := s.Store.UnsavePost(context.Background(), database.UnsavePostParams{
+		UserID: dbUser.ID,
+		PostID: dbPost.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("unsaving post: %w", err)
+	}
+	if unsaved == 0 {
+		return fmt.Errorf("post %q is not saved", dbPost.Url)
+	}
+
+	fmt.Printf("Removed %q from saved posts\n", dbPost.Title.String)
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/lmilojevicc/gator/internal/api"
+	"github.com/lmilojevicc/gator/internal/cli"
+	"github.com/lmilojevicc/gator/internal/fever"
+	"github.com/lmilojevicc/gator/internal/state"
+	"github.com/lmilojevicc/gator/internal/web"
+)
+
+func HandlerServe(s *state.State, cmd cli.Command) error {
+	flags := flag.NewFlagSet(cmd.Name, flag.ContinueOnError)
+	addr := flags.String("addr", ":8080", "address to listen on")
+	if err := flags.Parse(cmd.Arguments); err != nil || flags.NArg() != 0 {
+		return fmt.Errorf("usage: %s [--addr host:port]", cmd.Name)
+	}
+
+	mux := http.NewServeMux()
+	apiServer := api.New(s.Store)
+	apiServer.OpenRegistration = s.Cfg.OpenRegistration
+	mux.Handle("/api/", apiServer.Handler())
+	feverAPI := fever.New(s.Store).Handler()
+	mux.Handle("/fever", feverAPI)
+	mux.Handle("/fever/", feverAPI)
+	mux.Handle("/", web.New(s.Store).Handler())
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.ListenAndServe()
+	}()
+	fmt.Printf("Serving the reader and API on %s\n", *addr)
+
+	select {
+	case err := <-errs:
+		return fmt.Errorf("serving: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("shutting down: %w", err)
+	}
+
+	return nil
+}
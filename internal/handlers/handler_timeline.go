@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/lmilojevicc/gator/internal/cli"
+	"github.com/lmilojevicc/gator/internal/database"
+	"github.com/lmilojevicc/gator/internal/filter"
+	"github.com/lmilojevicc/gator/internal/state"
+	"github.com/lmilojevicc/gator/internal/syndication"
+)
+
+// HandlerTimeline writes the posts from every feed the user follows that pass
+// their filter rules to stdout as an RSS 2.0 feed, or Atom with --atom.
+func HandlerTimeline(s *state.State, cmd cli.Command, dbUser database.User) error {
+	flags := flag.NewFlagSet(cmd.Name, flag.ContinueOnError)
+	atom := flags.Bool("atom", false, "write Atom instead of RSS 2.0")
+	limit := flags.Int("limit", 50, "number of posts to include")
+	if err := flags.Parse(cmd.Arguments); err != nil || flags.NArg() != 0 || *limit < 1 {
+		return fmt.Errorf("usage: %s [--atom] [--limit n]", cmd.Name)
+	}
+
+	f, err := filter.Load(context.Background(), s.Store, dbUser.ID)
+	if err != nil {
+		return err
+	}
+
+	dbPosts, err := filter.Page(f, 0, *limit, filter.FromPostsByUser, func(offset, limit int) ([]database.GetPostsByUserRow, error) {
+		return s.Store.GetPostsByUser(context.Background(), database.GetPostsByUserParams{
+			UserID: dbUser.ID,
+			Limit:  int32(limit),
+			Offset: int32(offset),
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("getting posts: %w", err)
+	}
+
+	feed := syndication.Timeline(dbUser, dbPosts)
+	if *atom {
+		return syndication.WriteAtom(os.Stdout, feed)
+	}
+	return syndication.WriteRSS(os.Stdout, feed)
+}
@@ -2,10 +2,15 @@ package handlers
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"flag"
 	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
 
+	"github.com/lmilojevicc/gator/internal/auth"
 	"github.com/lmilojevicc/gator/internal/cli"
 	"github.com/lmilojevicc/gator/internal/database"
 	"github.com/lmilojevicc/gator/internal/state"
@@ -17,14 +22,30 @@ func HandlerLogin(s *state.State, cmd cli.Command) error {
 	}
 
 	username := cmd.Arguments[0]
-	dbUser, err := s.Queries.GetUserByName(context.Background(), username)
+	dbUser, err := s.Store.GetUserByName(context.Background(), username)
 	if err != nil {
 		return fmt.Errorf("getting user: %w", err)
 	}
 
-	err = s.Cfg.SetUser(dbUser.Name)
+	if err := checkPassword(dbUser, "Password: "); err != nil {
+		return err
+	}
+
+	if s.Cfg.SessionToken != "" {
+		err = s.Store.DeleteSession(context.Background(), auth.HashToken(s.Cfg.SessionToken))
+		if err != nil {
+			return fmt.Errorf("ending previous session: %w", err)
+		}
+	}
+
+	token, err := auth.StartSession(context.Background(), s.Store, dbUser.ID)
+	if err != nil {
+		return err
+	}
+
+	err = s.Cfg.SetSession(token)
 	if err != nil {
-		return fmt.Errorf("set user: %w", err)
+		return fmt.Errorf("set session: %w", err)
 	}
 
 	return nil
@@ -37,9 +58,15 @@ func HandlerRegister(s *state.State, cmd cli.Command) error {
 
 	username := cmd.Arguments[0]
 
-	_, err := s.Queries.CreateUser(context.Background(), database.CreateUserParams{
-		ID:   uuid.New(),
-		Name: username,
+	passwordHash, err := newPassword()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Store.CreateUser(context.Background(), database.CreateUserParams{
+		ID:           uuid.New(),
+		Name:         username,
+		PasswordHash: passwordHash,
 	})
 	if err != nil {
 		return fmt.Errorf("creating user: %w", err)
@@ -48,8 +75,113 @@ func HandlerRegister(s *state.State, cmd cli.Command) error {
 	return nil
 }
 
-func HandlerReset(s *state.State, cmd cli.Command) error {
-	err := s.Queries.ResetUsers(context.Background())
+func HandlerPasswd(s *state.State, cmd cli.Command, dbUser database.User) error {
+	if len(cmd.Arguments) != 0 {
+		return fmt.Errorf("usage: %s", cmd.Name)
+	}
+
+	if err := checkPassword(dbUser, "Current password: "); err != nil {
+		return err
+	}
+
+	passwordHash, err := newPassword()
+	if err != nil {
+		return err
+	}
+
+	err = s.Store.SetUserPassword(context.Background(), database.SetUserPasswordParams{
+		ID:           dbUser.ID,
+		PasswordHash: passwordHash,
+	})
+	if err != nil {
+		return fmt.Errorf("setting password: %w", err)
+	}
+
+	err = s.Store.DeleteOtherSessions(context.Background(), database.DeleteOtherSessionsParams{
+		UserID:    dbUser.ID,
+		TokenHash: auth.HashToken(s.Cfg.SessionToken),
+	})
+	if err != nil {
+		return fmt.Errorf("ending other sessions: %w", err)
+	}
+
+	if passwordHash.Valid {
+		fmt.Println("Password changed, other sessions were logged out")
+	} else {
+		fmt.Println("Password removed, other sessions were logged out")
+	}
+
+	// The Fever key is derived from the old password and would keep working.
+	if dbUser.FeverKeyHash.Valid {
+		err = s.Store.SetUserFeverKey(context.Background(), database.SetUserFeverKeyParams{ID: dbUser.ID})
+		if err != nil {
+			return fmt.Errorf("disabling fever api: %w", err)
+		}
+		fmt.Println("Fever API disabled, enable it again with the fever command")
+	}
+
+	return nil
+}
+
+// checkPassword prompts for the password of dbUser, if it has one.
+func checkPassword(dbUser database.User, prompt string) error {
+	if !dbUser.PasswordHash.Valid {
+		return nil
+	}
+
+	password, err := readPassword(prompt)
+	if err != nil {
+		return err
+	}
+
+	return auth.CheckPassword(dbUser.PasswordHash.String, password)
+}
+
+// newPassword prompts for a new password twice and returns its hash. An empty
+// password means the account has none.
+func newPassword() (sql.NullString, error) {
+	password, err := readPassword("New password (leave empty for none): ")
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	if password == "" {
+		return sql.NullString{}, nil
+	}
+
+	repeated, err := readPassword("Repeat password: ")
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	if repeated != password {
+		return sql.NullString{}, errors.New("passwords do not match")
+	}
+
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: hash, Valid: true}, nil
+}
+
+func HandlerReset(s *state.State, cmd cli.Command, dbUser database.User) error {
+	flags := flag.NewFlagSet(cmd.Name, flag.ContinueOnError)
+	yes := flags.Bool("yes", false, "do not ask for confirmation")
+	if err := flags.Parse(cmd.Arguments); err != nil || flags.NArg() != 0 {
+		return fmt.Errorf("usage: %s [--yes]", cmd.Name)
+	}
+
+	if !*yes {
+		ok, err := confirmTyped("This deletes every user, feed and post.", "reset")
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Aborted")
+			return nil
+		}
+	}
+
+	err := s.Store.ResetUsers(context.Background())
 	if err != nil {
 		return fmt.Errorf("reseting users: %w", err)
 	}
@@ -58,15 +190,27 @@ func HandlerReset(s *state.State, cmd cli.Command) error {
 }
 
 func HandlerUsers(s *state.State, cmd cli.Command) error {
-	dbUsers, err := s.Queries.GetUsers(context.Background())
+	dbUsers, err := s.Store.GetUsers(context.Background())
 	if err != nil {
 		return fmt.Errorf("getting users: %w", err)
 	}
 
-	currentUser := s.Cfg.CurrentUserName
+	var currentUser database.User
+	if s.Cfg.SessionToken != "" {
+		currentUser, _ = s.Store.GetUserBySession(context.Background(), auth.HashToken(s.Cfg.SessionToken))
+	}
+
 	for _, user := range dbUsers {
-		if user.Name == currentUser {
-			fmt.Printf("* %s (current)\n", user.Name)
+		var marks []string
+		if user.IsAdmin {
+			marks = append(marks, "admin")
+		}
+		if user.ID == currentUser.ID {
+			marks = append(marks, "current")
+		}
+
+		if len(marks) > 0 {
+			fmt.Printf("* %s (%s)\n", user.Name, strings.Join(marks, ", "))
 			continue
 		}
 		fmt.Printf("* %s\n", user.Name)
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/lmilojevicc/gator/internal/auth"
+	"github.com/lmilojevicc/gator/internal/cli"
+	"github.com/lmilojevicc/gator/internal/config"
+	"github.com/lmilojevicc/gator/internal/fever"
+	"github.com/lmilojevicc/gator/internal/middleware"
+	"github.com/lmilojevicc/gator/internal/state"
+)
+
+func TestRegisterAndLogin(t *testing.T) {
+	s := newTestState(t, nil)
+
+	answerPasswords(t, "hunter2", "hunter2")
+	if err := HandlerRegister(s, cli.Command{Name: "register", Arguments: []string{"alice"}}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	answerPasswords(t, "")
+	if err := HandlerRegister(s, cli.Command{Name: "register", Arguments: []string{"alice"}}); err == nil {
+		t.Error("registering a taken name succeeded")
+	}
+
+	if err := HandlerLogin(s, cli.Command{Name: "login", Arguments: []string{"bob"}}); err == nil {
+		t.Error("logging in as an unknown user succeeded")
+	}
+
+	answerPasswords(t, "wrong")
+	if err := HandlerLogin(s, cli.Command{Name: "login", Arguments: []string{"alice"}}); err == nil {
+		t.Error("logging in with a wrong password succeeded")
+	}
+	if s.Cfg.SessionToken != "" {
+		t.Error("a failed login stored a session token")
+	}
+
+	answerPasswords(t, "hunter2")
+	if err := HandlerLogin(s, cli.Command{Name: "login", Arguments: []string{"alice"}}); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+
+	saved, err := config.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.SessionToken == "" || saved.SessionToken == "alice" {
+		t.Errorf("saved session token = %q, want a random token", saved.SessionToken)
+	}
+
+	user, err := s.Store.GetUserBySession(context.Background(), auth.HashToken(saved.SessionToken))
+	if err != nil || user.Name != "alice" {
+		t.Errorf("session belongs to %q (%v), want alice", user.Name, err)
+	}
+}
+
+func TestRegisterPasswordsMustMatch(t *testing.T) {
+	s := newTestState(t, nil)
+
+	answerPasswords(t, "hunter2", "hunter3")
+	if err := HandlerRegister(s, cli.Command{Name: "register", Arguments: []string{"alice"}}); err == nil {
+		t.Error("register with mismatched passwords succeeded")
+	}
+}
+
+func TestPasswd(t *testing.T) {
+	s := newTestState(t, nil)
+	createUser(t, s, "alice")
+	passwd := middleware.LoggedIn(HandlerPasswd)
+
+	// Two sessions, as if alice had logged in on two machines.
+	if err := HandlerLogin(s, cli.Command{Name: "login", Arguments: []string{"alice"}}); err != nil {
+		t.Fatal(err)
+	}
+	otherSession := s.Cfg.SessionToken
+	s.Cfg.SessionToken = ""
+	if err := HandlerLogin(s, cli.Command{Name: "login", Arguments: []string{"alice"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	answerPasswords(t, "s3cret", "s3cret")
+	if err := passwd(s, cli.Command{Name: "passwd"}); err != nil {
+		t.Fatalf("passwd: %v", err)
+	}
+
+	if _, err := s.Store.GetUserBySession(context.Background(), auth.HashToken(otherSession)); err == nil {
+		t.Error("other session is still valid after passwd")
+	}
+	if _, err := s.Store.GetUserBySession(context.Background(), auth.HashToken(s.Cfg.SessionToken)); err != nil {
+		t.Errorf("current session was ended by passwd: %v", err)
+	}
+
+	answerPasswords(t, "wrong")
+	if err := passwd(s, cli.Command{Name: "passwd"}); err == nil {
+		t.Error("passwd with a wrong current password succeeded")
+	}
+
+	answerPasswords(t, "s3cret", "")
+	if err := passwd(s, cli.Command{Name: "passwd"}); err != nil {
+		t.Fatalf("removing password: %v", err)
+	}
+	if err := HandlerLogin(s, cli.Command{Name: "login", Arguments: []string{"alice"}}); err != nil {
+		t.Errorf("login without password after removing it: %v", err)
+	}
+}
+
+func TestFeverKeyFollowsPassword(t *testing.T) {
+	s := newTestState(t, nil)
+	passwd := middleware.LoggedIn(HandlerPasswd)
+	feverCmd := middleware.LoggedIn(HandlerFever)
+
+	answerPasswords(t, "hunter2", "hunter2")
+	if err := HandlerRegister(s, cli.Command{Name: "register", Arguments: []string{"alice"}}); err != nil {
+		t.Fatal(err)
+	}
+	answerPasswords(t, "hunter2")
+	if err := HandlerLogin(s, cli.Command{Name: "login", Arguments: []string{"alice"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	answerPasswords(t, "wrong")
+	if err := feverCmd(s, cli.Command{Name: "fever", Arguments: []string{"enable"}}); err == nil {
+		t.Error("fever enable with a wrong password succeeded")
+	}
+
+	answerPasswords(t, "hunter2")
+	if err := feverCmd(s, cli.Command{Name: "fever", Arguments: []string{"enable"}}); err != nil {
+		t.Fatalf("fever enable: %v", err)
+	}
+	keyHash := sql.NullString{String: auth.HashToken(fever.APIKey("alice", "hunter2")), Valid: true}
+	if _, err := s.Store.GetUserByFeverKey(context.Background(), keyHash); err != nil {
+		t.Fatalf("fever key was not stored: %v", err)
+	}
+
+	// The old key must not outlive the password it was derived from.
+	answerPasswords(t, "hunter2", "s3cret", "s3cret")
+	if err := passwd(s, cli.Command{Name: "passwd"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Store.GetUserByFeverKey(context.Background(), keyHash); err == nil {
+		t.Error("fever key still works after passwd")
+	}
+}
+
+func TestUsageErrors(t *testing.T) {
+	s := newTestState(t, nil)
+
+	handlers := map[string]func(s *state.State, cmd cli.Command) error{
+		"login":    HandlerLogin,
+		"register": HandlerRegister,
+		"agg":      HandlerAggregate,
+	}
+	for name, handler := range handlers {
+		if err := handler(s, cli.Command{Name: name}); err == nil {
+			t.Errorf("%s without arguments succeeded", name)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lmilojevicc/gator/internal/auth"
+	"github.com/lmilojevicc/gator/internal/config"
+	"github.com/lmilojevicc/gator/internal/database"
+	"github.com/lmilojevicc/gator/internal/notify"
+	"github.com/lmilojevicc/gator/internal/rss"
+	"github.com/lmilojevicc/gator/internal/state"
+	"github.com/lmilojevicc/gator/internal/store/memory"
+)
+
+// newTestState returns a state backed by the in-memory store whose fetcher
+// talks to srv. The config file is redirected to a temporary directory.
+func newTestState(t *testing.T, srv *httptest.Server) *state.State {
+	t.Helper()
+
+	t.Setenv("GATOR_CONFIG", t.TempDir()+"/config.json")
+
+	cfg := &config.Config{RedirectThreshold: 3}
+	client := http.DefaultClient
+	if srv != nil {
+		client = srv.Client()
+	}
+
+	st := memory.New()
+	notifier := notify.New(st)
+	notifier.Backoff = time.Millisecond
+	notifier.AllowLocal = true
+
+	return &state.State{
+		Store:    st,
+		Cfg:      cfg,
+		Fetcher:  rss.NewFetcherWithClient(client, cfg.Fetch),
+		Notifier: notifier,
+	}
+}
+
+func createUser(t *testing.T, s *state.State, name string) database.User {
+	t.Helper()
+
+	user, err := s.Store.CreateUser(context.Background(), database.CreateUserParams{
+		ID:   uuid.New(),
+		Name: name,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+// setPassword gives user a password, which admin rights need, and returns
+// the updated user.
+func setPassword(t *testing.T, s *state.State, user database.User) database.User {
+	t.Helper()
+
+	hash, err := auth.HashPassword("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user.PasswordHash = sql.NullString{String: hash, Valid: true}
+	err = s.Store.SetUserPassword(context.Background(), database.SetUserPasswordParams{
+		ID:           user.ID,
+		PasswordHash: user.PasswordHash,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+// answerPasswords makes the password prompts return answers in order.
+func answerPasswords(t *testing.T, answers ...string) {
+	t.Helper()
+
+	original := readPassword
+	t.Cleanup(func() { readPassword = original })
+
+	readPassword = func(prompt string) (string, error) {
+		if len(answers) == 0 {
+			t.Fatalf("unexpected prompt %q", prompt)
+		}
+		answer := answers[0]
+		answers = answers[1:]
+		return answer, nil
+	}
+}
+
+// answerPrompts feeds input to the y/N and typed confirmation prompts.
+func answerPrompts(t *testing.T, input string) {
+	t.Helper()
+
+	original := stdin
+	t.Cleanup(func() { stdin = original })
+
+	stdin = bufio.NewReader(strings.NewReader(input))
+}
+
+// feedServer serves body as an RSS document with the given status.
+func feedServer(t *testing.T, status int, body string) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+const testFeed = `<rss version="2.0"><channel>
+<title>Test</title>
+<item><title>Old</title><link>https://example.com/old</link><pubDate>Mon, 02 Jan 2006 15:04:05 MST</pubDate></item>
+<item><title>New</title><link>https://example.com/new</link><pubDate>2024-05-01T10:00:00Z</pubDate></item>
+<item><title>Undated</title><link>https://example.com/undated</link><pubDate>yesterday</pubDate></item>
+</channel></rss>`
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// stdin is shared by all prompts so that answers piped in on separate lines
+// are not swallowed by one prompt's buffer.
+var stdin = bufio.NewReader(os.Stdin)
+
+func confirm(prompt string) (bool, error) {
+	fmt.Printf("%s [y/N]: ", prompt)
+
+	answer, err := stdin.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, fmt.Errorf("reading answer: %w", err)
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	return answer == "y" || answer == "yes", nil
+}
+
+// confirmTyped asks the user to type expected, for actions that cannot be
+// undone and deserve more than a y/N.
+func confirmTyped(prompt, expected string) (bool, error) {
+	fmt.Printf("%s\nType %q to confirm: ", prompt, expected)
+
+	answer, err := stdin.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, fmt.Errorf("reading answer: %w", err)
+	}
+
+	return strings.TrimSpace(answer) == expected, nil
+}
+
+// stdinIsTerminal reports whether someone can answer a prompt that has no
+// safe default. It is a variable so tests can pretend they can.
+var stdinIsTerminal = func() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// choose lists options numbered from 1 and returns the index of the one the
+// user picks.
+func choose(prompt string, options []string) (int, error) {
+	fmt.Println(prompt)
+	for i, option := range options {
+		fmt.Printf("  %d) %s\n", i+1, option)
+	}
+	fmt.Printf("Choose 1-%d: ", len(options))
+
+	answer, err := stdin.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return 0, fmt.Errorf("reading answer: %w", err)
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(answer))
+	if err != nil || n < 1 || n > len(options) {
+		return 0, fmt.Errorf("invalid choice %q", strings.TrimSpace(answer))
+	}
+	return n - 1, nil
+}
+
+// readPassword prompts for a password without echoing it when stdin is a
+// terminal. It is a variable so tests can answer the prompt.
+var readPassword = func(prompt string) (string, error) {
+	fmt.Print(prompt)
+
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		password, err := term.ReadPassword(fd)
+		fmt.Println()
+		if err != nil {
+			return "", fmt.Errorf("reading password: %w", err)
+		}
+		return string(password), nil
+	}
+
+	password, err := stdin.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", fmt.Errorf("reading password: %w", err)
+	}
+	return strings.TrimRight(password, "\r\n"), nil
+}
@@ -2,8 +2,10 @@ package middleware
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 
+	"github.com/lmilojevicc/gator/internal/auth"
 	"github.com/lmilojevicc/gator/internal/cli"
 	"github.com/lmilojevicc/gator/internal/database"
 	"github.com/lmilojevicc/gator/internal/state"
@@ -13,12 +15,15 @@ type authenticationHandler = func(s *state.State, cmd cli.Command, user database
 
 func LoggedIn(handler authenticationHandler) func(*state.State, cli.Command) error {
 	return func(s *state.State, cmd cli.Command) error {
-		currentUser := s.Cfg.CurrentUserName
-		if currentUser == "" {
+		token := s.Cfg.SessionToken
+		if token == "" {
 			return fmt.Errorf("user must be logged in")
 		}
 
-		dbUser, err := s.Queries.GetUserByName(context.Background(), currentUser)
+		dbUser, err := s.Store.GetUserBySession(context.Background(), auth.HashToken(token))
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("session is no longer valid, log in again")
+		}
 		if err != nil {
 			return fmt.Errorf("getting user: %w", err)
 		}
@@ -26,3 +31,17 @@ func LoggedIn(handler authenticationHandler) func(*state.State, cli.Command) err
 		return handler(s, cmd, dbUser)
 	}
 }
+
+// Admin is LoggedIn for commands that only admins may run.
+func Admin(handler authenticationHandler) func(*state.State, cli.Command) error {
+	return LoggedIn(func(s *state.State, cmd cli.Command, user database.User) error {
+		if !user.IsAdmin {
+			return fmt.Errorf("only admins can run %s", cmd.Name)
+		}
+		if !auth.IsAdmin(user) {
+			return fmt.Errorf("set a password with passwd before running %s", cmd.Name)
+		}
+
+		return handler(s, cmd, user)
+	})
+}
@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/lmilojevicc/gator/internal/auth"
+	"github.com/lmilojevicc/gator/internal/cli"
+	"github.com/lmilojevicc/gator/internal/config"
+	"github.com/lmilojevicc/gator/internal/database"
+	"github.com/lmilojevicc/gator/internal/state"
+	"github.com/lmilojevicc/gator/internal/store/memory"
+)
+
+func TestLoggedIn(t *testing.T) {
+	st := memory.New()
+	alice, err := st.CreateUser(context.Background(), database.CreateUserParams{ID: uuid.New(), Name: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := auth.NewSessionToken()
+	err = st.CreateSession(context.Background(), database.CreateSessionParams{
+		TokenHash: auth.HashToken(token),
+		UserID:    alice.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{name: "logged in", token: token},
+		{name: "not logged in", token: "", wantErr: true},
+		{name: "unknown session", token: auth.NewSessionToken(), wantErr: true},
+		{name: "username instead of token", token: "alice", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &state.State{
+				Store: st,
+				Cfg:   &config.Config{SessionToken: tt.token},
+			}
+
+			var called bool
+			handler := LoggedIn(func(s *state.State, cmd cli.Command, user database.User) error {
+				called = true
+				if user.ID != alice.ID {
+					t.Errorf("handler got user %q, want alice", user.Name)
+				}
+				return nil
+			})
+
+			err := handler(s, cli.Command{Name: "test"})
+			if tt.wantErr {
+				if err == nil || called {
+					t.Errorf("err = %v, handler called = %v; want an error and no call", err, called)
+				}
+				return
+			}
+			if err != nil || !called {
+				t.Errorf("err = %v, handler called = %v; want the handler to run", err, called)
+			}
+		})
+	}
+}
+
+func TestAdmin(t *testing.T) {
+	st := memory.New()
+
+	sessions := map[string]string{}
+	for _, name := range []string{"admin", "user", "passwordless"} {
+		// The first user created becomes the admin.
+		user, err := st.CreateUser(context.Background(), database.CreateUserParams{ID: uuid.New(), Name: name})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		switch name {
+		case "admin":
+			err = st.SetUserPassword(context.Background(), database.SetUserPasswordParams{
+				ID:           user.ID,
+				PasswordHash: sql.NullString{String: "hash", Valid: true},
+			})
+		case "passwordless":
+			err = st.SetUserAdmin(context.Background(), database.SetUserAdminParams{ID: user.ID, IsAdmin: true})
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		token := auth.NewSessionToken()
+		err = st.CreateSession(context.Background(), database.CreateSessionParams{
+			TokenHash: auth.HashToken(token),
+			UserID:    user.ID,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sessions[name] = token
+	}
+
+	tests := []struct {
+		user    string
+		wantErr bool
+	}{
+		{user: "admin"},
+		{user: "user", wantErr: true},
+		{user: "passwordless", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.user, func(t *testing.T) {
+			s := &state.State{
+				Store: st,
+				Cfg:   &config.Config{SessionToken: sessions[tt.user]},
+			}
+
+			var called bool
+			handler := Admin(func(s *state.State, cmd cli.Command, user database.User) error {
+				called = true
+				return nil
+			})
+
+			err := handler(s, cli.Command{Name: "reset"})
+			if tt.wantErr != (err != nil) || called == tt.wantErr {
+				t.Errorf("err = %v, handler called = %v", err, called)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/pressly/goose/v3"
+
+	"github.com/lmilojevicc/gator/internal/config"
+	"github.com/lmilojevicc/gator/sql/schema"
+	sqliteschema "github.com/lmilojevicc/gator/sql/sqlite/schema"
+)
+
+// NewProvider returns a goose provider for the migrations of driver, one of
+// the config.Driver constants.
+func NewProvider(db *sql.DB, driver string) (*goose.Provider, error) {
+	dialect, fsys := goose.DialectPostgres, schema.FS
+	if driver == config.DriverSQLite {
+		dialect, fsys = goose.DialectSQLite3, sqliteschema.FS
+	}
+
+	provider, err := goose.NewProvider(dialect, db, fsys)
+	if err != nil {
+		return nil, fmt.Errorf("loading migrations: %w", err)
+	}
+	return provider, nil
+}
+
+// CheckCurrent returns an error unless the database schema is exactly at the
+// version of the newest embedded migration.
+func CheckCurrent(ctx context.Context, db *sql.DB, driver string) error {
+	provider, err := NewProvider(db, driver)
+	if err != nil {
+		return err
+	}
+
+	current, target, err := provider.GetVersions(ctx)
+	if err != nil {
+		return fmt.Errorf("getting schema version: %w", err)
+	}
+
+	switch {
+	case current < target:
+		return fmt.Errorf("database schema is at version %d but gator needs version %d, run `gator migrate up`", current, target)
+	case current > target:
+		return fmt.Errorf("database schema is at version %d, newer than this gator build supports (%d)", current, target)
+	}
+
+	return nil
+}
@@ -0,0 +1,255 @@
+// Package notify POSTs the new posts of a feed to the webhooks of the
+// notification rules that cover them, formatted for Slack, Mattermost,
+// Discord or as plain JSON.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lmilojevicc/gator/internal/database"
+	"github.com/lmilojevicc/gator/internal/filter"
+	"github.com/lmilojevicc/gator/internal/version"
+)
+
+// Post is a newly inserted post.
+type Post struct {
+	Title       string
+	URL         string
+	Description string
+	// PublishedAt is zero when the feed had no usable date.
+	PublishedAt time.Time
+	Author      string
+	// Categories holds one category per line, like the posts table.
+	Categories string
+}
+
+// Notifier delivers notifications and records every delivery with
+// CreateNotificationDelivery.
+type Notifier struct {
+	store  database.Querier
+	Client *http.Client
+	// Attempts is how often a delivery is tried. Retries wait Backoff, then
+	// twice as long before every further attempt.
+	Attempts int
+	Backoff  time.Duration
+	// Workers is how many notifications queued with Send are delivered at
+	// once.
+	Workers int
+	// AllowLocal lets webhooks point at loopback, link-local and private
+	// addresses, which tests serve them on.
+	AllowLocal bool
+	// AllowedNetworks are the private networks webhooks may point at.
+	AllowedNetworks []netip.Prefix
+
+	start   sync.Once
+	queue   chan job
+	pending sync.WaitGroup
+}
+
+// queueSize is how many notifications Send queues before it blocks.
+const queueSize = 64
+
+type job struct {
+	feed  database.Feed
+	posts []Post
+	done  func([]Delivery, error)
+}
+
+func New(q database.Querier) *Notifier {
+	n := &Notifier{
+		store:    q,
+		Attempts: 3,
+		Backoff:  2 * time.Second,
+		Workers:  4,
+	}
+	n.Client = n.newClient()
+	return n
+}
+
+// Send queues a Notify of posts in the background, so that slow or retried
+// webhooks do not hold up the caller, and calls done with its result. Send
+// only blocks while the queue is full.
+func (n *Notifier) Send(feed database.Feed, posts []Post, done func([]Delivery, error)) {
+	if len(posts) == 0 {
+		return
+	}
+
+	n.start.Do(func() {
+		n.queue = make(chan job, queueSize)
+		for range max(n.Workers, 1) {
+			go n.work()
+		}
+	})
+
+	n.pending.Add(1)
+	n.queue <- job{feed: feed, posts: posts, done: done}
+}
+
+func (n *Notifier) work() {
+	for j := range n.queue {
+		j.done(n.Notify(context.Background(), j.feed, j.posts))
+		n.pending.Done()
+	}
+}
+
+// Wait blocks until every notification queued with Send has been delivered.
+func (n *Notifier) Wait() {
+	n.pending.Wait()
+}
+
+// Delivery is the outcome of notifying one rule.
+type Delivery struct {
+	Rule       database.NotificationRule
+	Posts      int
+	Attempts   int
+	StatusCode int
+	// Err is nil if the webhook accepted the notification.
+	Err error
+}
+
+// Notify sends posts, the new posts of feed, to every rule of the feed's
+// followers that matches at least one of them. Posts hidden by the follower's
+// filter rules are left out. Failed deliveries are recorded and returned, not
+// reported as an error.
+func (n *Notifier) Notify(ctx context.Context, feed database.Feed, posts []Post) ([]Delivery, error) {
+	if len(posts) == 0 {
+		return nil, nil
+	}
+
+	rules, err := n.store.GetNotificationRulesForFeed(ctx, feed.ID)
+	if err != nil {
+		return nil, fmt.Errorf("getting notification rules: %w", err)
+	}
+
+	filters := map[uuid.UUID]*filter.Filter{}
+
+	var deliveries []Delivery
+	for _, rule := range rules {
+		f, ok := filters[rule.UserID]
+		if !ok {
+			f, err = filter.Load(ctx, n.store, rule.UserID)
+			if err != nil {
+				return deliveries, err
+			}
+			filters[rule.UserID] = f
+		}
+
+		var matched []Post
+		for _, post := range posts {
+			if matches(rule, post) && f.Allows(filter.NewPost(feed.ID, post.Title, post.Description, post.Author, post.Categories)) {
+				matched = append(matched, post)
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+
+		body, err := payload(rule.Format, feed, matched)
+		if err != nil {
+			return deliveries, err
+		}
+
+		delivery := n.deliver(ctx, rule.WebhookUrl, body)
+		delivery.Rule = rule
+		delivery.Posts = len(matched)
+
+		err = n.store.CreateNotificationDelivery(ctx, logEntry(feed, delivery))
+		if err != nil {
+			return deliveries, fmt.Errorf("recording notification delivery: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, nil
+}
+
+// matches reports whether post contains the rule's keyword, ignoring case.
+func matches(rule database.NotificationRule, post Post) bool {
+	if !rule.Keyword.Valid {
+		return true
+	}
+	keyword := strings.ToLower(rule.Keyword.String)
+	return strings.Contains(strings.ToLower(post.Title), keyword) ||
+		strings.Contains(strings.ToLower(post.Description), keyword)
+}
+
+// deliver POSTs body to url until it succeeds, fails permanently or runs out
+// of attempts. Network errors, 429 and 5xx responses are retried.
+func (n *Notifier) deliver(ctx context.Context, url string, body []byte) Delivery {
+	var delivery Delivery
+	wait := n.Backoff
+
+	for delivery.Attempts < max(n.Attempts, 1) {
+		if delivery.Attempts > 0 {
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				delivery.Err = ctx.Err()
+				return delivery
+			}
+			wait *= 2
+		}
+		delivery.Attempts++
+
+		var retry bool
+		delivery.StatusCode, retry, delivery.Err = n.post(ctx, url, body)
+		if delivery.Err == nil || !retry {
+			return delivery
+		}
+	}
+
+	return delivery
+}
+
+func (n *Notifier) post(ctx context.Context, url string, body []byte) (status int, retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, false, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gator/"+version.Version)
+
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return 0, !errors.Is(err, errLocalAddress), err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp.StatusCode, false, nil
+	}
+	retry = resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return resp.StatusCode, retry, fmt.Errorf("webhook returned %s", resp.Status)
+}
+
+func logEntry(feed database.Feed, delivery Delivery) database.CreateNotificationDeliveryParams {
+	entry := database.CreateNotificationDeliveryParams{
+		ID:        uuid.New(),
+		RuleID:    delivery.Rule.ID,
+		FeedID:    feed.ID,
+		PostCount: int32(delivery.Posts),
+		Attempts:  int32(delivery.Attempts),
+	}
+	if delivery.StatusCode != 0 {
+		entry.StatusCode = sql.NullInt32{Int32: int32(delivery.StatusCode), Valid: true}
+	}
+	if delivery.Err != nil {
+		entry.Error = sql.NullString{String: delivery.Err.Error(), Valid: true}
+	} else {
+		entry.DeliveredAt = sql.NullTime{Time: time.Now(), Valid: true}
+	}
+	return entry
+}
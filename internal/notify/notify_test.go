@@ -0,0 +1,321 @@
+package notify
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lmilojevicc/gator/internal/database"
+	"github.com/lmilojevicc/gator/internal/filter"
+	"github.com/lmilojevicc/gator/internal/store/memory"
+)
+
+// webhook records the bodies it receives and answers with statuses in turn,
+// then 204.
+type webhook struct {
+	*httptest.Server
+	mu       sync.Mutex
+	bodies   []string
+	statuses []int
+}
+
+func newWebhook(t *testing.T, statuses ...int) *webhook {
+	t.Helper()
+
+	hook := &webhook{statuses: statuses}
+	hook.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		hook.mu.Lock()
+		defer hook.mu.Unlock()
+		hook.bodies = append(hook.bodies, string(body))
+		status := http.StatusNoContent
+		if len(hook.statuses) > 0 {
+			status, hook.statuses = hook.statuses[0], hook.statuses[1:]
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(hook.Close)
+	return hook
+}
+
+// setup creates alice following a feed and returns a Notifier that does not
+// wait between retries.
+func setup(t *testing.T) (*Notifier, *memory.Store, database.User, database.Feed) {
+	t.Helper()
+	ctx := context.Background()
+
+	st := memory.New()
+	alice, err := st.CreateUser(ctx, database.CreateUserParams{ID: uuid.New(), Name: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	feed, err := st.CreateFeed(ctx, database.CreateFeedParams{
+		ID:     uuid.New(),
+		Name:   "Hacker News",
+		Url:    "https://news.example.com/rss",
+		UserID: alice.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = st.CreateFeedFollow(ctx, database.CreateFeedFollowParams{ID: uuid.New(), UserID: alice.ID, FeedID: feed.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n := New(st)
+	n.Backoff = time.Millisecond
+	n.AllowLocal = true
+	return n, st, alice, feed
+}
+
+func addRule(t *testing.T, st *memory.Store, params database.CreateNotificationRuleParams) database.NotificationRule {
+	t.Helper()
+
+	params.ID = uuid.New()
+	rule, err := st.CreateNotificationRule(context.Background(), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rule
+}
+
+var posts = []Post{
+	{Title: "Go 1.26 released", URL: "https://example.com/go"},
+	{Title: "Rust in the kernel", URL: "https://example.com/rust", Description: "Not about golang"},
+	{Title: "Show HN: a <b> & c", URL: "https://example.com/show"},
+}
+
+func TestNotifyKeyword(t *testing.T) {
+	n, st, alice, feed := setup(t)
+	hook := newWebhook(t)
+
+	addRule(t, st, database.CreateNotificationRuleParams{
+		UserID:     alice.ID,
+		Keyword:    sql.NullString{String: "GO", Valid: true},
+		WebhookUrl: hook.URL,
+		Format:     FormatJSON,
+	})
+	// Rules for posts nobody matches are not called at all.
+	addRule(t, st, database.CreateNotificationRuleParams{
+		UserID:     alice.ID,
+		Keyword:    sql.NullString{String: "haskell", Valid: true},
+		WebhookUrl: hook.URL,
+		Format:     FormatJSON,
+	})
+
+	deliveries, err := n.Notify(context.Background(), feed, posts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries) != 1 || deliveries[0].Err != nil || deliveries[0].Posts != 2 {
+		t.Fatalf("deliveries = %+v", deliveries)
+	}
+
+	var got jsonPayload
+	if err := json.Unmarshal([]byte(hook.bodies[0]), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Feed.Name != "Hacker News" || len(got.Posts) != 2 || got.Posts[1].URL != "https://example.com/rust" {
+		t.Errorf("payload = %+v", got)
+	}
+}
+
+func TestNotifyFilters(t *testing.T) {
+	n, st, alice, feed := setup(t)
+	hook := newWebhook(t)
+
+	addRule(t, st, database.CreateNotificationRuleParams{UserID: alice.ID, WebhookUrl: hook.URL, Format: FormatJSON})
+	_, err := st.CreateFilterRule(context.Background(), database.CreateFilterRuleParams{
+		ID:      uuid.New(),
+		UserID:  alice.ID,
+		FeedID:  uuid.NullUUID{UUID: feed.ID, Valid: true},
+		Action:  filter.ActionExclude,
+		Kind:    filter.KindCategory,
+		Pattern: "rust",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tagged := slices.Clone(posts)
+	tagged[1].Categories = "Rust\nLinux"
+
+	deliveries, err := n.Notify(context.Background(), feed, tagged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries) != 1 || deliveries[0].Posts != 2 || strings.Contains(hook.bodies[0], "kernel") {
+		t.Errorf("deliveries = %+v, body %s, want the Rust post muted", deliveries, hook.bodies[0])
+	}
+}
+
+func TestNotifyRetries(t *testing.T) {
+	n, st, alice, feed := setup(t)
+	ctx := context.Background()
+
+	flaky := newWebhook(t, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+	addRule(t, st, database.CreateNotificationRuleParams{UserID: alice.ID, WebhookUrl: flaky.URL, Format: FormatSlack})
+	broken := newWebhook(t, http.StatusNotFound)
+	addRule(t, st, database.CreateNotificationRuleParams{UserID: alice.ID, WebhookUrl: broken.URL, Format: FormatSlack})
+
+	deliveries, err := n.Notify(ctx, feed, posts[:1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries) != 2 {
+		t.Fatalf("got %d deliveries, want 2", len(deliveries))
+	}
+	if d := deliveries[0]; d.Err != nil || d.Attempts != 3 || d.StatusCode != http.StatusNoContent {
+		t.Errorf("flaky webhook: %+v, want delivered on the third attempt", d)
+	}
+	// A 404 will not go away by retrying.
+	if d := deliveries[1]; d.Err == nil || d.Attempts != 1 || d.StatusCode != http.StatusNotFound {
+		t.Errorf("broken webhook: %+v, want one failed attempt", d)
+	}
+
+	log, err := st.GetNotificationDeliveries(ctx, database.GetNotificationDeliveriesParams{UserID: alice.ID, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(log) != 2 {
+		t.Fatalf("log has %d entries, want 2", len(log))
+	}
+	for _, entry := range log {
+		delivered := entry.WebhookUrl == flaky.URL
+		if entry.DeliveredAt.Valid != delivered || entry.Error.Valid == delivered || entry.PostCount != 1 {
+			t.Errorf("log entry = %+v", entry)
+		}
+	}
+}
+
+func TestPayloadFormats(t *testing.T) {
+	feed := database.Feed{Name: "HN", Url: "https://news.example.com/rss"}
+
+	tests := []struct {
+		format, key, want string
+	}{
+		{FormatSlack, "text", "*3 new posts from HN*\n- <https://example.com/go|Go 1.26 released>"},
+		{FormatMattermost, "text", "#### 3 new posts from HN\n- [Go 1.26 released](https://example.com/go)"},
+		{FormatDiscord, "content", "**3 new posts from HN**\n- [Go 1.26 released](<https://example.com/go>)"},
+	}
+	for _, tt := range tests {
+		body, err := payload(tt.format, feed, posts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got map[string]string
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(got[tt.key], tt.want) {
+			t.Errorf("%s: %s = %q, want prefix %q", tt.format, tt.key, got[tt.key], tt.want)
+		}
+	}
+
+	body, _ := payload(FormatSlack, feed, posts)
+	if !strings.Contains(string(body), `Show HN: a &lt;b&gt; &amp; c`) {
+		t.Errorf("slack payload does not escape the title: %s", body)
+	}
+
+	if _, err := payload("irc", feed, posts); err == nil {
+		t.Error("unknown format succeeded")
+	}
+}
+
+func TestDiscordMessageLimit(t *testing.T) {
+	var many []Post
+	for range 200 {
+		many = append(many, Post{Title: strings.Repeat("x", 40), URL: "https://example.com/" + uuid.NewString()})
+	}
+
+	msg := message(database.Feed{Name: "HN"}, many, discordHeading, discordLink, discordMaxLength)
+	if len(msg) > discordMaxLength {
+		t.Errorf("message is %d bytes, over the limit of %d", len(msg), discordMaxLength)
+	}
+	if !strings.Contains(msg, "more") {
+		t.Errorf("message does not say posts were left out:\n%s", msg)
+	}
+}
+
+func TestSendInBackground(t *testing.T) {
+	n, st, alice, feed := setup(t)
+	hook := newWebhook(t)
+	addRule(t, st, database.CreateNotificationRuleParams{UserID: alice.ID, WebhookUrl: hook.URL, Format: FormatJSON})
+
+	var got []Delivery
+	n.Send(feed, posts, func(deliveries []Delivery, err error) {
+		if err != nil {
+			t.Error(err)
+		}
+		got = deliveries
+	})
+	n.Wait()
+
+	if len(got) != 1 || got[0].Err != nil || len(hook.bodies) != 1 {
+		t.Errorf("deliveries = %+v, webhook called %d times", got, len(hook.bodies))
+	}
+}
+
+func TestCheckWebhookURL(t *testing.T) {
+	n := New(memory.New())
+
+	for _, raw := range []string{
+		"ftp://example.com/hook",
+		"https://",
+		"http://localhost:8080/hook",
+		"http://127.0.0.1/hook",
+		"http://[::1]/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://[fe80::1]/hook",
+		"http://0.0.0.0/hook",
+		"http://10.0.0.1/hook",
+		"http://172.16.5.4/hook",
+		"http://192.168.1.1/hook",
+		"http://[fd00::1]/hook",
+		"http://[::ffff:10.0.0.1]/hook",
+	} {
+		if err := n.CheckWebhookURL(raw); err == nil {
+			t.Errorf("%s was accepted", raw)
+		}
+	}
+	if err := n.CheckWebhookURL("https://hooks.example.com/services/T0/B0"); err != nil {
+		t.Errorf("public webhook: %v", err)
+	}
+
+	// Private networks are allowed only when listed.
+	networks, err := ParseNetworks([]string{"10.1.0.0/16"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	n.AllowedNetworks = networks
+	if err := n.CheckWebhookURL("http://10.1.2.3/hook"); err != nil {
+		t.Errorf("webhook in an allowed network: %v", err)
+	}
+	for _, raw := range []string{"http://10.2.0.1/hook", "http://127.0.0.1/hook"} {
+		if err := n.CheckWebhookURL(raw); err == nil {
+			t.Errorf("%s was accepted with only 10.1.0.0/16 allowed", raw)
+		}
+	}
+	if _, err := ParseNetworks([]string{"10.1.0.0"}); err == nil {
+		t.Error("a network without a prefix length was accepted")
+	}
+
+	// Host names are checked again once resolved.
+	hook := newWebhook(t)
+	d := n.deliver(context.Background(), strings.Replace(hook.URL, "127.0.0.1", "localhost", 1), []byte("{}"))
+	if d.Err == nil || d.Attempts != 1 || len(hook.bodies) != 0 {
+		t.Errorf("delivery to a loopback address = %+v, want one refused attempt", d)
+	}
+}
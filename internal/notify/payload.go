@@ -0,0 +1,134 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lmilojevicc/gator/internal/database"
+)
+
+// Webhook payload formats a rule can use.
+const (
+	FormatSlack      = "slack"
+	FormatMattermost = "mattermost"
+	FormatDiscord    = "discord"
+	FormatJSON       = "json"
+)
+
+var Formats = []string{FormatSlack, FormatMattermost, FormatDiscord, FormatJSON}
+
+// discordMaxLength is the longest message content Discord accepts.
+const discordMaxLength = 2000
+
+type jsonPayload struct {
+	Feed  jsonFeed   `json:"feed"`
+	Posts []jsonPost `json:"posts"`
+}
+
+type jsonFeed struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type jsonPost struct {
+	Title       string     `json:"title"`
+	URL         string     `json:"url"`
+	Description string     `json:"description,omitempty"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+}
+
+// payload renders the webhook body announcing posts of feed in format.
+func payload(format string, feed database.Feed, posts []Post) ([]byte, error) {
+	switch format {
+	case FormatSlack:
+		return marshal(map[string]string{"text": message(feed, posts, slackHeading, slackLink, 0)})
+	case FormatMattermost:
+		return marshal(map[string]string{"text": message(feed, posts, markdownHeading, markdownLink, 0)})
+	case FormatDiscord:
+		return marshal(map[string]string{"content": message(feed, posts, discordHeading, discordLink, discordMaxLength)})
+	case FormatJSON:
+		body := jsonPayload{Feed: jsonFeed{Name: feed.Name, URL: feed.Url}}
+		for _, post := range posts {
+			p := jsonPost{Title: post.Title, URL: post.URL, Description: post.Description}
+			if !post.PublishedAt.IsZero() {
+				p.PublishedAt = &post.PublishedAt
+			}
+			body.Posts = append(body.Posts, p)
+		}
+		return marshal(body)
+	default:
+		return nil, fmt.Errorf("unknown notification format %q", format)
+	}
+}
+
+// marshal is json.Marshal without escaping <, > and &, which chat messages
+// use for links and entities.
+func marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// message is a heading followed by one line per post. With maxLength, posts
+// that do not fit are summarized as "...and N more".
+func message(feed database.Feed, posts []Post, heading func(string) string, link func(title, url string) string, maxLength int) string {
+	noun := "posts"
+	if len(posts) == 1 {
+		noun = "post"
+	}
+
+	var b strings.Builder
+	b.WriteString(heading(fmt.Sprintf("%d new %s from %s", len(posts), noun, feed.Name)))
+	for i, post := range posts {
+		title := post.Title
+		if title == "" {
+			title = post.URL
+		}
+		line := "\n- " + link(title, post.URL)
+
+		more := fmt.Sprintf("\n...and %d more", len(posts)-i)
+		if maxLength > 0 && b.Len()+len(line)+len(more) > maxLength {
+			b.WriteString(more)
+			break
+		}
+		b.WriteString(line)
+	}
+	return b.String()
+}
+
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+func slackHeading(s string) string {
+	return "*" + slackEscaper.Replace(s) + "*"
+}
+
+func slackLink(title, url string) string {
+	return "<" + url + "|" + slackEscaper.Replace(title) + ">"
+}
+
+var markdownEscaper = strings.NewReplacer("[", `\[`, "]", `\]`, "*", `\*`, "_", `\_`)
+
+func markdownHeading(s string) string {
+	return "#### " + s
+}
+
+func markdownLink(title, url string) string {
+	return "[" + markdownEscaper.Replace(title) + "](" + url + ")"
+}
+
+func discordHeading(s string) string {
+	return "**" + markdownEscaper.Replace(s) + "**"
+}
+
+// discordLink wraps the URL in angle brackets so Discord does not embed a
+// preview of every post.
+func discordLink(title, url string) string {
+	return "[" + markdownEscaper.Replace(title) + "](<" + url + ">)"
+}
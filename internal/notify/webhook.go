@@ -0,0 +1,100 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// errLocalAddress is returned when a webhook host resolves to a loopback,
+// link-local or private address that is not allowed. Such deliveries are not
+// retried.
+var errLocalAddress = errors.New("webhook resolves to a loopback, link-local or private address")
+
+// ParseNetworks parses the CIDR prefixes of the allowed_webhook_networks
+// config setting.
+func ParseNetworks(cidrs []string) ([]netip.Prefix, error) {
+	var networks []netip.Prefix
+	for _, cidr := range cidrs {
+		network, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook network %q: %w", cidr, err)
+		}
+		networks = append(networks, network.Masked())
+	}
+	return networks, nil
+}
+
+// CheckWebhookURL returns an error unless raw is an http(s) URL that does not
+// point at a loopback, link-local or private address, so that rules cannot be
+// used to reach services on the machine gator runs on, its internal network
+// or cloud metadata endpoints. Private addresses in AllowedNetworks pass.
+func (n *Notifier) CheckWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid webhook url %q", raw)
+	}
+	if n.AllowLocal {
+		return nil
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("webhook url %q points at this machine", raw)
+	}
+	if ip, err := netip.ParseAddr(host); err == nil && !n.allowed(ip) {
+		return fmt.Errorf("webhook url %q points at a loopback, link-local or private address", raw)
+	}
+	return nil
+}
+
+// newClient returns the client webhooks are posted with. Its dialer checks the
+// resolved address as well, which CheckWebhookURL cannot do for host names.
+func (n *Notifier) newClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   n.checkDial,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{Transport: transport, Timeout: 10 * time.Second}
+}
+
+func (n *Notifier) checkDial(network, address string, _ syscall.RawConn) error {
+	if n.AllowLocal {
+		return nil
+	}
+
+	addr, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !n.allowed(addr.Addr()) {
+		return fmt.Errorf("%w: %s", errLocalAddress, addr.Addr())
+	}
+	return nil
+}
+
+// allowed reports whether webhooks may be delivered to ip: never to loopback
+// or link-local addresses, and to private ones only within AllowedNetworks.
+func (n *Notifier) allowed(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	if ip.IsPrivate() {
+		return slices.ContainsFunc(n.AllowedNetworks, func(network netip.Prefix) bool {
+			return network.Contains(ip)
+		})
+	}
+	return true
+}
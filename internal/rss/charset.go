@@ -0,0 +1,52 @@
+package rss
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+
+	"golang.org/x/net/html/charset"
+)
+
+// newDecoder returns an XML decoder that transcodes r to UTF-8. A charset
+// given in the Content-Type header takes precedence over the XML declaration.
+func newDecoder(r io.Reader, contentType string) (*xml.Decoder, error) {
+	headerCharset := charsetFromContentType(contentType)
+	if headerCharset != "" && !isUTF8(headerCharset) {
+		enc, _ := charset.Lookup(headerCharset)
+		if enc == nil {
+			return nil, fmt.Errorf("unsupported charset %q", headerCharset)
+		}
+		r = enc.NewDecoder().Reader(r)
+	}
+
+	decoder := xml.NewDecoder(r)
+	decoder.CharsetReader = func(label string, input io.Reader) (io.Reader, error) {
+		if headerCharset != "" {
+			return input, nil
+		}
+		return charset.NewReaderLabel(label, input)
+	}
+
+	return decoder, nil
+}
+
+func charsetFromContentType(contentType string) string {
+	if contentType == "" {
+		return ""
+	}
+
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(params["charset"])
+}
+
+func isUTF8(label string) bool {
+	label = strings.ToLower(label)
+	return label == "utf-8" || label == "utf8"
+}
@@ -0,0 +1,89 @@
+package rss
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+const acceptEncoding = "gzip, br, deflate"
+
+// decompress wraps body in a streaming decoder for the given Content-Encoding.
+func decompress(contentEncoding string, body io.Reader) (io.Reader, error) {
+	switch strings.ToLower(strings.TrimSpace(contentEncoding)) {
+	case "", "identity":
+		return body, nil
+	case "gzip", "x-gzip":
+		r, err := gzip.NewReader(body)
+		if err != nil {
+			return nil, fmt.Errorf("reading gzip body: %w", err)
+		}
+		return r, nil
+	case "br":
+		return brotli.NewReader(body), nil
+	case "deflate":
+		return newDeflateReader(body)
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", contentEncoding)
+	}
+}
+
+// newDeflateReader accepts both zlib-wrapped deflate, as the spec requires,
+// and the raw deflate streams some servers send instead.
+func newDeflateReader(body io.Reader) (io.Reader, error) {
+	buffered := bufio.NewReader(body)
+
+	header, err := buffered.Peek(2)
+	if err != nil {
+		return nil, fmt.Errorf("reading deflate body: %w", err)
+	}
+
+	if isZlibHeader(header) {
+		r, err := zlib.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("reading deflate body: %w", err)
+		}
+		return r, nil
+	}
+
+	return flate.NewReader(buffered), nil
+}
+
+func isZlibHeader(header []byte) bool {
+	return header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// sizeLimitReader counts the bytes read through it and fails with ErrTooLarge
+// once more than limit bytes have been read.
+type sizeLimitReader struct {
+	r     io.Reader
+	limit int64
+	n     int64
+}
+
+func (l *sizeLimitReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.n += int64(n)
+	if l.n > l.limit {
+		return n, fmt.Errorf("%w: more than %d bytes", ErrTooLarge, l.limit)
+	}
+	return n, err
+}
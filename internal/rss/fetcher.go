@@ -0,0 +1,237 @@
+package rss
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/lmilojevicc/gator/internal/config"
+	"github.com/lmilojevicc/gator/internal/version"
+)
+
+const (
+	defaultMaxBodySize = 10 << 20
+	defaultMaxItems    = 1000
+)
+
+// Fetcher performs feed requests with the client settings from config.FetchConfig.
+type Fetcher struct {
+	client      *http.Client
+	userAgent   string
+	maxBodySize int64
+	maxItems    int
+}
+
+func NewFetcher(cfg config.FetchConfig) (*Fetcher, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if cfg.ProxyURL != "" {
+		proxyURL, err := url.Parse(cfg.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("parsing proxy url: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	tlsConfig, err := newTLSConfig(cfg.TLS)
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	client := &http.Client{Transport: transport}
+	if cfg.Timeout != "" {
+		client.Timeout, err = time.ParseDuration(cfg.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("parsing fetch timeout: %w", err)
+		}
+	}
+
+	return NewFetcherWithClient(client, cfg), nil
+}
+
+// NewFetcherWithClient returns a Fetcher that sends requests through client,
+// such as the one returned by httptest.Server.Client. Only the user agent and
+// size limits are taken from cfg.
+func NewFetcherWithClient(client *http.Client, cfg config.FetchConfig) *Fetcher {
+	maxBodySize := cfg.MaxBodyBytes
+	if maxBodySize <= 0 {
+		maxBodySize = defaultMaxBodySize
+	}
+
+	maxItems := cfg.MaxItems
+	if maxItems <= 0 {
+		maxItems = defaultMaxItems
+	}
+
+	return &Fetcher{
+		client:      client,
+		userAgent:   userAgent(cfg),
+		maxBodySize: maxBodySize,
+		maxItems:    maxItems,
+	}
+}
+
+func newTLSConfig(cfg config.TLSConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+
+	switch cfg.MinVersion {
+	case "":
+	case "1.0":
+		tlsConfig.MinVersion = tls.VersionTLS10
+	case "1.1":
+		tlsConfig.MinVersion = tls.VersionTLS11
+	case "1.2":
+		tlsConfig.MinVersion = tls.VersionTLS12
+	case "1.3":
+		tlsConfig.MinVersion = tls.VersionTLS13
+	default:
+		return nil, fmt.Errorf("unknown tls min_version %q", cfg.MinVersion)
+	}
+
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading ca file: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return tlsConfig, nil
+}
+
+func userAgent(cfg config.FetchConfig) string {
+	ua := cfg.UserAgent
+	if ua == "" {
+		ua = "gator/" + version.Version
+	}
+	if cfg.Contact != "" {
+		ua += " (+" + cfg.Contact + ")"
+	}
+	return ua
+}
+
+// Auth holds the credentials sent with requests for a single feed.
+type Auth struct {
+	Username    string
+	Password    string
+	BearerToken string
+	Header      http.Header
+}
+
+func (a *Auth) apply(req *http.Request) {
+	if a == nil {
+		return
+	}
+
+	for name, values := range a.Header {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+
+	switch {
+	case a.BearerToken != "":
+		req.Header.Set("Authorization", "Bearer "+a.BearerToken)
+	case a.Username != "":
+		req.SetBasicAuth(a.Username, a.Password)
+	}
+}
+
+// strip removes the headers apply added, so credentials are not sent to
+// another host. http.Client only drops Authorization, and keeps it for
+// subdomains.
+func (a *Auth) strip(req *http.Request) {
+	if a == nil {
+		return
+	}
+
+	for name := range a.Header {
+		req.Header.Del(name)
+	}
+	req.Header.Del("Authorization")
+}
+
+// get requests feedURL, following redirects, and fails on any status other than 200 OK.
+func (f *Fetcher) get(ctx context.Context, feedURL string, auth *Auth) (*http.Response, *redirectChain, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", f.userAgent)
+	req.Header.Set("Accept-Encoding", acceptEncoding)
+	auth.apply(req)
+
+	redirects := &redirectChain{auth: auth}
+	client := *f.client
+	client.CheckRedirect = redirects.check
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("response: %w", err)
+	}
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		return res, redirects, nil
+	case http.StatusGone:
+		res.Body.Close()
+		return nil, nil, ErrGone
+	default:
+		res.Body.Close()
+		return nil, nil, &StatusError{StatusCode: res.StatusCode, Status: res.Status}
+	}
+}
+
+// redirectChain records the redirects followed by an http.Client.
+type redirectChain struct {
+	// auth is stripped from requests that leave the feed's host.
+	auth       *Auth
+	redirected bool
+	temporary  bool
+}
+
+func (c *redirectChain) check(req *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return errors.New("stopped after 10 redirects")
+	}
+
+	c.redirected = true
+	if !isPermanentRedirect(req.Response.StatusCode) {
+		c.temporary = true
+	}
+
+	if req.URL.Host != via[0].URL.Host {
+		c.auth.strip(req)
+	}
+
+	return nil
+}
+
+func (c *redirectChain) permanentURL(res *http.Response) string {
+	if !c.redirected || c.temporary {
+		return ""
+	}
+	return res.Request.URL.String()
+}
+
+func isPermanentRedirect(statusCode int) bool {
+	return statusCode == http.StatusMovedPermanently || statusCode == http.StatusPermanentRedirect
+}
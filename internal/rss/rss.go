@@ -3,19 +3,18 @@ package rss
 import (
 	"context"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"html"
 	"io"
-	"net/http"
+	"strings"
 )
 
-type RSSFeed struct {
-	Channel struct {
-		Title       string    `xml:"title"`
-		Link        string    `xml:"link"`
-		Description string    `xml:"description"`
-		Items       []RSSItem `xml:"item"`
-	} `xml:"channel"`
+// Channel is the feed-level metadata of an RSS document.
+type Channel struct {
+	Title       string
+	Link        string
+	Description string
 }
 
 type RSSItem struct {
@@ -23,43 +22,198 @@ type RSSItem struct {
 	Link        string `xml:"link"`
 	Description string `xml:"description"`
 	PubDate     string `xml:"pubDate"`
+	// Author is <author> or, as many feeds use instead, <dc:creator>.
+	Author     string   `xml:"author"`
+	Creator    string   `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Categories []string `xml:"category"`
 }
 
-func FetchFeed(ctx context.Context, feedURL string) (*RSSFeed, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
+// ErrGone is returned by FetchFeed when the server answers 410 Gone.
+var ErrGone = errors.New("feed is gone")
+
+// ErrNotRSS is returned when the document is not an <rss> document with a
+// <channel>, such as an Atom feed.
+var ErrNotRSS = errors.New("not an RSS feed")
+
+// ErrTooLarge is returned when a response exceeds the configured body size.
+var ErrTooLarge = errors.New("response body too large")
+
+// StatusError is returned when the server answers with a status other than 200 OK.
+type StatusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status: %s", e.Status)
+}
+
+// FetchResult describes a fetched feed and what was learned while fetching it.
+type FetchResult struct {
+	Channel Channel
+	// Items is the number of items handed to the item handler.
+	Items int
+	// Truncated is set when the feed had more items than the per-fetch cap.
+	Truncated bool
+	// PermanentURL is the final URL when every redirect followed was permanent (301/308).
+	PermanentURL string
+	// ContentEncoding is the Content-Encoding the server responded with.
+	ContentEncoding string
+	// CompressedBytes is the size of the body as transferred.
+	CompressedBytes int64
+	// UncompressedBytes is the size of the body after decompression.
+	UncompressedBytes int64
+}
+
+// FetchFeed streams the feed at feedURL, calling handleItem for every item in
+// document order until the fetcher's item cap is reached. An error returned
+// by handleItem stops the fetch and is returned as is.
+func (f *Fetcher) FetchFeed(ctx context.Context, feedURL string, auth *Auth, handleItem func(RSSItem) error) (*FetchResult, error) {
+	res, redirects, err := f.get(ctx, feedURL, auth)
 	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
+		return nil, err
 	}
+	defer res.Body.Close()
 
-	req.Header.Set("User-Agent", "gator")
+	compressed := &countingReader{r: res.Body}
+	contentEncoding := res.Header.Get("Content-Encoding")
 
-	res, err := http.DefaultClient.Do(req)
+	body, err := decompress(contentEncoding, compressed)
 	if err != nil {
-		return nil, fmt.Errorf("response: %w", err)
+		return nil, err
 	}
 
-	defer res.Body.Close()
+	uncompressed := &sizeLimitReader{r: body, limit: f.maxBodySize}
+
+	decoder, err := newDecoder(uncompressed, res.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &FetchResult{
+		PermanentURL:    redirects.permanentURL(res),
+		ContentEncoding: contentEncoding,
+	}
+
+	if err := streamFeed(decoder, f.maxItems, result, handleItem); err != nil {
+		return nil, err
+	}
+
+	result.CompressedBytes = compressed.n
+	result.UncompressedBytes = uncompressed.n
 
-	resData, err := io.ReadAll(res.Body)
+	return result, nil
+}
+
+// ResolveURL requests feedURL and returns the URL it permanently redirects to.
+// If the redirect chain contains a temporary redirect, feedURL is returned as is.
+func (f *Fetcher) ResolveURL(ctx context.Context, feedURL string, auth *Auth) (string, error) {
+	res, redirects, err := f.get(ctx, feedURL, auth)
 	if err != nil {
-		return nil, fmt.Errorf("reading response data: %w", err)
+		return "", err
 	}
+	defer res.Body.Close()
 
-	var feed RSSFeed
-	if err = xml.Unmarshal(resData, &feed); err != nil {
-		return nil, fmt.Errorf("unmarshaling body: %w", err)
+	if permanentURL := redirects.permanentURL(res); permanentURL != "" {
+		return permanentURL, nil
 	}
 
-	unescapeFeed(&feed)
+	return feedURL, nil
+}
+
+// streamFeed reads an RSS document token by token and decodes one <item> at a
+// time, so a large feed is never held in memory as a whole.
+func streamFeed(decoder *xml.Decoder, maxItems int, result *FetchResult, handleItem func(RSSItem) error) error {
+	depth := 0
+	inChannel := false
+	sawChannel := false
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			if !sawChannel {
+				return fmt.Errorf("%w: no <channel> element", ErrNotRSS)
+			}
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("parsing feed: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+
+			switch {
+			case depth == 1 && t.Name.Local != "rss":
+				return fmt.Errorf("%w: root element is <%s>", ErrNotRSS, t.Name.Local)
+			case depth == 2 && t.Name.Local == "channel":
+				inChannel = true
+				sawChannel = true
+			case inChannel && depth == 3 && t.Name.Local == "item":
+				if maxItems > 0 && result.Items >= maxItems {
+					result.Truncated = true
+					return nil
+				}
+
+				var item RSSItem
+				if err := decoder.DecodeElement(&item, &t); err != nil {
+					return fmt.Errorf("decoding item: %w", err)
+				}
+				depth--
+
+				unescapeItem(&item)
+				if err := handleItem(item); err != nil {
+					return err
+				}
+				result.Items++
+			case inChannel && depth == 3:
+				var value string
+				if err := decoder.DecodeElement(&value, &t); err != nil {
+					return fmt.Errorf("decoding channel %s: %w", t.Name.Local, err)
+				}
+				depth--
 
-	return &feed, nil
+				setChannelField(&result.Channel, t.Name.Local, html.UnescapeString(value))
+			}
+		case xml.EndElement:
+			if depth == 2 {
+				inChannel = false
+			}
+			depth--
+		}
+	}
 }
 
-func unescapeFeed(feed *RSSFeed) {
-	feed.Channel.Title = html.UnescapeString(feed.Channel.Title)
-	feed.Channel.Description = html.UnescapeString(feed.Channel.Description)
-	for i := range feed.Channel.Items {
-		feed.Channel.Items[i].Title = html.UnescapeString(feed.Channel.Items[i].Title)
-		feed.Channel.Items[i].Description = html.UnescapeString(feed.Channel.Items[i].Description)
+func setChannelField(channel *Channel, name, value string) {
+	if value == "" {
+		return
+	}
+
+	switch name {
+	case "title":
+		channel.Title = value
+	case "link":
+		channel.Link = value
+	case "description":
+		channel.Description = value
+	}
+}
+
+func unescapeItem(item *RSSItem) {
+	item.Title = html.UnescapeString(item.Title)
+	item.Description = html.UnescapeString(item.Description)
+
+	if item.Author == "" {
+		item.Author = item.Creator
+	}
+	item.Author = strings.TrimSpace(html.UnescapeString(item.Author))
+
+	categories := item.Categories[:0]
+	for _, category := range item.Categories {
+		if category = strings.TrimSpace(html.UnescapeString(category)); category != "" {
+			categories = append(categories, category)
+		}
 	}
+	item.Categories = categories
 }
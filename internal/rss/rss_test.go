@@ -0,0 +1,307 @@
+package rss
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/lmilojevicc/gator/internal/config"
+)
+
+const emptyFeed = `<rss version="2.0"><channel></channel></rss>`
+
+// serveFixture serves testdata/name at /feed.
+func serveFixture(t *testing.T, name, contentType string) *httptest.Server {
+	t.Helper()
+
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func fetchAll(t *testing.T, f *Fetcher, url string) (*FetchResult, []RSSItem, error) {
+	t.Helper()
+
+	var items []RSSItem
+	result, err := f.FetchFeed(context.Background(), url, nil, func(item RSSItem) error {
+		items = append(items, item)
+		return nil
+	})
+	return result, items, err
+}
+
+func TestFetchFeedFixtures(t *testing.T) {
+	tests := []struct {
+		name        string
+		fixture     string
+		contentType string
+		wantChannel Channel
+		wantTitles  []string
+		wantErr     bool
+		// wantErrIs is checked with errors.Is when set.
+		wantErrIs error
+	}{
+		{
+			name:        "rss",
+			fixture:     "rss.xml",
+			contentType: "application/rss+xml",
+			wantChannel: Channel{
+				Title:       "Boot.dev Blog",
+				Link:        "https://blog.boot.dev/",
+				Description: "Recent content on Boot.dev Blog",
+			},
+			wantTitles: []string{"The Zen of Proverbs", "Learn Go & SQL", "No Date"},
+		},
+		{
+			name:        "charset from xml declaration",
+			fixture:     "latin1.xml",
+			contentType: "application/xml",
+			wantChannel: Channel{Title: "Café"},
+			wantTitles:  []string{"Crème brûlée"},
+		},
+		{
+			// Atom is not supported, which must not look like an empty feed.
+			name:        "atom",
+			fixture:     "atom.xml",
+			contentType: "application/atom+xml",
+			wantErr:     true,
+			wantErrIs:   ErrNotRSS,
+		},
+		{
+			name:        "malformed",
+			fixture:     "malformed.xml",
+			contentType: "application/rss+xml",
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := serveFixture(t, tt.fixture, tt.contentType)
+			f := NewFetcherWithClient(srv.Client(), config.FetchConfig{})
+
+			result, items, err := fetchAll(t, f, srv.URL+"/feed")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+					t.Errorf("error = %v, want %v", err, tt.wantErrIs)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if result.Channel != tt.wantChannel {
+				t.Errorf("channel = %+v, want %+v", result.Channel, tt.wantChannel)
+			}
+			if result.Items != len(tt.wantTitles) || len(items) != len(tt.wantTitles) {
+				t.Fatalf("got %d items (result says %d), want %d", len(items), result.Items, len(tt.wantTitles))
+			}
+			for i, title := range tt.wantTitles {
+				if items[i].Title != title {
+					t.Errorf("item %d title = %q, want %q", i, items[i].Title, title)
+				}
+			}
+		})
+	}
+}
+
+func TestFetchFeedItemFields(t *testing.T) {
+	srv := serveFixture(t, "rss.xml", "application/rss+xml")
+	f := NewFetcherWithClient(srv.Client(), config.FetchConfig{})
+
+	_, items, err := fetchAll(t, f, srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := RSSItem{
+		Title:       "The Zen of Proverbs",
+		Link:        "https://blog.boot.dev/misc/zen-of-proverbs/",
+		Description: "Proverbs & sayings",
+		PubDate:     "Mon, 02 Jan 2006 15:04:05 +0000",
+		Author:      "Lane Wagner",
+		Creator:     "Lane Wagner",
+		Categories:  []string{"Go", "Proverbs"},
+	}
+	if !reflect.DeepEqual(items[0], want) {
+		t.Errorf("item = %+v, want %+v", items[0], want)
+	}
+	if items[1].Author != "lane@boot.dev (Lane Wagner)" {
+		t.Errorf("item author = %q, want the <author> element", items[1].Author)
+	}
+}
+
+func TestFetchFeedMaxItems(t *testing.T) {
+	srv := serveFixture(t, "rss.xml", "application/rss+xml")
+	f := NewFetcherWithClient(srv.Client(), config.FetchConfig{MaxItems: 2})
+
+	result, items, err := fetchAll(t, f, srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Truncated || len(items) != 2 {
+		t.Errorf("got %d items, truncated = %v; want 2 items, truncated", len(items), result.Truncated)
+	}
+}
+
+func TestFetchFeedMaxBodyBytes(t *testing.T) {
+	srv := serveFixture(t, "rss.xml", "application/rss+xml")
+	f := NewFetcherWithClient(srv.Client(), config.FetchConfig{MaxBodyBytes: 100})
+
+	_, _, err := fetchAll(t, f, srv.URL)
+	if !errors.Is(err, ErrTooLarge) {
+		t.Errorf("err = %v, want ErrTooLarge", err)
+	}
+}
+
+func TestFetchFeedGzip(t *testing.T) {
+	body, err := os.ReadFile(filepath.Join("testdata", "rss.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	zw.Write(body)
+	zw.Close()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(compressed.Bytes())
+	}))
+	defer srv.Close()
+
+	f := NewFetcherWithClient(srv.Client(), config.FetchConfig{})
+	result, items, err := fetchAll(t, f, srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != 3 {
+		t.Errorf("got %d items, want 3", len(items))
+	}
+	if result.ContentEncoding != "gzip" {
+		t.Errorf("content encoding = %q, want gzip", result.ContentEncoding)
+	}
+	if result.CompressedBytes != int64(compressed.Len()) || result.UncompressedBytes != int64(len(body)) {
+		t.Errorf("bytes = %d/%d, want %d/%d", result.CompressedBytes, result.UncompressedBytes, compressed.Len(), len(body))
+	}
+}
+
+func TestFetchFeedStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		check  func(error) bool
+	}{
+		{"gone", http.StatusGone, func(err error) bool { return errors.Is(err, ErrGone) }},
+		{"not found", http.StatusNotFound, func(err error) bool {
+			var statusErr *StatusError
+			return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			f := NewFetcherWithClient(srv.Client(), config.FetchConfig{})
+			if _, _, err := fetchAll(t, f, srv.URL); !tt.check(err) {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestFetchFeedPermanentRedirect(t *testing.T) {
+	feed := serveFixture(t, "rss.xml", "application/rss+xml")
+
+	tests := []struct {
+		name   string
+		status int
+		want   string
+	}{
+		{"moved permanently", http.StatusMovedPermanently, feed.URL + "/feed"},
+		{"permanent redirect", http.StatusPermanentRedirect, feed.URL + "/feed"},
+		{"found", http.StatusFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.RedirectHandler(feed.URL+"/feed", tt.status))
+			defer srv.Close()
+
+			f := NewFetcherWithClient(srv.Client(), config.FetchConfig{})
+			result, _, err := fetchAll(t, f, srv.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.PermanentURL != tt.want {
+				t.Errorf("permanent url = %q, want %q", result.PermanentURL, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchFeedUserAgent(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("User-Agent")
+		w.Write([]byte(emptyFeed))
+	}))
+	defer srv.Close()
+
+	f := NewFetcherWithClient(srv.Client(), config.FetchConfig{Contact: "https://example.com/bot"})
+	if _, _, err := fetchAll(t, f, srv.URL); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "gator/dev (+https://example.com/bot)"; got != want {
+		t.Errorf("user agent = %q, want %q", got, want)
+	}
+}
+
+func TestFetchFeedRedirectStripsAuth(t *testing.T) {
+	var got http.Header
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+		w.Write([]byte(emptyFeed))
+	}))
+	defer other.Close()
+
+	srv := httptest.NewServer(http.RedirectHandler(other.URL, http.StatusFound))
+	defer srv.Close()
+
+	auth := &Auth{BearerToken: "token", Header: http.Header{"X-Api-Key": {"key"}}}
+	f := NewFetcherWithClient(srv.Client(), config.FetchConfig{})
+	_, err := f.FetchFeed(context.Background(), srv.URL, auth, func(RSSItem) error { return nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Get("Authorization") != "" || got.Get("X-Api-Key") != "" {
+		t.Errorf("credentials sent to another host: %v", got)
+	}
+}
@@ -0,0 +1,63 @@
+package secret
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Validate checks that ref is a secret reference of the form "env:NAME" or
+// "file:/path/to/secret". Secrets themselves are never stored in the database.
+func Validate(ref string) error {
+	kind, target, ok := strings.Cut(ref, ":")
+	if !ok || target == "" || (kind != "env" && kind != "file") {
+		return fmt.Errorf("invalid secret reference %q (use env:NAME or file:/path)", ref)
+	}
+	return nil
+}
+
+// Allowed checks that ref matches one of patterns, path.Match patterns such as
+// "env:GATOR_*" or "file:/etc/gator/*". File paths must be clean so ".." cannot
+// step out of an allowed directory.
+func Allowed(ref string, patterns []string) error {
+	if err := Validate(ref); err != nil {
+		return err
+	}
+
+	kind, target, _ := strings.Cut(ref, ":")
+	if kind == "file" && filepath.Clean(target) != target {
+		return fmt.Errorf("secret file path %q is not clean", target)
+	}
+
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, ref); ok {
+			return nil
+		}
+	}
+	return fmt.Errorf("secret reference %q is not in allowed_secrets", ref)
+}
+
+// Resolve returns the secret that ref points to.
+func Resolve(ref string) (string, error) {
+	if err := Validate(ref); err != nil {
+		return "", err
+	}
+
+	kind, target, _ := strings.Cut(ref, ":")
+	switch kind {
+	case "env":
+		value, ok := os.LookupEnv(target)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", target)
+		}
+		return value, nil
+	default:
+		data, err := os.ReadFile(target)
+		if err != nil {
+			return "", fmt.Errorf("reading secret file: %w", err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	}
+}
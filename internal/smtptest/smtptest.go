@@ -0,0 +1,138 @@
+// Package smtptest is a local SMTP server for tests, in the spirit of
+// net/http/httptest. It accepts every message and keeps it in memory.
+package smtptest
+
+import (
+	"encoding/base64"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Message is one mail transaction received by the server.
+type Message struct {
+	From string
+	To   []string
+	// Auth is the "user:password" of an AUTH PLAIN before the transaction.
+	Auth string
+	Data string
+}
+
+type Server struct {
+	Host string
+	Port int
+
+	listener net.Listener
+	mu       sync.Mutex
+	messages []Message
+}
+
+// NewServer starts a server on a random port of 127.0.0.1 that is closed when
+// the test ends.
+func NewServer(t *testing.T) *Server {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().(*net.TCPAddr)
+
+	srv := &Server{Host: addr.IP.String(), Port: addr.Port, listener: listener}
+	go srv.serve()
+	t.Cleanup(func() { listener.Close() })
+	return srv
+}
+
+// Messages returns the messages received so far.
+func (srv *Server) Messages() []Message {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	return append([]Message(nil), srv.messages...)
+}
+
+func (srv *Server) serve() {
+	for {
+		conn, err := srv.listener.Accept()
+		if err != nil {
+			return
+		}
+		go srv.handle(textproto.NewConn(conn))
+	}
+}
+
+// handle speaks just enough ESMTP for net/smtp.SendMail: EHLO, AUTH PLAIN,
+// MAIL, RCPT, DATA, RSET and QUIT.
+func (srv *Server) handle(conn *textproto.Conn) {
+	defer conn.Close()
+
+	var msg Message
+	conn.PrintfLine("220 %s ESMTP smtptest", srv.Host)
+	for {
+		line, err := conn.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			conn.PrintfLine("250-%s\r\n250-8BITMIME\r\n250 AUTH PLAIN", srv.Host)
+		case "AUTH":
+			mechanism, initial, _ := strings.Cut(arg, " ")
+			decoded, err := base64.StdEncoding.DecodeString(initial)
+			if mechanism != "PLAIN" || err != nil {
+				conn.PrintfLine("504 unsupported authentication")
+				continue
+			}
+			// The PLAIN response is authzid NUL user NUL password.
+			parts := strings.SplitN(string(decoded), "\x00", 3)
+			if len(parts) != 3 {
+				conn.PrintfLine("501 malformed credentials")
+				continue
+			}
+			msg.Auth = parts[1] + ":" + parts[2]
+			conn.PrintfLine("235 authenticated")
+		case "MAIL":
+			msg.From = address(arg)
+			conn.PrintfLine("250 ok")
+		case "RCPT":
+			msg.To = append(msg.To, address(arg))
+			conn.PrintfLine("250 ok")
+		case "DATA":
+			conn.PrintfLine("354 end data with <CR><LF>.<CR><LF>")
+			lines, err := conn.ReadDotLines()
+			if err != nil {
+				return
+			}
+			msg.Data = strings.Join(lines, "\r\n")
+
+			srv.mu.Lock()
+			srv.messages = append(srv.messages, msg)
+			srv.mu.Unlock()
+
+			msg = Message{Auth: msg.Auth}
+			conn.PrintfLine("250 queued")
+		case "RSET":
+			msg = Message{Auth: msg.Auth}
+			conn.PrintfLine("250 ok")
+		case "NOOP":
+			conn.PrintfLine("250 ok")
+		case "QUIT":
+			conn.PrintfLine("221 bye")
+			return
+		default:
+			conn.PrintfLine("502 command not implemented")
+		}
+	}
+}
+
+// address extracts the path from "FROM:<a@example.com> BODY=8BITMIME".
+func address(arg string) string {
+	_, path, _ := strings.Cut(arg, "<")
+	path, _, _ = strings.Cut(path, ">")
+	return path
+}
@@ -4,11 +4,16 @@ import (
 	"database/sql"
 
 	"github.com/lmilojevicc/gator/internal/config"
-	"github.com/lmilojevicc/gator/internal/database"
+	"github.com/lmilojevicc/gator/internal/notify"
+	"github.com/lmilojevicc/gator/internal/rss"
+	"github.com/lmilojevicc/gator/internal/store"
 )
 
 type State struct {
-	Queries *database.Queries
-	Cfg     *config.Config
-	Conn    *sql.DB
+	Store store.Store
+	Cfg   *config.Config
+	// Conn is only used to run migrations and is nil for the in-memory store.
+	Conn     *sql.DB
+	Fetcher  *rss.Fetcher
+	Notifier *notify.Notifier
 }
@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/lmilojevicc/gator/internal/database"
+)
+
+func (s *Store) CreateCategory(ctx context.Context, arg database.CreateCategoryParams) (database.Category, error) {
+	defer s.lock()()
+
+	if s.data.hasCategory(arg.UserID, arg.Name) {
+		return database.Category{}, ErrDuplicate
+	}
+
+	category := database.Category{
+		ID:        arg.ID,
+		Seq:       nextSeq(s.data.categories, func(c database.Category) int64 { return c.Seq }),
+		UserID:    arg.UserID,
+		Name:      arg.Name,
+		CreatedAt: now(),
+	}
+	s.data.categories = append(s.data.categories, category)
+	return category, nil
+}
+
+func (s *Store) GetCategoriesForUser(ctx context.Context, userID uuid.UUID) ([]database.GetCategoriesForUserRow, error) {
+	defer s.lock()()
+
+	var rows []database.GetCategoriesForUserRow
+	for _, category := range s.data.categories {
+		if category.UserID != userID {
+			continue
+		}
+		row := database.GetCategoriesForUserRow{
+			ID:        category.ID,
+			Seq:       category.Seq,
+			UserID:    category.UserID,
+			Name:      category.Name,
+			CreatedAt: category.CreatedAt,
+		}
+		for _, follow := range s.data.follows {
+			if follow.CategoryID.Valid && follow.CategoryID.UUID == category.ID {
+				row.FeedCount++
+			}
+		}
+		rows = append(rows, row)
+	}
+	slices.SortFunc(rows, func(a, b database.GetCategoriesForUserRow) int { return strings.Compare(a.Name, b.Name) })
+	return rows, nil
+}
+
+func (s *Store) GetCategoryByName(ctx context.Context, arg database.GetCategoryByNameParams) (database.Category, error) {
+	defer s.lock()()
+
+	category, err := find(s.data.categories, func(c database.Category) bool {
+		return c.UserID == arg.UserID && c.Name == arg.Name
+	})
+	if err != nil {
+		return database.Category{}, err
+	}
+	return *category, nil
+}
+
+func (s *Store) RenameCategory(ctx context.Context, arg database.RenameCategoryParams) (int64, error) {
+	defer s.lock()()
+
+	category, err := find(s.data.categories, func(c database.Category) bool {
+		return c.ID == arg.ID && c.UserID == arg.UserID
+	})
+	if err != nil {
+		return 0, nil
+	}
+	if category.Name != arg.Name && s.data.hasCategory(arg.UserID, arg.Name) {
+		return 0, ErrDuplicate
+	}
+
+	category.Name = arg.Name
+	return 1, nil
+}
+
+func (s *Store) DeleteCategory(ctx context.Context, arg database.DeleteCategoryParams) (int64, error) {
+	defer s.lock()()
+
+	before := len(s.data.categories)
+	s.data.deleteCategories(func(c database.Category) bool {
+		return c.ID == arg.ID && c.UserID == arg.UserID
+	})
+	return int64(before - len(s.data.categories)), nil
+}
+
+func (s *Store) SetFollowCategory(ctx context.Context, arg database.SetFollowCategoryParams) (int64, error) {
+	defer s.lock()()
+
+	follow, err := find(s.data.follows, func(f database.FeedFollow) bool {
+		return f.UserID == arg.UserID && f.FeedID == arg.FeedID
+	})
+	if err != nil {
+		return 0, nil
+	}
+
+	follow.CategoryID = arg.CategoryID
+	follow.UpdatedAt = now()
+	return 1, nil
+}
+
+func (d *data) hasCategory(userID uuid.UUID, name string) bool {
+	return slices.ContainsFunc(d.categories, func(c database.Category) bool {
+		return c.UserID == userID && c.Name == name
+	})
+}
+
+// deleteCategories removes the categories matching match and, like ON DELETE
+// SET NULL, takes their follows out of them.
+func (d *data) deleteCategories(match func(database.Category) bool) {
+	deleted := map[uuid.UUID]bool{}
+	d.categories = slices.DeleteFunc(d.categories, func(c database.Category) bool {
+		if match(c) {
+			deleted[c.ID] = true
+			return true
+		}
+		return false
+	})
+
+	for i, follow := range d.follows {
+		if follow.CategoryID.Valid && deleted[follow.CategoryID.UUID] {
+			d.follows[i].CategoryID = uuid.NullUUID{}
+		}
+	}
+}
+
+// categoryName returns the name of the category id, if it is set.
+func (d *data) categoryName(id uuid.NullUUID) (name string, ok bool) {
+	if !id.Valid {
+		return "", false
+	}
+	category, err := find(d.categories, func(c database.Category) bool { return c.ID == id.UUID })
+	if err != nil {
+		return "", false
+	}
+	return category.Name, true
+}
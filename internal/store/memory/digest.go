@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/lmilojevicc/gator/internal/database"
+)
+
+func (s *Store) SetUserDigest(ctx context.Context, arg database.SetUserDigestParams) error {
+	defer s.lock()()
+
+	user, err := find(s.data.users, func(u database.User) bool { return u.ID == arg.ID })
+	if err != nil {
+		return nil
+	}
+	user.Email = arg.Email
+	user.DigestFrequency = arg.DigestFrequency
+	user.UpdatedAt = now()
+	return nil
+}
+
+func (s *Store) GetDigestSubscribers(ctx context.Context) ([]database.User, error) {
+	defer s.lock()()
+
+	var users []database.User
+	for _, user := range s.data.users {
+		if user.Email.Valid && user.DigestFrequency.Valid {
+			users = append(users, user)
+		}
+	}
+	slices.SortFunc(users, func(a, b database.User) int { return strings.Compare(a.Name, b.Name) })
+	return users, nil
+}
+
+func (s *Store) GetDigestPosts(ctx context.Context, arg database.GetDigestPostsParams) ([]database.GetDigestPostsRow, error) {
+	defer s.lock()()
+
+	var rows []database.GetDigestPostsRow
+	for _, post := range s.data.posts {
+		if !s.data.following(arg.UserID, post.FeedID) || post.Seq <= arg.AfterSeq ||
+			!post.CreatedAt.After(arg.AddedAfter) || post.CreatedAt.After(arg.AddedBefore) {
+			continue
+		}
+		feed, err := find(s.data.feeds, func(f database.Feed) bool { return f.ID == post.FeedID })
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, database.GetDigestPostsRow{
+			Seq:         post.Seq,
+			FeedID:      post.FeedID,
+			FeedName:    s.data.feedName(arg.UserID, *feed),
+			Title:       post.Title,
+			Url:         post.Url,
+			PublishedAt: post.PublishedAt,
+			Description: post.Description,
+			Author:      post.Author,
+			Categories:  post.Categories,
+		})
+	}
+	slices.SortFunc(rows, func(a, b database.GetDigestPostsRow) int { return cmp.Compare(a.Seq, b.Seq) })
+	return page(rows, int(arg.PageOffset), int(arg.MaxPosts)), nil
+}
+
+func (s *Store) SetUserLastDigest(ctx context.Context, arg database.SetUserLastDigestParams) error {
+	defer s.lock()()
+
+	user, err := find(s.data.users, func(u database.User) bool { return u.ID == arg.ID })
+	if err != nil {
+		return nil
+	}
+	user.LastDigestAt = arg.LastDigestAt
+	user.LastDigestSeq = arg.LastDigestSeq
+	return nil
+}
@@ -0,0 +1,315 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lmilojevicc/gator/internal/database"
+)
+
+func (s *Store) CreateFeed(ctx context.Context, arg database.CreateFeedParams) (database.Feed, error) {
+	defer s.lock()()
+
+	if slices.ContainsFunc(s.data.feeds, func(f database.Feed) bool { return f.Url == arg.Url }) {
+		return database.Feed{}, ErrDuplicate
+	}
+	if !slices.ContainsFunc(s.data.users, func(u database.User) bool { return u.ID == arg.UserID }) {
+		return database.Feed{}, sql.ErrNoRows
+	}
+
+	feed := database.Feed{
+		ID:        arg.ID,
+		Seq:       nextSeq(s.data.feeds, func(f database.Feed) int64 { return f.Seq }),
+		Name:      arg.Name,
+		Url:       arg.Url,
+		UserID:    arg.UserID,
+		CreatedAt: now(),
+		UpdatedAt: now(),
+	}
+	s.data.feeds = append(s.data.feeds, feed)
+	return feed, nil
+}
+
+func (s *Store) GetAllFeeds(ctx context.Context) ([]database.Feed, error) {
+	defer s.lock()()
+
+	return slices.Clone(s.data.feeds), nil
+}
+
+func (s *Store) GetFeedByURL(ctx context.Context, url string) (database.Feed, error) {
+	defer s.lock()()
+
+	feed, err := find(s.data.feeds, func(f database.Feed) bool { return f.Url == url })
+	if err != nil {
+		return database.Feed{}, err
+	}
+	return *feed, nil
+}
+
+func (s *Store) MarkFeedFetched(ctx context.Context, id uuid.UUID) error {
+	return s.updateFeed(id, func(f *database.Feed) {
+		f.UpdatedAt = now()
+		f.LastFetchedAt = sql.NullTime{Time: now(), Valid: true}
+	})
+}
+
+func (s *Store) GetNextFeedToFetch(ctx context.Context) (database.Feed, error) {
+	defer s.lock()()
+
+	var next *database.Feed
+	for i, feed := range s.data.feeds {
+		if feed.DeadAt.Valid {
+			continue
+		}
+		if next == nil || fetchedBefore(feed, *next) {
+			next = &s.data.feeds[i]
+		}
+	}
+	if next == nil {
+		return database.Feed{}, sql.ErrNoRows
+	}
+	return *next, nil
+}
+
+// fetchedBefore orders feeds by last_fetched_at ASC NULLS FIRST.
+func fetchedBefore(a, b database.Feed) bool {
+	switch {
+	case !a.LastFetchedAt.Valid:
+		return b.LastFetchedAt.Valid
+	case !b.LastFetchedAt.Valid:
+		return false
+	default:
+		return a.LastFetchedAt.Time.Before(b.LastFetchedAt.Time)
+	}
+}
+
+func (s *Store) RenameFeed(ctx context.Context, arg database.RenameFeedParams) (database.Feed, error) {
+	var renamed database.Feed
+	err := s.updateFeed(arg.ID, func(f *database.Feed) {
+		f.Name = arg.Name
+		f.UpdatedAt = now()
+		renamed = *f
+	})
+	return renamed, err
+}
+
+func (s *Store) UpdateFeedURL(ctx context.Context, arg database.UpdateFeedURLParams) (database.Feed, error) {
+	s.mu.Lock()
+	taken := slices.ContainsFunc(s.data.feeds, func(f database.Feed) bool { return f.Url == arg.Url && f.ID != arg.ID })
+	s.mu.Unlock()
+	if taken {
+		return database.Feed{}, ErrDuplicate
+	}
+
+	var updated database.Feed
+	err := s.updateFeed(arg.ID, func(f *database.Feed) {
+		f.Url = arg.Url
+		f.RedirectUrl = sql.NullString{}
+		f.RedirectCount = 0
+		f.DeadAt = sql.NullTime{}
+		f.UpdatedAt = now()
+		updated = *f
+	})
+	return updated, err
+}
+
+func (s *Store) DeleteFeed(ctx context.Context, id uuid.UUID) error {
+	defer s.lock()()
+
+	s.data.deleteFeeds(func(f database.Feed) bool { return f.ID == id })
+	return nil
+}
+
+func (s *Store) RecordFeedRedirect(ctx context.Context, arg database.RecordFeedRedirectParams) (int32, error) {
+	var count int32
+	err := s.updateFeed(arg.ID, func(f *database.Feed) {
+		if f.RedirectUrl.Valid && arg.RedirectUrl.Valid && f.RedirectUrl.String == arg.RedirectUrl.String {
+			f.RedirectCount++
+		} else {
+			f.RedirectCount = 1
+		}
+		f.RedirectUrl = arg.RedirectUrl
+		count = f.RedirectCount
+	})
+	return count, err
+}
+
+func (s *Store) ClearFeedRedirect(ctx context.Context, id uuid.UUID) error {
+	return s.updateFeed(id, func(f *database.Feed) {
+		f.RedirectUrl = sql.NullString{}
+		f.RedirectCount = 0
+	})
+}
+
+func (s *Store) MarkFeedDead(ctx context.Context, id uuid.UUID) error {
+	return s.updateFeed(id, func(f *database.Feed) {
+		f.UpdatedAt = now()
+		f.DeadAt = sql.NullTime{Time: now(), Valid: true}
+	})
+}
+
+// updateFeed applies update to the feed with id. Like an UPDATE ... RETURNING
+// it fails with sql.ErrNoRows when there is no such feed.
+func (s *Store) updateFeed(id uuid.UUID, update func(*database.Feed)) error {
+	defer s.lock()()
+
+	feed, err := find(s.data.feeds, func(f database.Feed) bool { return f.ID == id })
+	if err != nil {
+		return err
+	}
+	update(feed)
+	return nil
+}
+
+func (s *Store) CreateFeedHistory(ctx context.Context, arg database.CreateFeedHistoryParams) error {
+	defer s.lock()()
+
+	s.data.history = append(s.data.history, database.FeedHistory{
+		ID:        arg.ID,
+		CreatedAt: now(),
+		FeedID:    arg.FeedID,
+		Event:     arg.Event,
+		Detail:    arg.Detail,
+	})
+	return nil
+}
+
+func (s *Store) GetFeedHistory(ctx context.Context, feedID uuid.UUID) ([]database.FeedHistory, error) {
+	defer s.lock()()
+
+	var history []database.FeedHistory
+	for _, entry := range slices.Backward(s.data.history) {
+		if entry.FeedID == feedID {
+			history = append(history, entry)
+		}
+	}
+	slices.SortStableFunc(history, func(a, b database.FeedHistory) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return history, nil
+}
+
+func (s *Store) SetFeedCredential(ctx context.Context, arg database.SetFeedCredentialParams) error {
+	defer s.lock()()
+
+	existing, err := find(s.data.credentials, func(c database.FeedCredential) bool {
+		return c.FeedID == arg.FeedID && c.Kind == arg.Kind && c.HeaderName == arg.HeaderName
+	})
+	if err == nil {
+		existing.Username = arg.Username
+		existing.SecretRef = arg.SecretRef
+		existing.UpdatedAt = now()
+		return nil
+	}
+
+	s.data.credentials = append(s.data.credentials, database.FeedCredential{
+		ID:         arg.ID,
+		CreatedAt:  now(),
+		UpdatedAt:  now(),
+		FeedID:     arg.FeedID,
+		Kind:       arg.Kind,
+		HeaderName: arg.HeaderName,
+		Username:   arg.Username,
+		SecretRef:  arg.SecretRef,
+	})
+	return nil
+}
+
+func (s *Store) GetFeedCredentials(ctx context.Context, feedID uuid.UUID) ([]database.FeedCredential, error) {
+	defer s.lock()()
+
+	var credentials []database.FeedCredential
+	for _, credential := range s.data.credentials {
+		if credential.FeedID == feedID {
+			credentials = append(credentials, credential)
+		}
+	}
+	slices.SortFunc(credentials, func(a, b database.FeedCredential) int {
+		return cmp.Or(cmp.Compare(a.Kind, b.Kind), cmp.Compare(a.HeaderName, b.HeaderName))
+	})
+	return credentials, nil
+}
+
+func (s *Store) DeleteFeedAuthorization(ctx context.Context, feedID uuid.UUID) error {
+	defer s.lock()()
+
+	s.data.credentials = slices.DeleteFunc(s.data.credentials, func(c database.FeedCredential) bool {
+		return c.FeedID == feedID && (c.Kind == "basic" || c.Kind == "bearer")
+	})
+	return nil
+}
+
+func (s *Store) ClearFeedCredentials(ctx context.Context, feedID uuid.UUID) error {
+	defer s.lock()()
+
+	s.data.credentials = slices.DeleteFunc(s.data.credentials, func(c database.FeedCredential) bool {
+		return c.FeedID == feedID
+	})
+	return nil
+}
+
+func (s *Store) CreateFeedFetch(ctx context.Context, arg database.CreateFeedFetchParams) error {
+	defer s.lock()()
+
+	s.data.fetches = append(s.data.fetches, database.FeedFetch{
+		ID:                arg.ID,
+		FetchedAt:         now(),
+		FeedID:            arg.FeedID,
+		ContentEncoding:   arg.ContentEncoding,
+		CompressedBytes:   arg.CompressedBytes,
+		UncompressedBytes: arg.UncompressedBytes,
+	})
+	return nil
+}
+
+func (s *Store) GetFeedBandwidth(ctx context.Context, fetchedAt time.Time) ([]database.GetFeedBandwidthRow, error) {
+	defer s.lock()()
+
+	var rows []database.GetFeedBandwidthRow
+	for _, feed := range s.data.feeds {
+		row := database.GetFeedBandwidthRow{Name: feed.Name, Url: feed.Url}
+		for _, fetch := range s.data.fetches {
+			if fetch.FeedID != feed.ID || fetch.FetchedAt.Before(fetchedAt) {
+				continue
+			}
+			row.Fetches++
+			row.CompressedBytes += fetch.CompressedBytes
+			row.UncompressedBytes += fetch.UncompressedBytes
+		}
+		if row.Fetches > 0 {
+			rows = append(rows, row)
+		}
+	}
+	slices.SortStableFunc(rows, func(a, b database.GetFeedBandwidthRow) int {
+		return cmp.Compare(b.CompressedBytes, a.CompressedBytes)
+	})
+	return rows, nil
+}
+
+// deleteFeeds removes the matching feeds along with the rows that reference
+// them, like the ON DELETE CASCADE clauses of the schema.
+func (d *data) deleteFeeds(match func(database.Feed) bool) {
+	deleted := map[uuid.UUID]bool{}
+	d.feeds = slices.DeleteFunc(d.feeds, func(f database.Feed) bool {
+		if match(f) {
+			deleted[f.ID] = true
+			return true
+		}
+		return false
+	})
+
+	d.follows = slices.DeleteFunc(d.follows, func(f database.FeedFollow) bool { return deleted[f.FeedID] })
+	d.history = slices.DeleteFunc(d.history, func(h database.FeedHistory) bool { return deleted[h.FeedID] })
+	d.credentials = slices.DeleteFunc(d.credentials, func(c database.FeedCredential) bool { return deleted[c.FeedID] })
+	d.fetches = slices.DeleteFunc(d.fetches, func(f database.FeedFetch) bool { return deleted[f.FeedID] })
+	d.deleteRules(func(r database.NotificationRule) bool { return r.FeedID.Valid && deleted[r.FeedID.UUID] })
+	d.deliveries = slices.DeleteFunc(d.deliveries, func(n database.NotificationDelivery) bool { return deleted[n.FeedID] })
+	d.filters = slices.DeleteFunc(d.filters, func(r database.FilterRule) bool { return r.FeedID.Valid && deleted[r.FeedID.UUID] })
+	d.pruned = slices.DeleteFunc(d.pruned, func(p database.PrunedPost) bool { return deleted[p.FeedID] })
+	d.deletePosts(func(p database.Post) bool { return deleted[p.FeedID] })
+}
@@ -0,0 +1,154 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/lmilojevicc/gator/internal/database"
+)
+
+func (s *Store) GetFeverFeeds(ctx context.Context, userID uuid.UUID) ([]database.GetFeverFeedsRow, error) {
+	defer s.lock()()
+
+	var rows []database.GetFeverFeedsRow
+	for _, follow := range s.data.follows {
+		if follow.UserID != userID {
+			continue
+		}
+		feed, err := find(s.data.feeds, func(f database.Feed) bool { return f.ID == follow.FeedID })
+		if err != nil {
+			return nil, err
+		}
+		row := database.GetFeverFeedsRow{
+			Seq:           feed.Seq,
+			Name:          cmp.Or(follow.Title.String, feed.Name),
+			Url:           feed.Url,
+			LastFetchedAt: feed.LastFetchedAt,
+		}
+		if category, err := find(s.data.categories, func(c database.Category) bool {
+			return follow.CategoryID.Valid && c.ID == follow.CategoryID.UUID
+		}); err == nil {
+			row.CategorySeq.Int64, row.CategorySeq.Valid = category.Seq, true
+		}
+		rows = append(rows, row)
+	}
+	slices.SortFunc(rows, func(a, b database.GetFeverFeedsRow) int { return cmp.Compare(a.Seq, b.Seq) })
+	return rows, nil
+}
+
+func (s *Store) GetFeverItemsAfter(ctx context.Context, arg database.GetFeverItemsAfterParams) ([]database.GetFeverItemsAfterRow, error) {
+	defer s.lock()()
+
+	items, err := s.data.feverItems(arg.UserID, func(p database.Post) bool { return p.Seq > arg.AfterSeq })
+	if err != nil {
+		return nil, err
+	}
+	return items[:min(len(items), int(arg.MaxItems))], nil
+}
+
+func (s *Store) GetFeverItemsBefore(ctx context.Context, arg database.GetFeverItemsBeforeParams) ([]database.GetFeverItemsBeforeRow, error) {
+	defer s.lock()()
+
+	items, err := s.data.feverItems(arg.UserID, func(p database.Post) bool { return p.Seq < arg.BeforeSeq })
+	if err != nil {
+		return nil, err
+	}
+	slices.Reverse(items)
+
+	var rows []database.GetFeverItemsBeforeRow
+	for _, item := range items[:min(len(items), int(arg.MaxItems))] {
+		rows = append(rows, database.GetFeverItemsBeforeRow(item))
+	}
+	return rows, nil
+}
+
+func (s *Store) GetFeverItemStates(ctx context.Context, userID uuid.UUID) ([]database.GetFeverItemStatesRow, error) {
+	defer s.lock()()
+
+	items, err := s.data.feverItems(userID, func(database.Post) bool { return true })
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []database.GetFeverItemStatesRow
+	for _, item := range items {
+		rows = append(rows, database.GetFeverItemStatesRow{
+			Seq:         item.Seq,
+			FeedID:      item.FeedID,
+			Title:       item.Title,
+			Description: item.Description,
+			Author:      item.Author,
+			Categories:  item.Categories,
+			IsRead:      item.IsRead,
+			IsSaved:     item.IsSaved,
+		})
+	}
+	return rows, nil
+}
+
+func (s *Store) MarkFeverPostsRead(ctx context.Context, arg database.MarkFeverPostsReadParams) error {
+	defer s.lock()()
+
+	for _, post := range s.data.posts {
+		if !s.data.following(arg.UserID, post.FeedID) || post.CreatedAt.After(arg.AddedBefore) {
+			continue
+		}
+		if arg.FeedSeq.Valid && !slices.ContainsFunc(s.data.feeds, func(f database.Feed) bool {
+			return f.ID == post.FeedID && f.Seq == arg.FeedSeq.Int64
+		}) {
+			continue
+		}
+		if s.data.read(arg.UserID, post.ID) {
+			continue
+		}
+		s.data.readPosts = append(s.data.readPosts, database.ReadPost{
+			UserID: arg.UserID,
+			PostID: post.ID,
+			ReadAt: now(),
+		})
+	}
+	return nil
+}
+
+// feverItems returns the matching posts from the feeds userID follows,
+// ordered by seq.
+func (d *data) feverItems(userID uuid.UUID, match func(database.Post) bool) ([]database.GetFeverItemsAfterRow, error) {
+	var rows []database.GetFeverItemsAfterRow
+	for _, post := range d.posts {
+		if !d.following(userID, post.FeedID) || !match(post) {
+			continue
+		}
+		feed, err := find(d.feeds, func(f database.Feed) bool { return f.ID == post.FeedID })
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, database.GetFeverItemsAfterRow{
+			ID:          post.ID,
+			Seq:         post.Seq,
+			FeedSeq:     feed.Seq,
+			Title:       post.Title,
+			Url:         post.Url,
+			Description: post.Description,
+			PublishedAt: post.PublishedAt,
+			CreatedAt:   post.CreatedAt,
+			FeedID:      post.FeedID,
+			Author:      post.Author,
+			Categories:  post.Categories,
+			IsRead:      d.read(userID, post.ID),
+			IsSaved: slices.ContainsFunc(d.savedPosts, func(p database.SavedPost) bool {
+				return p.UserID == userID && p.PostID == post.ID
+			}),
+		})
+	}
+	slices.SortFunc(rows, func(a, b database.GetFeverItemsAfterRow) int { return cmp.Compare(a.Seq, b.Seq) })
+	return rows, nil
+}
+
+func (d *data) following(userID, feedID uuid.UUID) bool {
+	return slices.ContainsFunc(d.follows, func(f database.FeedFollow) bool {
+		return f.UserID == userID && f.FeedID == feedID
+	})
+}
@@ -0,0 +1,61 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/lmilojevicc/gator/internal/database"
+)
+
+func (s *Store) CreateFilterRule(ctx context.Context, arg database.CreateFilterRuleParams) (database.FilterRule, error) {
+	defer s.lock()()
+
+	rule := database.FilterRule{
+		ID:        arg.ID,
+		UserID:    arg.UserID,
+		FeedID:    arg.FeedID,
+		Action:    arg.Action,
+		Kind:      arg.Kind,
+		Pattern:   arg.Pattern,
+		CreatedAt: now(),
+	}
+	s.data.filters = append(s.data.filters, rule)
+	return rule, nil
+}
+
+func (s *Store) GetFilterRulesForUser(ctx context.Context, userID uuid.UUID) ([]database.GetFilterRulesForUserRow, error) {
+	defer s.lock()()
+
+	var rows []database.GetFilterRulesForUserRow
+	for _, rule := range s.data.filters {
+		if rule.UserID != userID {
+			continue
+		}
+		row := database.GetFilterRulesForUserRow{
+			ID:        rule.ID,
+			UserID:    rule.UserID,
+			FeedID:    rule.FeedID,
+			Action:    rule.Action,
+			Kind:      rule.Kind,
+			Pattern:   rule.Pattern,
+			CreatedAt: rule.CreatedAt,
+		}
+		if feed, err := find(s.data.feeds, func(f database.Feed) bool { return rule.FeedID.Valid && f.ID == rule.FeedID.UUID }); err == nil {
+			row.FeedName.String, row.FeedName.Valid = feed.Name, true
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func (s *Store) DeleteFilterRule(ctx context.Context, arg database.DeleteFilterRuleParams) (int64, error) {
+	defer s.lock()()
+
+	before := len(s.data.filters)
+	s.data.filters = slices.DeleteFunc(s.data.filters, func(r database.FilterRule) bool {
+		return r.ID == arg.ID && r.UserID == arg.UserID
+	})
+	return int64(before - len(s.data.filters)), nil
+}
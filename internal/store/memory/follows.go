@@ -0,0 +1,142 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/lmilojevicc/gator/internal/database"
+)
+
+func (s *Store) CreateFeedFollow(ctx context.Context, arg database.CreateFeedFollowParams) (database.CreateFeedFollowRow, error) {
+	defer s.lock()()
+
+	if slices.ContainsFunc(s.data.follows, func(f database.FeedFollow) bool {
+		return f.UserID == arg.UserID && f.FeedID == arg.FeedID
+	}) {
+		return database.CreateFeedFollowRow{}, ErrDuplicate
+	}
+
+	follow := database.FeedFollow{
+		ID:        arg.ID,
+		CreatedAt: now(),
+		UpdatedAt: now(),
+		UserID:    arg.UserID,
+		FeedID:    arg.FeedID,
+	}
+	row, err := s.data.followRow(follow)
+	if err != nil {
+		return database.CreateFeedFollowRow{}, err
+	}
+
+	s.data.follows = append(s.data.follows, follow)
+	return database.CreateFeedFollowRow{
+		ID:         row.ID,
+		CreatedAt:  row.CreatedAt,
+		UpdatedAt:  row.UpdatedAt,
+		UserID:     row.UserID,
+		FeedID:     row.FeedID,
+		Title:      row.Title,
+		CategoryID: row.CategoryID,
+		FeedName:   row.FeedName,
+		UserName:   row.UserName,
+	}, nil
+}
+
+func (s *Store) GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) ([]database.GetFeedFollowsForUserRow, error) {
+	defer s.lock()()
+
+	var rows []database.GetFeedFollowsForUserRow
+	for _, follow := range s.data.follows {
+		if follow.UserID != userID {
+			continue
+		}
+		row, err := s.data.followRow(follow)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func (s *Store) Unfollow(ctx context.Context, arg database.UnfollowParams) (database.FeedFollow, error) {
+	defer s.lock()()
+
+	i := slices.IndexFunc(s.data.follows, func(f database.FeedFollow) bool {
+		return f.UserID == arg.UserID && f.FeedID == arg.FeedID
+	})
+	if i < 0 {
+		return database.FeedFollow{}, sql.ErrNoRows
+	}
+
+	follow := s.data.follows[i]
+	s.data.follows = slices.Delete(s.data.follows, i, i+1)
+	return follow, nil
+}
+
+func (s *Store) CountOtherFeedFollowers(ctx context.Context, arg database.CountOtherFeedFollowersParams) (int64, error) {
+	defer s.lock()()
+
+	var count int64
+	for _, follow := range s.data.follows {
+		if follow.FeedID == arg.FeedID && follow.UserID != arg.UserID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (s *Store) SetFollowTitle(ctx context.Context, arg database.SetFollowTitleParams) (int64, error) {
+	defer s.lock()()
+
+	follow, err := find(s.data.follows, func(f database.FeedFollow) bool {
+		return f.UserID == arg.UserID && f.FeedID == arg.FeedID
+	})
+	if err != nil {
+		return 0, nil
+	}
+
+	follow.Title = arg.Title
+	follow.UpdatedAt = now()
+	return 1, nil
+}
+
+// feedName is the name userID sees for feed: the title of their follow, if
+// they gave it one.
+func (d *data) feedName(userID uuid.UUID, feed database.Feed) string {
+	follow, err := find(d.follows, func(f database.FeedFollow) bool { return f.UserID == userID && f.FeedID == feed.ID })
+	if err != nil {
+		return feed.Name
+	}
+	return cmp.Or(follow.Title.String, feed.Name)
+}
+
+// followRow joins follow with the names of its feed, user and category.
+func (d *data) followRow(follow database.FeedFollow) (database.GetFeedFollowsForUserRow, error) {
+	feed, err := find(d.feeds, func(f database.Feed) bool { return f.ID == follow.FeedID })
+	if err != nil {
+		return database.GetFeedFollowsForUserRow{}, err
+	}
+	user, err := find(d.users, func(u database.User) bool { return u.ID == follow.UserID })
+	if err != nil {
+		return database.GetFeedFollowsForUserRow{}, err
+	}
+
+	row := database.GetFeedFollowsForUserRow{
+		ID:         follow.ID,
+		CreatedAt:  follow.CreatedAt,
+		UpdatedAt:  follow.UpdatedAt,
+		UserID:     follow.UserID,
+		FeedID:     follow.FeedID,
+		Title:      follow.Title,
+		CategoryID: follow.CategoryID,
+		FeedName:   cmp.Or(follow.Title.String, feed.Name),
+		UserName:   user.Name,
+	}
+	row.CategoryName.String, row.CategoryName.Valid = d.categoryName(follow.CategoryID)
+	return row, nil
+}
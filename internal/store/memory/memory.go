@@ -0,0 +1,136 @@
+// Package memory is an in-memory store.Store for tests. It mirrors the
+// behavior of the SQL queries closely enough for handler tests, including
+// unique constraints and ON DELETE CASCADE, but makes no attempt at
+// performance.
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/lmilojevicc/gator/internal/database"
+	"github.com/lmilojevicc/gator/internal/store"
+)
+
+// ErrDuplicate is returned where the SQL schema has a unique constraint.
+var ErrDuplicate = errors.New("duplicate key value violates unique constraint")
+
+type Store struct {
+	// txMu serializes transactions; mu guards data.
+	txMu sync.Mutex
+	mu   sync.Mutex
+	data data
+}
+
+var _ store.Store = (*Store)(nil)
+
+func New() *Store {
+	return &Store{}
+}
+
+type data struct {
+	users       []database.User
+	sessions    []database.Session
+	feeds       []database.Feed
+	follows     []database.FeedFollow
+	posts       []database.Post
+	savedPosts  []database.SavedPost
+	readPosts   []database.ReadPost
+	history     []database.FeedHistory
+	credentials []database.FeedCredential
+	fetches     []database.FeedFetch
+	rules       []database.NotificationRule
+	deliveries  []database.NotificationDelivery
+	filters     []database.FilterRule
+	categories  []database.Category
+	pruned      []database.PrunedPost
+}
+
+func (d data) clone() data {
+	return data{
+		users:       slices.Clone(d.users),
+		sessions:    slices.Clone(d.sessions),
+		feeds:       slices.Clone(d.feeds),
+		follows:     slices.Clone(d.follows),
+		posts:       slices.Clone(d.posts),
+		savedPosts:  slices.Clone(d.savedPosts),
+		readPosts:   slices.Clone(d.readPosts),
+		history:     slices.Clone(d.history),
+		credentials: slices.Clone(d.credentials),
+		fetches:     slices.Clone(d.fetches),
+		rules:       slices.Clone(d.rules),
+		deliveries:  slices.Clone(d.deliveries),
+		filters:     slices.Clone(d.filters),
+		categories:  slices.Clone(d.categories),
+		pruned:      slices.Clone(d.pruned),
+	}
+}
+
+// InTx takes a snapshot of the data and restores it if fn fails.
+func (s *Store) InTx(ctx context.Context, fn func(store.Store) error) error {
+	s.txMu.Lock()
+	defer s.txMu.Unlock()
+
+	s.mu.Lock()
+	snapshot := s.data.clone()
+	s.mu.Unlock()
+
+	if err := fn(tx{s}); err != nil {
+		s.mu.Lock()
+		s.data = snapshot
+		s.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+// tx is handed to InTx callbacks so that nested InTx calls join the
+// transaction instead of deadlocking on txMu.
+type tx struct {
+	*Store
+}
+
+func (t tx) InTx(ctx context.Context, fn func(store.Store) error) error {
+	return fn(t)
+}
+
+func (s *Store) lock() func() {
+	s.mu.Lock()
+	return s.mu.Unlock
+}
+
+func now() time.Time {
+	return time.Now().UTC()
+}
+
+// find returns a pointer to the first element of items matching match, or
+// sql.ErrNoRows like a :one query that matched nothing.
+func find[T any](items []T, match func(T) bool) (*T, error) {
+	i := slices.IndexFunc(items, match)
+	if i < 0 {
+		return nil, sql.ErrNoRows
+	}
+	return &items[i], nil
+}
+
+// nextSeq numbers a new row one past the highest seq so far, like the
+// identity columns of the PostgreSQL schema.
+func nextSeq[T any](items []T, seq func(T) int64) int64 {
+	var highest int64
+	for _, item := range items {
+		highest = max(highest, seq(item))
+	}
+	return highest + 1
+}
+
+// page returns the items a query with LIMIT limit OFFSET offset would.
+func page[T any](items []T, offset, limit int) []T {
+	start := min(len(items), offset)
+	end := min(len(items), start+limit)
+	return items[start:end]
+}
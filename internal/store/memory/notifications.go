@@ -0,0 +1,138 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/lmilojevicc/gator/internal/database"
+)
+
+func (s *Store) CreateNotificationRule(ctx context.Context, arg database.CreateNotificationRuleParams) (database.NotificationRule, error) {
+	defer s.lock()()
+
+	rule := database.NotificationRule{
+		ID:         arg.ID,
+		UserID:     arg.UserID,
+		FeedID:     arg.FeedID,
+		Keyword:    arg.Keyword,
+		WebhookUrl: arg.WebhookUrl,
+		Format:     arg.Format,
+		CreatedAt:  now(),
+	}
+	s.data.rules = append(s.data.rules, rule)
+	return rule, nil
+}
+
+func (s *Store) GetNotificationRulesForUser(ctx context.Context, userID uuid.UUID) ([]database.GetNotificationRulesForUserRow, error) {
+	defer s.lock()()
+
+	var rows []database.GetNotificationRulesForUserRow
+	for _, rule := range s.data.rules {
+		if rule.UserID != userID {
+			continue
+		}
+		row := database.GetNotificationRulesForUserRow{
+			ID:         rule.ID,
+			UserID:     rule.UserID,
+			FeedID:     rule.FeedID,
+			Keyword:    rule.Keyword,
+			WebhookUrl: rule.WebhookUrl,
+			Format:     rule.Format,
+			CreatedAt:  rule.CreatedAt,
+		}
+		if feed, err := find(s.data.feeds, func(f database.Feed) bool { return rule.FeedID.Valid && f.ID == rule.FeedID.UUID }); err == nil {
+			row.FeedName.String, row.FeedName.Valid = feed.Name, true
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func (s *Store) GetNotificationRulesForFeed(ctx context.Context, feedID uuid.UUID) ([]database.NotificationRule, error) {
+	defer s.lock()()
+
+	var rules []database.NotificationRule
+	for _, rule := range s.data.rules {
+		if !s.data.following(rule.UserID, feedID) {
+			continue
+		}
+		if rule.FeedID.Valid && rule.FeedID.UUID != feedID {
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func (s *Store) DeleteNotificationRule(ctx context.Context, arg database.DeleteNotificationRuleParams) (int64, error) {
+	defer s.lock()()
+
+	before := len(s.data.rules)
+	s.data.deleteRules(func(r database.NotificationRule) bool { return r.ID == arg.ID && r.UserID == arg.UserID })
+	return int64(before - len(s.data.rules)), nil
+}
+
+func (s *Store) CreateNotificationDelivery(ctx context.Context, arg database.CreateNotificationDeliveryParams) error {
+	defer s.lock()()
+
+	s.data.deliveries = append(s.data.deliveries, database.NotificationDelivery{
+		ID:          arg.ID,
+		RuleID:      arg.RuleID,
+		FeedID:      arg.FeedID,
+		PostCount:   arg.PostCount,
+		Attempts:    arg.Attempts,
+		StatusCode:  arg.StatusCode,
+		Error:       arg.Error,
+		DeliveredAt: arg.DeliveredAt,
+		CreatedAt:   now(),
+	})
+	return nil
+}
+
+func (s *Store) GetNotificationDeliveries(ctx context.Context, arg database.GetNotificationDeliveriesParams) ([]database.GetNotificationDeliveriesRow, error) {
+	defer s.lock()()
+
+	var rows []database.GetNotificationDeliveriesRow
+	for _, delivery := range s.data.deliveries {
+		rule, err := find(s.data.rules, func(r database.NotificationRule) bool { return r.ID == delivery.RuleID })
+		if err != nil || rule.UserID != arg.UserID {
+			continue
+		}
+		feed, err := find(s.data.feeds, func(f database.Feed) bool { return f.ID == delivery.FeedID })
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, database.GetNotificationDeliveriesRow{
+			ID:          delivery.ID,
+			RuleID:      delivery.RuleID,
+			FeedID:      delivery.FeedID,
+			PostCount:   delivery.PostCount,
+			Attempts:    delivery.Attempts,
+			StatusCode:  delivery.StatusCode,
+			Error:       delivery.Error,
+			DeliveredAt: delivery.DeliveredAt,
+			CreatedAt:   delivery.CreatedAt,
+			WebhookUrl:  rule.WebhookUrl,
+			FeedName:    feed.Name,
+		})
+	}
+	slices.SortStableFunc(rows, func(a, b database.GetNotificationDeliveriesRow) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	return rows[:min(len(rows), int(arg.Limit))], nil
+}
+
+// deleteRules removes the matching rules and their deliveries.
+func (d *data) deleteRules(match func(database.NotificationRule) bool) {
+	deleted := map[uuid.UUID]bool{}
+	d.rules = slices.DeleteFunc(d.rules, func(r database.NotificationRule) bool {
+		if match(r) {
+			deleted[r.ID] = true
+			return true
+		}
+		return false
+	})
+	d.deliveries = slices.DeleteFunc(d.deliveries, func(n database.NotificationDelivery) bool { return deleted[n.RuleID] })
+}
@@ -0,0 +1,378 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lmilojevicc/gator/internal/database"
+)
+
+func (s *Store) CreatePosts(ctx context.Context, arg database.CreatePostsParams) ([]uuid.UUID, error) {
+	defer s.lock()()
+
+	var created []uuid.UUID
+	for i := range arg.Ids {
+		if slices.ContainsFunc(s.data.posts, func(p database.Post) bool { return p.Url == arg.Urls[i] }) {
+			continue
+		}
+		if slices.ContainsFunc(s.data.pruned, func(p database.PrunedPost) bool { return p.Url == arg.Urls[i] }) {
+			continue
+		}
+
+		s.data.posts = append(s.data.posts, database.Post{
+			ID:          arg.Ids[i],
+			Seq:         nextSeq(s.data.posts, func(p database.Post) int64 { return p.Seq }),
+			CreatedAt:   now(),
+			UpdatedAt:   now(),
+			Title:       sql.NullString{String: arg.Titles[i], Valid: arg.Titles[i] != ""},
+			Url:         arg.Urls[i],
+			Description: sql.NullString{String: arg.Descriptions[i], Valid: arg.Descriptions[i] != ""},
+			PublishedAt: sql.NullTime{Time: arg.PublishedAts[i], Valid: true},
+			FeedID:      arg.FeedID,
+			Author:      sql.NullString{String: arg.Authors[i], Valid: arg.Authors[i] != ""},
+			Categories:  sql.NullString{String: arg.Categories[i], Valid: arg.Categories[i] != ""},
+		})
+		created = append(created, arg.Ids[i])
+	}
+	return created, nil
+}
+
+func (s *Store) GetPostsByUser(ctx context.Context, arg database.GetPostsByUserParams) ([]database.GetPostsByUserRow, error) {
+	defer s.lock()()
+
+	var posts []database.Post
+	for _, post := range s.data.posts {
+		if slices.ContainsFunc(s.data.follows, func(f database.FeedFollow) bool {
+			return f.UserID == arg.UserID && f.FeedID == post.FeedID
+		}) {
+			posts = append(posts, post)
+		}
+	}
+	slices.SortStableFunc(posts, newestFirst)
+
+	var rows []database.GetPostsByUserRow
+	for _, post := range page(posts, int(arg.Offset), int(arg.Limit)) {
+		rows = append(rows, database.GetPostsByUserRow{
+			Title:       post.Title,
+			Url:         post.Url,
+			Description: post.Description,
+			PublishedAt: post.PublishedAt,
+			FeedID:      post.FeedID,
+			Author:      post.Author,
+			Categories:  post.Categories,
+		})
+	}
+	return rows, nil
+}
+
+func (s *Store) GetPostsForUser(ctx context.Context, arg database.GetPostsForUserParams) ([]database.GetPostsForUserRow, error) {
+	defer s.lock()()
+
+	var posts []database.Post
+	for _, post := range s.data.posts {
+		follow, err := find(s.data.follows, func(f database.FeedFollow) bool {
+			return f.UserID == arg.UserID && f.FeedID == post.FeedID
+		})
+		if err != nil {
+			continue
+		}
+		if arg.UnreadOnly && s.data.read(arg.UserID, post.ID) {
+			continue
+		}
+		if arg.FeedID.Valid && post.FeedID != arg.FeedID.UUID {
+			continue
+		}
+		if arg.CategoryID.Valid && follow.CategoryID != arg.CategoryID {
+			continue
+		}
+		posts = append(posts, post)
+	}
+	slices.SortStableFunc(posts, func(a, b database.Post) int {
+		return cmp.Or(newestFirst(a, b), strings.Compare(a.ID.String(), b.ID.String()))
+	})
+
+	var rows []database.GetPostsForUserRow
+	for _, post := range page(posts, int(arg.PageOffset), int(arg.PageSize)) {
+		row, err := s.data.postRow(arg.UserID, post)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func (s *Store) GetPostForUser(ctx context.Context, arg database.GetPostForUserParams) (database.GetPostForUserRow, error) {
+	defer s.lock()()
+
+	post, err := find(s.data.posts, func(p database.Post) bool { return p.ID == arg.ID })
+	if err != nil {
+		return database.GetPostForUserRow{}, err
+	}
+	if !slices.ContainsFunc(s.data.follows, func(f database.FeedFollow) bool {
+		return f.UserID == arg.UserID && f.FeedID == post.FeedID
+	}) {
+		return database.GetPostForUserRow{}, sql.ErrNoRows
+	}
+
+	row, err := s.data.postRow(arg.UserID, *post)
+	return database.GetPostForUserRow(row), err
+}
+
+func (s *Store) GetPostByID(ctx context.Context, id uuid.UUID) (database.Post, error) {
+	defer s.lock()()
+
+	post, err := find(s.data.posts, func(p database.Post) bool { return p.ID == id })
+	if err != nil {
+		return database.Post{}, err
+	}
+	return *post, nil
+}
+
+func (s *Store) GetPostByURL(ctx context.Context, url string) (database.Post, error) {
+	defer s.lock()()
+
+	post, err := find(s.data.posts, func(p database.Post) bool { return p.Url == url })
+	if err != nil {
+		return database.Post{}, err
+	}
+	return *post, nil
+}
+
+func (s *Store) SavePost(ctx context.Context, arg database.SavePostParams) error {
+	defer s.lock()()
+
+	if slices.ContainsFunc(s.data.savedPosts, func(p database.SavedPost) bool {
+		return p.UserID == arg.UserID && p.PostID == arg.PostID
+	}) {
+		return nil
+	}
+
+	s.data.savedPosts = append(s.data.savedPosts, database.SavedPost{
+		UserID:    arg.UserID,
+		PostID:    arg.PostID,
+		CreatedAt: now(),
+	})
+	return nil
+}
+
+func (s *Store) UnsavePost(ctx context.Context, arg database.UnsavePostParams) (int64, error) {
+	defer s.lock()()
+
+	before := len(s.data.savedPosts)
+	s.data.savedPosts = slices.DeleteFunc(s.data.savedPosts, func(p database.SavedPost) bool {
+		return p.UserID == arg.UserID && p.PostID == arg.PostID
+	})
+	return int64(before - len(s.data.savedPosts)), nil
+}
+
+func (s *Store) MarkPostRead(ctx context.Context, arg database.MarkPostReadParams) error {
+	defer s.lock()()
+
+	if s.data.read(arg.UserID, arg.PostID) {
+		return nil
+	}
+
+	s.data.readPosts = append(s.data.readPosts, database.ReadPost{
+		UserID: arg.UserID,
+		PostID: arg.PostID,
+		ReadAt: now(),
+	})
+	return nil
+}
+
+func (s *Store) MarkPostUnread(ctx context.Context, arg database.MarkPostUnreadParams) error {
+	defer s.lock()()
+
+	s.data.readPosts = slices.DeleteFunc(s.data.readPosts, func(p database.ReadPost) bool {
+		return p.UserID == arg.UserID && p.PostID == arg.PostID
+	})
+	return nil
+}
+
+func (s *Store) PrunePostsOlderThan(ctx context.Context, cutoff time.Time) ([]database.PrunePostsOlderThanRow, error) {
+	defer s.lock()()
+
+	deleted := s.data.deletePosts(func(p database.Post) bool {
+		postedAt := p.CreatedAt
+		if p.PublishedAt.Valid {
+			postedAt = p.PublishedAt.Time
+		}
+		return postedAt.Before(cutoff) && !s.data.saved(p.ID)
+	})
+	s.data.tombstone(deleted)
+	return s.data.removedByFeed(deleted), nil
+}
+
+func (s *Store) PrunePostsBeyondNewest(ctx context.Context, keep int64) ([]database.PrunePostsBeyondNewestRow, error) {
+	defer s.lock()()
+
+	byFeed := map[uuid.UUID][]database.Post{}
+	for _, post := range s.data.posts {
+		byFeed[post.FeedID] = append(byFeed[post.FeedID], post)
+	}
+
+	beyond := map[uuid.UUID]bool{}
+	for _, posts := range byFeed {
+		slices.SortStableFunc(posts, func(a, b database.Post) int {
+			return cmp.Or(newestFirst(a, b), b.CreatedAt.Compare(a.CreatedAt))
+		})
+		for _, post := range posts[min(int64(len(posts)), keep):] {
+			beyond[post.ID] = true
+		}
+	}
+
+	deleted := s.data.deletePosts(func(p database.Post) bool {
+		return beyond[p.ID] && !s.data.saved(p.ID)
+	})
+	s.data.tombstone(deleted)
+
+	var rows []database.PrunePostsBeyondNewestRow
+	for _, row := range s.data.removedByFeed(deleted) {
+		rows = append(rows, database.PrunePostsBeyondNewestRow(row))
+	}
+	return rows, nil
+}
+
+func (s *Store) MarkPrunedPostsSeen(ctx context.Context, arg database.MarkPrunedPostsSeenParams) error {
+	defer s.lock()()
+
+	for i, pruned := range s.data.pruned {
+		if pruned.FeedID == arg.FeedID && slices.Contains(arg.Urls, pruned.Url) {
+			s.data.pruned[i].SeenAt = sql.NullTime{Time: now(), Valid: true}
+		}
+	}
+	return nil
+}
+
+func (s *Store) ExpirePrunedPosts(ctx context.Context) (int64, error) {
+	defer s.lock()()
+
+	latest := map[uuid.UUID]time.Time{}
+	for _, fetch := range s.data.fetches {
+		if fetch.FetchedAt.After(latest[fetch.FeedID]) {
+			latest[fetch.FeedID] = fetch.FetchedAt
+		}
+	}
+
+	n := len(s.data.pruned)
+	s.data.pruned = slices.DeleteFunc(s.data.pruned, func(p database.PrunedPost) bool {
+		seenAt := p.PrunedAt
+		if p.SeenAt.Valid {
+			seenAt = p.SeenAt.Time
+		}
+		return seenAt.Before(latest[p.FeedID])
+	})
+	return int64(n - len(s.data.pruned)), nil
+}
+
+// newestFirst orders posts by published_at DESC NULLS LAST.
+func newestFirst(a, b database.Post) int {
+	switch {
+	case a.PublishedAt.Valid && b.PublishedAt.Valid:
+		return b.PublishedAt.Time.Compare(a.PublishedAt.Time)
+	case a.PublishedAt.Valid:
+		return -1
+	case b.PublishedAt.Valid:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// postRow joins post with the name userID sees for its feed and its read and
+// saved state.
+func (d *data) postRow(userID uuid.UUID, post database.Post) (database.GetPostsForUserRow, error) {
+	feed, err := find(d.feeds, func(f database.Feed) bool { return f.ID == post.FeedID })
+	if err != nil {
+		return database.GetPostsForUserRow{}, err
+	}
+
+	return database.GetPostsForUserRow{
+		ID:          post.ID,
+		Title:       post.Title,
+		Url:         post.Url,
+		Description: post.Description,
+		PublishedAt: post.PublishedAt,
+		Author:      post.Author,
+		Categories:  post.Categories,
+		FeedID:      post.FeedID,
+		FeedName:    d.feedName(userID, *feed),
+		IsRead:      d.read(userID, post.ID),
+		IsSaved: slices.ContainsFunc(d.savedPosts, func(p database.SavedPost) bool {
+			return p.UserID == userID && p.PostID == post.ID
+		}),
+	}, nil
+}
+
+func (d *data) read(userID, postID uuid.UUID) bool {
+	return slices.ContainsFunc(d.readPosts, func(p database.ReadPost) bool {
+		return p.UserID == userID && p.PostID == postID
+	})
+}
+
+func (d *data) saved(postID uuid.UUID) bool {
+	return slices.ContainsFunc(d.savedPosts, func(p database.SavedPost) bool { return p.PostID == postID })
+}
+
+// deletePosts removes the matching posts and the saves and reads referencing them, and
+// returns the removed posts.
+func (d *data) deletePosts(match func(database.Post) bool) []database.Post {
+	var deleted []database.Post
+	d.posts = slices.DeleteFunc(d.posts, func(p database.Post) bool {
+		if match(p) {
+			deleted = append(deleted, p)
+			return true
+		}
+		return false
+	})
+
+	d.savedPosts = slices.DeleteFunc(d.savedPosts, func(saved database.SavedPost) bool {
+		return slices.ContainsFunc(deleted, func(p database.Post) bool { return p.ID == saved.PostID })
+	})
+	d.readPosts = slices.DeleteFunc(d.readPosts, func(read database.ReadPost) bool {
+		return slices.ContainsFunc(deleted, func(p database.Post) bool { return p.ID == read.PostID })
+	})
+	return deleted
+}
+
+// tombstone records pruned posts so CreatePosts skips them.
+func (d *data) tombstone(deleted []database.Post) {
+	for _, post := range deleted {
+		if !slices.ContainsFunc(d.pruned, func(p database.PrunedPost) bool { return p.Url == post.Url }) {
+			d.pruned = append(d.pruned, database.PrunedPost{Url: post.Url, FeedID: post.FeedID, PrunedAt: now()})
+		}
+	}
+}
+
+// removedByFeed counts deleted posts per feed, ordered by feed name like the
+// prune queries.
+func (d *data) removedByFeed(deleted []database.Post) []database.PrunePostsOlderThanRow {
+	var rows []database.PrunePostsOlderThanRow
+	for _, feed := range d.feeds {
+		removed := int64(0)
+		for _, post := range deleted {
+			if post.FeedID == feed.ID {
+				removed++
+			}
+		}
+		if removed > 0 {
+			rows = append(rows, database.PrunePostsOlderThanRow{
+				FeedID:  feed.ID,
+				Name:    feed.Name,
+				Url:     feed.Url,
+				Removed: removed,
+			})
+		}
+	}
+	slices.SortStableFunc(rows, func(a, b database.PrunePostsOlderThanRow) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.Url, b.Url))
+	})
+	return rows
+}
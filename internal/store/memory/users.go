@@ -0,0 +1,204 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/lmilojevicc/gator/internal/database"
+)
+
+func (s *Store) CreateUser(ctx context.Context, arg database.CreateUserParams) (database.User, error) {
+	defer s.lock()()
+
+	return s.data.createUser(database.CreateNonAdminUserParams(arg), len(s.data.users) == 0)
+}
+
+func (s *Store) CreateNonAdminUser(ctx context.Context, arg database.CreateNonAdminUserParams) (database.User, error) {
+	defer s.lock()()
+
+	return s.data.createUser(arg, false)
+}
+
+func (d *data) createUser(arg database.CreateNonAdminUserParams, isAdmin bool) (database.User, error) {
+	if slices.ContainsFunc(d.users, func(u database.User) bool { return u.Name == arg.Name }) {
+		return database.User{}, ErrDuplicate
+	}
+
+	user := database.User{
+		ID:           arg.ID,
+		CreatedAt:    now(),
+		UpdatedAt:    now(),
+		Name:         arg.Name,
+		PasswordHash: arg.PasswordHash,
+		IsAdmin:      isAdmin,
+	}
+	d.users = append(d.users, user)
+	return user, nil
+}
+
+func (s *Store) GetUserByName(ctx context.Context, name string) (database.User, error) {
+	defer s.lock()()
+
+	user, err := find(s.data.users, func(u database.User) bool { return u.Name == name })
+	if err != nil {
+		return database.User{}, err
+	}
+	return *user, nil
+}
+
+func (s *Store) GetUserByID(ctx context.Context, id uuid.UUID) (database.User, error) {
+	defer s.lock()()
+
+	user, err := find(s.data.users, func(u database.User) bool { return u.ID == id })
+	if err != nil {
+		return database.User{}, err
+	}
+	return *user, nil
+}
+
+// ResetUsers deletes every user and, through the cascades, everything else.
+func (s *Store) ResetUsers(ctx context.Context) error {
+	defer s.lock()()
+
+	s.data = data{}
+	return nil
+}
+
+func (s *Store) GetUsers(ctx context.Context) ([]database.User, error) {
+	defer s.lock()()
+
+	return slices.Clone(s.data.users), nil
+}
+
+func (s *Store) SetUserPassword(ctx context.Context, arg database.SetUserPasswordParams) error {
+	defer s.lock()()
+
+	user, err := find(s.data.users, func(u database.User) bool { return u.ID == arg.ID })
+	if err != nil {
+		// Like the UPDATE, a missing user is not an error.
+		return nil
+	}
+	user.PasswordHash = arg.PasswordHash
+	user.UpdatedAt = now()
+	return nil
+}
+
+func (s *Store) SetUserFeverKey(ctx context.Context, arg database.SetUserFeverKeyParams) error {
+	defer s.lock()()
+
+	if arg.FeverKeyHash.Valid && slices.ContainsFunc(s.data.users, func(u database.User) bool {
+		return u.ID != arg.ID && u.FeverKeyHash == arg.FeverKeyHash
+	}) {
+		return ErrDuplicate
+	}
+
+	user, err := find(s.data.users, func(u database.User) bool { return u.ID == arg.ID })
+	if err != nil {
+		return nil
+	}
+	user.FeverKeyHash = arg.FeverKeyHash
+	user.UpdatedAt = now()
+	return nil
+}
+
+func (s *Store) GetUserByFeverKey(ctx context.Context, feverKeyHash sql.NullString) (database.User, error) {
+	defer s.lock()()
+
+	user, err := find(s.data.users, func(u database.User) bool {
+		return u.FeverKeyHash.Valid && u.FeverKeyHash == feverKeyHash
+	})
+	if err != nil {
+		return database.User{}, err
+	}
+	return *user, nil
+}
+
+func (s *Store) SetUserAdmin(ctx context.Context, arg database.SetUserAdminParams) error {
+	defer s.lock()()
+
+	user, err := find(s.data.users, func(u database.User) bool { return u.ID == arg.ID })
+	if err != nil {
+		return nil
+	}
+	user.IsAdmin = arg.IsAdmin
+	user.UpdatedAt = now()
+	return nil
+}
+
+func (s *Store) CountAdmins(ctx context.Context) (int64, error) {
+	defer s.lock()()
+
+	var count int64
+	for _, user := range s.data.users {
+		if user.IsAdmin {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// DeleteUser removes the user along with everything that references it.
+func (s *Store) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	defer s.lock()()
+
+	s.data.users = slices.DeleteFunc(s.data.users, func(u database.User) bool { return u.ID == id })
+	s.data.sessions = slices.DeleteFunc(s.data.sessions, func(session database.Session) bool { return session.UserID == id })
+	s.data.follows = slices.DeleteFunc(s.data.follows, func(f database.FeedFollow) bool { return f.UserID == id })
+	s.data.savedPosts = slices.DeleteFunc(s.data.savedPosts, func(p database.SavedPost) bool { return p.UserID == id })
+	s.data.readPosts = slices.DeleteFunc(s.data.readPosts, func(p database.ReadPost) bool { return p.UserID == id })
+	s.data.deleteRules(func(r database.NotificationRule) bool { return r.UserID == id })
+	s.data.filters = slices.DeleteFunc(s.data.filters, func(r database.FilterRule) bool { return r.UserID == id })
+	s.data.deleteCategories(func(c database.Category) bool { return c.UserID == id })
+	s.data.deleteFeeds(func(f database.Feed) bool { return f.UserID == id })
+	return nil
+}
+
+func (s *Store) CreateSession(ctx context.Context, arg database.CreateSessionParams) error {
+	defer s.lock()()
+
+	if slices.ContainsFunc(s.data.sessions, func(session database.Session) bool { return session.TokenHash == arg.TokenHash }) {
+		return ErrDuplicate
+	}
+
+	s.data.sessions = append(s.data.sessions, database.Session{
+		TokenHash: arg.TokenHash,
+		UserID:    arg.UserID,
+		CreatedAt: now(),
+	})
+	return nil
+}
+
+func (s *Store) GetUserBySession(ctx context.Context, tokenHash string) (database.User, error) {
+	defer s.lock()()
+
+	session, err := find(s.data.sessions, func(session database.Session) bool { return session.TokenHash == tokenHash })
+	if err != nil {
+		return database.User{}, err
+	}
+	user, err := find(s.data.users, func(u database.User) bool { return u.ID == session.UserID })
+	if err != nil {
+		return database.User{}, err
+	}
+	return *user, nil
+}
+
+func (s *Store) DeleteSession(ctx context.Context, tokenHash string) error {
+	defer s.lock()()
+
+	s.data.sessions = slices.DeleteFunc(s.data.sessions, func(session database.Session) bool {
+		return session.TokenHash == tokenHash
+	})
+	return nil
+}
+
+func (s *Store) DeleteOtherSessions(ctx context.Context, arg database.DeleteOtherSessionsParams) error {
+	defer s.lock()()
+
+	s.data.sessions = slices.DeleteFunc(s.data.sessions, func(session database.Session) bool {
+		return session.UserID == arg.UserID && session.TokenHash != arg.TokenHash
+	})
+	return nil
+}
@@ -0,0 +1,96 @@
+// Package store is the storage layer handlers talk to. It hides whether the
+// data lives in PostgreSQL, SQLite or, in tests, in memory.
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	_ "github.com/lib/pq"
+
+	"github.com/lmilojevicc/gator/internal/config"
+	"github.com/lmilojevicc/gator/internal/database"
+	"github.com/lmilojevicc/gator/internal/database/sqlite"
+)
+
+// Store covers users, feeds, follows and posts. The row and parameter types
+// are the ones sqlc generates for PostgreSQL in the database package.
+type Store interface {
+	database.Querier
+
+	// InTx runs fn against a Store bound to a single transaction, which is
+	// committed if fn returns nil and rolled back otherwise. Calling InTx on
+	// the Store passed to fn runs in the same transaction.
+	InTx(ctx context.Context, fn func(Store) error) error
+}
+
+// Open connects to the database selected by driver, one of the config.Driver
+// constants. The *sql.DB is returned as well for running migrations.
+func Open(driver, source string) (Store, *sql.DB, error) {
+	switch driver {
+	case config.DriverSQLite:
+		db, err := sqlite.Open(source)
+		if err != nil {
+			return nil, nil, err
+		}
+		return NewSQLite(db), db, nil
+	default:
+		db, err := sql.Open("postgres", source)
+		if err != nil {
+			return nil, nil, fmt.Errorf("opening postgres database: %w", err)
+		}
+		return NewPostgres(db), db, nil
+	}
+}
+
+func NewPostgres(db *sql.DB) Store {
+	return &sqlStore{
+		Querier: database.New(db),
+		db:      db,
+		withTx: func(tx *sql.Tx) database.Querier {
+			return database.New(tx)
+		},
+	}
+}
+
+func NewSQLite(db *sql.DB) Store {
+	return &sqlStore{
+		Querier: sqlite.NewStore(db),
+		db:      db,
+		withTx: func(tx *sql.Tx) database.Querier {
+			return sqlite.NewStore(tx)
+		},
+	}
+}
+
+// sqlStore is a Store backed by database/sql, shared by both SQL backends.
+type sqlStore struct {
+	database.Querier
+	db     *sql.DB
+	withTx func(tx *sql.Tx) database.Querier
+}
+
+func (s *sqlStore) InTx(ctx context.Context, fn func(Store) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(txStore{s.withTx(tx)}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// txStore is handed to InTx callbacks; its queries already run inside the
+// transaction.
+type txStore struct {
+	database.Querier
+}
+
+func (s txStore) InTx(ctx context.Context, fn func(Store) error) error {
+	return fn(s)
+}
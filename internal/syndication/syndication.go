@@ -0,0 +1,226 @@
+// Package syndication writes posts back out as Atom or RSS 2.0 feeds, so that
+// gator's merged stream can be read by other feed readers.
+package syndication
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/lmilojevicc/gator/internal/database"
+	"github.com/lmilojevicc/gator/internal/version"
+)
+
+type Feed struct {
+	// ID identifies the feed permanently, e.g. urn:uuid:<user id>.
+	ID          string
+	Title       string
+	Description string
+	// Link is the HTML page of the feed and SelfLink the feed document itself;
+	// both are optional.
+	Link     string
+	SelfLink string
+	// Updated defaults to the newest entry.
+	Updated time.Time
+	Entries []Entry
+}
+
+type Entry struct {
+	Title   string
+	Link    string
+	Summary string
+	// Published is zero when the original feed had no usable date.
+	Published time.Time
+}
+
+// Timeline is the feed of everything user follows, built from the rows
+// GetPostsByUser returns.
+func Timeline(user database.User, posts []database.GetPostsByUserRow) Feed {
+	feed := Feed{
+		ID:          "urn:uuid:" + user.ID.String(),
+		Title:       fmt.Sprintf("%s's gator timeline", user.Name),
+		Description: fmt.Sprintf("Posts from the feeds %s follows", user.Name),
+	}
+	for _, post := range posts {
+		entry := Entry{
+			Title:   post.Title.String,
+			Link:    post.Url,
+			Summary: post.Description.String,
+		}
+		if post.PublishedAt.Valid {
+			entry.Published = post.PublishedAt.Time
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	return feed
+}
+
+// updated returns f.Updated, or the newest entry date, or now.
+func (f Feed) updated() time.Time {
+	if !f.Updated.IsZero() {
+		return f.Updated
+	}
+
+	var newest time.Time
+	for _, entry := range f.Entries {
+		if entry.Published.After(newest) {
+			newest = entry.Published
+		}
+	}
+	if newest.IsZero() {
+		return time.Now().UTC()
+	}
+	return newest
+}
+
+type atomFeed struct {
+	XMLName   xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Subtitle  string      `xml:"subtitle,omitempty"`
+	Updated   string      `xml:"updated"`
+	Links     []atomLink  `xml:"link"`
+	Author    atomPerson  `xml:"author"`
+	Generator atomText    `xml:"generator"`
+	Entries   []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomText struct {
+	Version string `xml:"version,attr,omitempty"`
+	Type    string `xml:"type,attr,omitempty"`
+	Body    string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	ID        string    `xml:"id"`
+	Title     string    `xml:"title"`
+	Link      atomLink  `xml:"link"`
+	Updated   string    `xml:"updated"`
+	Published string    `xml:"published,omitempty"`
+	Summary   *atomText `xml:"summary"`
+}
+
+// WriteAtom writes f as an Atom 1.0 document. Entries are identified by their
+// link, which is unique per post.
+func WriteAtom(w io.Writer, f Feed) error {
+	updated := f.updated()
+
+	doc := atomFeed{
+		ID:        f.ID,
+		Title:     f.Title,
+		Subtitle:  f.Description,
+		Updated:   updated.Format(time.RFC3339),
+		Author:    atomPerson{Name: "gator"},
+		Generator: atomText{Version: version.Version, Body: "gator"},
+	}
+	if f.Link != "" {
+		doc.Links = append(doc.Links, atomLink{Rel: "alternate", Href: f.Link})
+	}
+	if f.SelfLink != "" {
+		doc.Links = append(doc.Links, atomLink{Rel: "self", Href: f.SelfLink})
+	}
+
+	for _, entry := range f.Entries {
+		e := atomEntry{
+			ID:      entry.Link,
+			Title:   entry.Title,
+			Link:    atomLink{Href: entry.Link},
+			Updated: updated.Format(time.RFC3339),
+		}
+		if !entry.Published.IsZero() {
+			e.Updated = entry.Published.Format(time.RFC3339)
+			e.Published = e.Updated
+		}
+		if entry.Summary != "" {
+			e.Summary = &atomText{Type: "html", Body: entry.Summary}
+		}
+		doc.Entries = append(doc.Entries, e)
+	}
+
+	return encode(w, doc)
+}
+
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Generator     string    `xml:"generator"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title,omitempty"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	Description string  `xml:"description,omitempty"`
+	PubDate     string  `xml:"pubDate,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// WriteRSS writes f as an RSS 2.0 document.
+func WriteRSS(w io.Writer, f Feed) error {
+	doc := rssDocument{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:         f.Title,
+			Link:          f.Link,
+			Description:   f.Description,
+			LastBuildDate: f.updated().Format(time.RFC1123Z),
+			Generator:     "gator " + version.Version,
+		},
+	}
+	if doc.Channel.Link == "" {
+		doc.Channel.Link = f.SelfLink
+	}
+
+	for _, entry := range f.Entries {
+		item := rssItem{
+			Title:       entry.Title,
+			Link:        entry.Link,
+			GUID:        rssGUID{IsPermaLink: true, Value: entry.Link},
+			Description: entry.Summary,
+		}
+		if !entry.Published.IsZero() {
+			item.PubDate = entry.Published.Format(time.RFC1123Z)
+		}
+		doc.Channel.Items = append(doc.Channel.Items, item)
+	}
+
+	return encode(w, doc)
+}
+
+func encode(w io.Writer, doc any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("encoding feed: %w", err)
+	}
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return err
+	}
+	return nil
+}
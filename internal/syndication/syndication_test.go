@@ -0,0 +1,134 @@
+package syndication
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lmilojevicc/gator/internal/config"
+	"github.com/lmilojevicc/gator/internal/database"
+	"github.com/lmilojevicc/gator/internal/rss"
+)
+
+var (
+	alice = database.User{ID: uuid.MustParse("7d444840-9dc0-11d1-b245-5ffdce74fad2"), Name: "alice"}
+
+	published = time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	posts = []database.GetPostsByUserRow{
+		{
+			Title:       sql.NullString{String: "Fish & chips", Valid: true},
+			Url:         "https://example.com/fish?a=1&b=2",
+			Description: sql.NullString{String: "<p>Tasty</p>", Valid: true},
+			PublishedAt: sql.NullTime{Time: published, Valid: true},
+		},
+		{Url: "https://example.com/undated"},
+	}
+)
+
+func TestWriteAtom(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteAtom(&buf, Timeline(alice, posts)); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc struct {
+		ID      string `xml:"id"`
+		Title   string `xml:"title"`
+		Updated string `xml:"updated"`
+		Entries []struct {
+			ID        string `xml:"id"`
+			Title     string `xml:"title"`
+			Updated   string `xml:"updated"`
+			Published string `xml:"published"`
+			Link      struct {
+				Href string `xml:"href,attr"`
+			} `xml:"link"`
+			Summary struct {
+				Type string `xml:"type,attr"`
+				Body string `xml:",chardata"`
+			} `xml:"summary"`
+		} `xml:"entry"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("output is not valid XML: %v\n%s", err, buf.String())
+	}
+
+	if doc.ID != "urn:uuid:"+alice.ID.String() || doc.Title != "alice's gator timeline" {
+		t.Errorf("feed id/title = %q/%q", doc.ID, doc.Title)
+	}
+	// The feed is as new as its newest entry.
+	if doc.Updated != "2024-05-01T10:00:00Z" {
+		t.Errorf("updated = %q", doc.Updated)
+	}
+	if len(doc.Entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(doc.Entries))
+	}
+
+	first := doc.Entries[0]
+	if first.ID != posts[0].Url || first.Link.Href != posts[0].Url || first.Title != "Fish & chips" {
+		t.Errorf("first entry = %+v", first)
+	}
+	if first.Published != "2024-05-01T10:00:00Z" || first.Summary.Type != "html" || first.Summary.Body != "<p>Tasty</p>" {
+		t.Errorf("first entry = %+v", first)
+	}
+
+	// Atom requires updated on every entry, even without a date.
+	if undated := doc.Entries[1]; undated.Updated != doc.Updated || undated.Published != "" {
+		t.Errorf("undated entry = %+v", undated)
+	}
+}
+
+// TestWriteRSSRoundTrip reads the RSS output back with gator's own parser.
+func TestWriteRSSRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	feed := Timeline(alice, posts)
+	feed.Link = "https://gator.example.com/"
+	if err := WriteRSS(&buf, feed); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write(buf.Bytes())
+	}))
+	defer srv.Close()
+
+	var items []rss.RSSItem
+	f := rss.NewFetcherWithClient(srv.Client(), config.FetchConfig{})
+	result, err := f.FetchFeed(context.Background(), srv.URL, nil, func(item rss.RSSItem) error {
+		items = append(items, item)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("%v\n%s", err, buf.String())
+	}
+
+	if result.Channel.Title != "alice's gator timeline" || result.Channel.Link != feed.Link {
+		t.Errorf("channel = %+v", result.Channel)
+	}
+	if len(items) != 2 {
+		t.Fatalf("got %d items, want 2", len(items))
+	}
+
+	want := rss.RSSItem{
+		Title:       "Fish & chips",
+		Link:        posts[0].Url,
+		Description: "<p>Tasty</p>",
+		PubDate:     "Wed, 01 May 2024 10:00:00 +0000",
+	}
+	if !reflect.DeepEqual(items[0], want) {
+		t.Errorf("item = %+v, want %+v", items[0], want)
+	}
+	if items[1].PubDate != "" {
+		t.Errorf("undated item has pubDate %q", items[1].PubDate)
+	}
+}
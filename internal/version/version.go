@@ -0,0 +1,5 @@
+package version
+
+// Version is the gator release, overridden at build time with
+// -ldflags "-X github.com/lmilojevicc/gator/internal/version.Version=v1.2.3".
+var Version = "dev"
@@ -0,0 +1,97 @@
+package web
+
+import (
+	"database/sql"
+	"net/http"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/lmilojevicc/gator/internal/database"
+)
+
+type followsPage struct {
+	Follows []database.GetFeedFollowsForUserRow
+	// Others are the feeds the user does not follow yet.
+	Others []database.Feed
+}
+
+func (srv *Server) handleFollows(w http.ResponseWriter, r *http.Request, user database.User) {
+	srv.renderFollows(w, r, user, http.StatusOK, "")
+}
+
+func (srv *Server) renderFollows(w http.ResponseWriter, r *http.Request, user database.User, status int, msg string) {
+	follows, err := srv.store.GetFeedFollowsForUser(r.Context(), user.ID)
+	if err != nil {
+		serverError(w, "getting follows", err)
+		return
+	}
+
+	feeds, err := srv.store.GetAllFeeds(r.Context())
+	if err != nil {
+		serverError(w, "getting feeds", err)
+		return
+	}
+	others := slices.DeleteFunc(feeds, func(feed database.Feed) bool {
+		return slices.ContainsFunc(follows, func(f database.GetFeedFollowsForUserRow) bool { return f.FeedID == feed.ID })
+	})
+
+	srv.render(w, status, "follows.html", page{
+		Title: "Follows",
+		User:  &user,
+		Error: msg,
+		Data:  followsPage{Follows: follows, Others: others},
+	})
+}
+
+func (srv *Server) handleFollow(w http.ResponseWriter, r *http.Request, user database.User) {
+	feedURL := r.PostFormValue("feed_url")
+
+	feed, err := srv.store.GetFeedByURL(r.Context(), feedURL)
+	if err == sql.ErrNoRows {
+		srv.renderFollows(w, r, user, http.StatusNotFound, "No feed with URL "+feedURL)
+		return
+	}
+	if err != nil {
+		serverError(w, "getting feed by url", err)
+		return
+	}
+
+	follows, err := srv.store.GetFeedFollowsForUser(r.Context(), user.ID)
+	if err != nil {
+		serverError(w, "getting follows", err)
+		return
+	}
+	if !slices.ContainsFunc(follows, func(f database.GetFeedFollowsForUserRow) bool { return f.FeedID == feed.ID }) {
+		_, err = srv.store.CreateFeedFollow(r.Context(), database.CreateFeedFollowParams{
+			ID:     uuid.New(),
+			UserID: user.ID,
+			FeedID: feed.ID,
+		})
+		if err != nil {
+			serverError(w, "creating follow", err)
+			return
+		}
+	}
+
+	http.Redirect(w, r, "/follows", http.StatusSeeOther)
+}
+
+func (srv *Server) handleUnfollow(w http.ResponseWriter, r *http.Request, user database.User) {
+	feedID, err := uuid.Parse(r.PathValue("feedID"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	_, err = srv.store.Unfollow(r.Context(), database.UnfollowParams{
+		FeedID: feedID,
+		UserID: user.ID,
+	})
+	if err != nil && err != sql.ErrNoRows {
+		serverError(w, "unfollowing feed", err)
+		return
+	}
+
+	http.Redirect(w, r, "/follows", http.StatusSeeOther)
+}
@@ -0,0 +1,192 @@
+package web
+
+import (
+	"database/sql"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/lmilojevicc/gator/internal/database"
+	"github.com/lmilojevicc/gator/internal/filter"
+)
+
+const pageSize = 25
+
+// listing is the state of the post list that survives paging.
+type listing struct {
+	FeedID string
+	Unread bool
+}
+
+// Page returns the URL of the given page of the list.
+func (f listing) Page(n int) string {
+	query := url.Values{}
+	if f.FeedID != "" {
+		query.Set("feed", f.FeedID)
+	}
+	if f.Unread {
+		query.Set("unread", "1")
+	}
+	if n > 1 {
+		query.Set("page", strconv.Itoa(n))
+	}
+	if len(query) == 0 {
+		return "/"
+	}
+	return "/?" + query.Encode()
+}
+
+func (f listing) WithFeed(feedID string) string {
+	f.FeedID = feedID
+	return f.Page(1)
+}
+
+func (f listing) WithUnread(unread bool) string {
+	f.Unread = unread
+	return f.Page(1)
+}
+
+type postsPage struct {
+	Filter  listing
+	Page    int
+	More    bool
+	Posts   []database.GetPostsForUserRow
+	Follows []database.GetFeedFollowsForUserRow
+}
+
+func (srv *Server) handlePosts(w http.ResponseWriter, r *http.Request, user database.User) {
+	query := r.URL.Query()
+
+	data := postsPage{Page: 1, Filter: listing{Unread: query.Get("unread") == "1"}}
+	// Pages past the int32 offset range are treated like invalid ones.
+	if n, err := strconv.Atoi(query.Get("page")); err == nil && n > 1 && n <= math.MaxInt32/pageSize {
+		data.Page = n
+	}
+
+	params := database.GetPostsForUserParams{
+		UserID:     user.ID,
+		UnreadOnly: data.Filter.Unread,
+	}
+	if feedID, err := uuid.Parse(query.Get("feed")); err == nil {
+		params.FeedID = uuid.NullUUID{UUID: feedID, Valid: true}
+		data.Filter.FeedID = feedID.String()
+	}
+
+	f, err := filter.Load(r.Context(), srv.store, user.ID)
+	if err != nil {
+		serverError(w, "getting filter rules", err)
+		return
+	}
+
+	// One more than shown, to know whether there is an older page.
+	posts, err := filter.Page(f, (data.Page-1)*pageSize, pageSize+1, filter.FromPostsForUser, func(offset, limit int) ([]database.GetPostsForUserRow, error) {
+		params.PageOffset, params.PageSize = int32(offset), int32(limit)
+		return srv.store.GetPostsForUser(r.Context(), params)
+	})
+	if err != nil {
+		serverError(w, "getting posts", err)
+		return
+	}
+	if len(posts) > pageSize {
+		posts, data.More = posts[:pageSize], true
+	}
+	data.Posts = posts
+
+	data.Follows, err = srv.store.GetFeedFollowsForUser(r.Context(), user.ID)
+	if err != nil {
+		serverError(w, "getting follows", err)
+		return
+	}
+
+	title := "All posts"
+	for _, follow := range data.Follows {
+		if follow.FeedID.String() == data.Filter.FeedID {
+			title = follow.FeedName
+		}
+	}
+
+	srv.render(w, http.StatusOK, "posts.html", page{Title: title, User: &user, Data: data})
+}
+
+// handlePost shows a single post and marks it read.
+func (srv *Server) handlePost(w http.ResponseWriter, r *http.Request, user database.User) {
+	postID, err := uuid.Parse(r.PathValue("postID"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	post, err := srv.store.GetPostForUser(r.Context(), database.GetPostForUserParams{
+		ID:     postID,
+		UserID: user.ID,
+	})
+	if err == sql.ErrNoRows {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		serverError(w, "getting post", err)
+		return
+	}
+
+	err = srv.store.MarkPostRead(r.Context(), database.MarkPostReadParams{
+		UserID: user.ID,
+		PostID: post.ID,
+	})
+	if err != nil {
+		serverError(w, "marking post read", err)
+		return
+	}
+
+	title := post.Title.String
+	if title == "" {
+		title = "(untitled)"
+	}
+	srv.render(w, http.StatusOK, "post.html", page{Title: title, User: &user, Data: post})
+}
+
+// handlePostAction marks a post read or unread, or saves or unsaves it, then
+// returns to the page named by the back form field.
+func (srv *Server) handlePostAction(w http.ResponseWriter, r *http.Request, user database.User) {
+	postID, err := uuid.Parse(r.PathValue("postID"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	_, err = srv.store.GetPostForUser(r.Context(), database.GetPostForUserParams{
+		ID:     postID,
+		UserID: user.ID,
+	})
+	if err == sql.ErrNoRows {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		serverError(w, "getting post", err)
+		return
+	}
+
+	switch r.PathValue("action") {
+	case "read":
+		err = srv.store.MarkPostRead(r.Context(), database.MarkPostReadParams{UserID: user.ID, PostID: postID})
+	case "unread":
+		err = srv.store.MarkPostUnread(r.Context(), database.MarkPostUnreadParams{UserID: user.ID, PostID: postID})
+	case "save":
+		err = srv.store.SavePost(r.Context(), database.SavePostParams{UserID: user.ID, PostID: postID})
+	case "unsave":
+		_, err = srv.store.UnsavePost(r.Context(), database.UnsavePostParams{UserID: user.ID, PostID: postID})
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		serverError(w, "updating post", err)
+		return
+	}
+
+	http.Redirect(w, r, localPath(r.PostFormValue("back"), "/"), http.StatusSeeOther)
+}
@@ -0,0 +1,49 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/lmilojevicc/gator/internal/auth"
+	"github.com/lmilojevicc/gator/internal/database"
+)
+
+func (srv *Server) handleLoginPage(w http.ResponseWriter, r *http.Request) {
+	srv.render(w, http.StatusOK, "login.html", page{Title: "Log in"})
+}
+
+func (srv *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	name := r.PostFormValue("name")
+
+	user, err := auth.Authenticate(r.Context(), srv.store, name, r.PostFormValue("password"))
+	if errors.Is(err, auth.ErrWrongPassword) || errors.Is(err, auth.ErrNoPassword) {
+		srv.render(w, http.StatusUnauthorized, "login.html", page{Title: "Log in", Error: err.Error(), Data: name})
+		return
+	}
+	if err != nil {
+		serverError(w, "logging in", err)
+		return
+	}
+
+	token, err := auth.StartSession(r.Context(), srv.store, user.ID)
+	if err != nil {
+		serverError(w, "logging in", err)
+		return
+	}
+
+	setSessionCookie(w, r, token)
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+func (srv *Server) handleLogout(w http.ResponseWriter, r *http.Request, user database.User) {
+	cookie, err := r.Cookie(sessionCookie)
+	if err == nil {
+		if err := srv.store.DeleteSession(r.Context(), auth.HashToken(cookie.Value)); err != nil {
+			serverError(w, "deleting session", err)
+			return
+		}
+	}
+
+	clearSessionCookie(w)
+	http.Redirect(w, r, "/login", http.StatusSeeOther)
+}
@@ -0,0 +1,185 @@
+// Package web is a small server-rendered reader on top of the same store the
+// CLI uses. It logs users in with the same sessions, kept in a cookie.
+package web
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"html/template"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+
+	"github.com/lmilojevicc/gator/internal/auth"
+	"github.com/lmilojevicc/gator/internal/database"
+	"github.com/lmilojevicc/gator/internal/store"
+)
+
+const sessionCookie = "gator_session"
+
+//go:embed templates
+var templatesFS embed.FS
+
+type Server struct {
+	store     store.Store
+	templates map[string]*template.Template
+}
+
+func New(st store.Store) *Server {
+	srv := &Server{store: st, templates: map[string]*template.Template{}}
+	for _, name := range []string{"login.html", "posts.html", "post.html", "follows.html"} {
+		srv.templates[name] = template.Must(template.New(name).Funcs(funcs).ParseFS(
+			templatesFS, "templates/layout.html", "templates/"+name,
+		))
+	}
+	return srv
+}
+
+type authenticatedHandler = func(w http.ResponseWriter, r *http.Request, user database.User)
+
+// Handler returns the routes of the reader. Forms are protected against
+// cross-site submission by http.CrossOriginProtection.
+func (srv *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /login", srv.handleLoginPage)
+	mux.HandleFunc("POST /login", srv.handleLogin)
+	mux.HandleFunc("POST /logout", srv.authenticated(srv.handleLogout))
+
+	mux.HandleFunc("GET /{$}", srv.authenticated(srv.handlePosts))
+	mux.HandleFunc("GET /posts/{postID}", srv.authenticated(srv.handlePost))
+	mux.HandleFunc("POST /posts/{postID}/{action}", srv.authenticated(srv.handlePostAction))
+
+	mux.HandleFunc("GET /follows", srv.authenticated(srv.handleFollows))
+	mux.HandleFunc("POST /follows", srv.authenticated(srv.handleFollow))
+	mux.HandleFunc("POST /follows/{feedID}/unfollow", srv.authenticated(srv.handleUnfollow))
+
+	return http.NewCrossOriginProtection().Handler(mux)
+}
+
+// authenticated resolves the session cookie to a user and sends everyone else
+// to the login page.
+func (srv *Server) authenticated(handler authenticatedHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie(sessionCookie)
+		if err != nil {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		user, err := srv.store.GetUserBySession(r.Context(), auth.HashToken(cookie.Value))
+		if err != nil {
+			if !errors.Is(err, context.Canceled) {
+				clearSessionCookie(w)
+			}
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+
+		handler(w, r, user)
+	}
+}
+
+func setSessionCookie(w http.ResponseWriter, r *http.Request, token string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    token,
+		Path:     "/",
+		MaxAge:   int((30 * 24 * time.Hour).Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func clearSessionCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+	})
+}
+
+// page is what the layout template renders; Data is specific to each page.
+type page struct {
+	Title string
+	User  *database.User
+	Error string
+	Data  any
+}
+
+func (srv *Server) render(w http.ResponseWriter, status int, name string, p page) {
+	var buf bytes.Buffer
+	if err := srv.templates[name].ExecuteTemplate(&buf, "layout", p); err != nil {
+		serverError(w, "rendering "+name, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	buf.WriteTo(w)
+}
+
+// serverError logs err and answers 500 without leaking the details.
+func serverError(w http.ResponseWriter, doing string, err error) {
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	log.Printf("web: %s: %v", doing, err)
+	http.Error(w, "internal server error", http.StatusInternalServerError)
+}
+
+// localPath returns path if it points into this site, and fallback otherwise,
+// so that redirects taken from forms cannot leave it.
+func localPath(path, fallback string) string {
+	if !strings.HasPrefix(path, "/") || strings.HasPrefix(path, "//") || strings.HasPrefix(path, "/\\") {
+		return fallback
+	}
+	return path
+}
+
+var funcs = template.FuncMap{
+	"add": func(a, b int) int { return a + b },
+	"sub": func(a, b int) int { return a - b },
+	"date": func(t time.Time) string {
+		return t.Format("2006-01-02 15:04")
+	},
+	"postTitle": func(title string) string {
+		if title == "" {
+			return "(untitled)"
+		}
+		return title
+	},
+	"plainText": plainText,
+}
+
+// plainText strips the markup from a feed description. Descriptions come from
+// arbitrary feeds, so they are never rendered as HTML.
+func plainText(s string) string {
+	var b strings.Builder
+	skip := false
+	z := html.NewTokenizer(strings.NewReader(s))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return strings.Join(strings.Fields(b.String()), " ")
+		case html.TextToken:
+			if !skip {
+				b.Write(z.Text())
+			}
+		case html.StartTagToken:
+			name, _ := z.TagName()
+			skip = string(name) == "script" || string(name) == "style"
+			b.WriteByte(' ')
+		case html.EndTagToken, html.SelfClosingTagToken:
+			skip = false
+			b.WriteByte(' ')
+		}
+	}
+}
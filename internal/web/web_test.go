@@ -0,0 +1,304 @@
+package web
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lmilojevicc/gator/internal/auth"
+	"github.com/lmilojevicc/gator/internal/database"
+	"github.com/lmilojevicc/gator/internal/store/memory"
+)
+
+type browser struct {
+	t      *testing.T
+	srv    *httptest.Server
+	client *http.Client
+}
+
+// newBrowser serves the reader from a fresh store and returns a client that
+// keeps cookies but does not follow redirects.
+func newBrowser(t *testing.T) (*browser, *memory.Store) {
+	t.Helper()
+
+	st := memory.New()
+	srv := httptest.NewServer(New(st).Handler())
+	t.Cleanup(srv.Close)
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := srv.Client()
+	client.Jar = jar
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	return &browser{t: t, srv: srv, client: client}, st
+}
+
+func (b *browser) get(path string) (int, string) {
+	b.t.Helper()
+
+	resp, err := b.client.Get(b.srv.URL + path)
+	if err != nil {
+		b.t.Fatal(err)
+	}
+	return b.read(resp)
+}
+
+// post submits a form and returns the status and the redirect location.
+func (b *browser) post(path string, form url.Values) (int, string) {
+	b.t.Helper()
+
+	resp, err := b.client.PostForm(b.srv.URL+path, form)
+	if err != nil {
+		b.t.Fatal(err)
+	}
+	b.read(resp)
+	return resp.StatusCode, resp.Header.Get("Location")
+}
+
+func (b *browser) read(resp *http.Response) (int, string) {
+	b.t.Helper()
+
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		b.t.Fatal(err)
+	}
+	return resp.StatusCode, string(body)
+}
+
+func createUser(t *testing.T, st *memory.Store, name, password string) database.User {
+	t.Helper()
+
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, err := st.CreateUser(context.Background(), database.CreateUserParams{
+		ID:           uuid.New(),
+		Name:         name,
+		PasswordHash: sql.NullString{String: hash, Valid: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+// createFeed adds a feed followed by user with posts titled titles, newest
+// last.
+func createFeed(t *testing.T, st *memory.Store, user database.User, name string, titles ...string) (database.Feed, []uuid.UUID) {
+	t.Helper()
+
+	ctx := context.Background()
+	feed, err := st.CreateFeed(ctx, database.CreateFeedParams{
+		ID:     uuid.New(),
+		Name:   name,
+		Url:    "https://example.com/" + name,
+		UserID: user.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = st.CreateFeedFollow(ctx, database.CreateFeedFollowParams{ID: uuid.New(), UserID: user.ID, FeedID: feed.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	params := database.CreatePostsParams{FeedID: feed.ID}
+	for i, title := range titles {
+		params.Ids = append(params.Ids, uuid.New())
+		params.Titles = append(params.Titles, title)
+		params.Urls = append(params.Urls, feed.Url+"/"+uuid.NewString())
+		params.Descriptions = append(params.Descriptions, "<p>About <b>"+title+"</b></p>")
+		params.PublishedAts = append(params.PublishedAts, time.Date(2024, 5, 1, i, 0, 0, 0, time.UTC))
+		params.Authors = append(params.Authors, "")
+		params.Categories = append(params.Categories, "")
+	}
+	if _, err := st.CreatePosts(ctx, params); err != nil {
+		t.Fatal(err)
+	}
+	return feed, params.Ids
+}
+
+func (b *browser) login(name, password string) {
+	b.t.Helper()
+
+	status, location := b.post("/login", url.Values{"name": {name}, "password": {password}})
+	if status != http.StatusSeeOther || location != "/" {
+		b.t.Fatalf("login: status %d, location %q", status, location)
+	}
+}
+
+func TestLogin(t *testing.T) {
+	b, st := newBrowser(t)
+	createUser(t, st, "alice", "hunter2")
+
+	resp, err := b.client.Get(b.srv.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusSeeOther || resp.Header.Get("Location") != "/login" {
+		t.Fatalf("logged out: status %d, want a redirect to /login", resp.StatusCode)
+	}
+
+	if code, _ := b.post("/login", url.Values{"name": {"alice"}, "password": {"wrong"}}); code != http.StatusUnauthorized {
+		t.Errorf("wrong password: status %d, want 401", code)
+	}
+
+	b.login("alice", "hunter2")
+	if code, body := b.get("/"); code != http.StatusOK || !strings.Contains(body, "alice") {
+		t.Fatalf("after login: status %d", code)
+	}
+
+	if code, location := b.post("/logout", nil); code != http.StatusSeeOther || location != "/login" {
+		t.Fatalf("logout: status %d, location %q", code, location)
+	}
+	if code, _ := b.get("/"); code != http.StatusSeeOther {
+		t.Errorf("after logout: status %d, want a redirect", code)
+	}
+}
+
+func TestPosts(t *testing.T) {
+	b, st := newBrowser(t)
+	alice := createUser(t, st, "alice", "hunter2")
+	blog, posts := createFeed(t, st, alice, "blog", "First", "Second")
+	createFeed(t, st, alice, "news", "Headline")
+	b.login("alice", "hunter2")
+
+	_, body := b.get("/")
+	for _, title := range []string{"First", "Second", "Headline"} {
+		if !strings.Contains(body, title) {
+			t.Errorf("post list is missing %q", title)
+		}
+	}
+
+	_, body = b.get("/?feed=" + blog.ID.String())
+	if strings.Contains(body, "Headline") || !strings.Contains(body, "Second") {
+		t.Errorf("feed filter did not apply:\n%s", body)
+	}
+
+	// Opening a post shows its description as text and marks it read.
+	code, body := b.get("/posts/" + posts[1].String())
+	if code != http.StatusOK || !strings.Contains(body, "About Second") {
+		t.Fatalf("post page: status %d\n%s", code, body)
+	}
+	_, body = b.get("/?unread=1")
+	if strings.Contains(body, "Second") || !strings.Contains(body, "First") {
+		t.Errorf("unread filter still shows the read post:\n%s", body)
+	}
+
+	code, location := b.post("/posts/"+posts[1].String()+"/unread", url.Values{"back": {"/?unread=1"}})
+	if code != http.StatusSeeOther || location != "/?unread=1" {
+		t.Fatalf("mark unread: status %d, location %q", code, location)
+	}
+	_, body = b.get("/?unread=1")
+	if !strings.Contains(body, "Second") {
+		t.Error("post is still read after marking it unread")
+	}
+
+	b.post("/posts/"+posts[0].String()+"/save", nil)
+	rows, err := st.GetPostsForUser(context.Background(), database.GetPostsForUserParams{
+		UserID:   alice.ID,
+		FeedID:   uuid.NullUUID{UUID: blog.ID, Valid: true},
+		PageSize: 10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || !rows[1].IsSaved {
+		t.Errorf("rows = %+v, want the first post saved", rows)
+	}
+
+	// Redirects never leave the site.
+	_, location = b.post("/posts/"+posts[0].String()+"/read", url.Values{"back": {"//evil.example.com"}})
+	if location != "/" {
+		t.Errorf("location = %q, want /", location)
+	}
+}
+
+func TestPostOfOtherUser(t *testing.T) {
+	b, st := newBrowser(t)
+	alice := createUser(t, st, "alice", "hunter2")
+	createUser(t, st, "bob", "hunter2")
+	_, posts := createFeed(t, st, alice, "blog", "Private")
+	b.login("bob", "hunter2")
+
+	if code, _ := b.get("/posts/" + posts[0].String()); code != http.StatusNotFound {
+		t.Errorf("status %d, want 404 for a post from a feed bob does not follow", code)
+	}
+}
+
+func TestFollows(t *testing.T) {
+	b, st := newBrowser(t)
+	alice := createUser(t, st, "alice", "hunter2")
+	createUser(t, st, "bob", "hunter2")
+	blog, _ := createFeed(t, st, alice, "blog")
+	b.login("bob", "hunter2")
+
+	_, body := b.get("/follows")
+	if !strings.Contains(body, "not following any feed") || !strings.Contains(body, blog.Url) {
+		t.Fatalf("follows page does not offer the blog:\n%s", body)
+	}
+
+	if code, _ := b.post("/follows", url.Values{"feed_url": {blog.Url}}); code != http.StatusSeeOther {
+		t.Fatalf("follow: status %d", code)
+	}
+	if code, _ := b.post("/follows", url.Values{"feed_url": {"https://missing.example.com"}}); code != http.StatusNotFound {
+		t.Errorf("follow missing feed: status %d, want 404", code)
+	}
+
+	_, body = b.get("/follows")
+	if !strings.Contains(body, "/follows/"+blog.ID.String()+"/unfollow") {
+		t.Fatalf("blog is not listed as followed:\n%s", body)
+	}
+
+	b.post("/follows/"+blog.ID.String()+"/unfollow", nil)
+	_, body = b.get("/follows")
+	if !strings.Contains(body, "not following any feed") {
+		t.Error("blog is still followed after unfollowing")
+	}
+}
+
+func TestCrossOriginFormsAreRejected(t *testing.T) {
+	b, st := newBrowser(t)
+	alice := createUser(t, st, "alice", "hunter2")
+	blog, _ := createFeed(t, st, alice, "blog")
+	b.login("alice", "hunter2")
+
+	req, err := http.NewRequest("POST", b.srv.URL+"/follows/"+blog.ID.String()+"/unfollow", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Sec-Fetch-Site", "cross-site")
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("status %d, want 403", resp.StatusCode)
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	got := plainText(`<p>Fish &amp; chips<br/>with <script>x</script><a href="#">peas</a></p>`)
+	if want := "Fish & chips with peas"; got != want {
+		t.Errorf("plainText = %q, want %q", got, want)
+	}
+}
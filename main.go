@@ -1,18 +1,19 @@
 package main
 
 import (
-	"database/sql"
+	"context"
 	"log"
 	"os"
 
-	_ "github.com/lib/pq"
-
 	"github.com/lmilojevicc/gator/internal/cli"
 	"github.com/lmilojevicc/gator/internal/config"
-	"github.com/lmilojevicc/gator/internal/database"
 	"github.com/lmilojevicc/gator/internal/handlers"
 	"github.com/lmilojevicc/gator/internal/middleware"
+	"github.com/lmilojevicc/gator/internal/migrations"
+	"github.com/lmilojevicc/gator/internal/notify"
+	"github.com/lmilojevicc/gator/internal/rss"
 	"github.com/lmilojevicc/gator/internal/state"
+	"github.com/lmilojevicc/gator/internal/store"
 )
 
 func main() {
@@ -21,34 +22,73 @@ func main() {
 		log.Fatalf("Failed to read config: %v\n", err)
 	}
 
-	db, err := sql.Open("postgres", cfg.DBURL)
+	driver, source := cfg.Database()
+
+	dbStore, db, err := store.Open(driver, source)
 	if err != nil {
 		log.Fatalf("failed connecting to db: %v", err)
 	}
 
-	dbQueries := database.New(db)
+	fetcher, err := rss.NewFetcher(cfg.Fetch)
+	if err != nil {
+		log.Fatalf("failed configuring feed fetcher: %v", err)
+	}
+
+	notifier := notify.New(dbStore)
+	notifier.AllowedNetworks, err = notify.ParseNetworks(cfg.AllowedWebhookNetworks)
+	if err != nil {
+		log.Fatalf("failed configuring webhooks: %v", err)
+	}
 
 	programState := state.State{
-		Cfg:     &cfg,
-		Queries: dbQueries,
-		Conn:    db,
+		Cfg:      &cfg,
+		Store:    dbStore,
+		Conn:     db,
+		Fetcher:  fetcher,
+		Notifier: notifier,
 	}
 
 	cmds := cli.Commands{
 		RegisteredCommands: make(map[string]func(*state.State, cli.Command) error),
 	}
 
+	cmds.Register("migrate", handlers.HandlerMigrate)
 	cmds.Register("login", handlers.HandlerLogin)
 	cmds.Register("register", handlers.HandlerRegister)
-	cmds.Register("reset", handlers.HandlerReset)
+	cmds.Register("reset", middleware.Admin(handlers.HandlerReset))
 	cmds.Register("users", handlers.HandlerUsers)
+	cmds.Register("passwd", middleware.LoggedIn(handlers.HandlerPasswd))
+	cmds.Register("promote", middleware.Admin(handlers.HandlerPromote))
+	cmds.Register("demote", middleware.Admin(handlers.HandlerDemote))
+	cmds.Register("deleteuser", middleware.Admin(handlers.HandlerDeleteUser))
 	cmds.Register("agg", handlers.HandlerAggregate)
+	cmds.Register("bandwidth", handlers.HandlerBandwidth)
 	cmds.Register("addfeed", middleware.LoggedIn(handlers.HandlerAddFeed))
 	cmds.Register("feeds", handlers.HandlerFeeds)
+	cmds.Register("renamefeed", middleware.LoggedIn(handlers.HandlerRenameFeed))
+	cmds.Register("setfeedurl", middleware.LoggedIn(handlers.HandlerSetFeedURL))
+	cmds.Register("deletefeed", middleware.LoggedIn(handlers.HandlerDeleteFeed))
+	cmds.Register("feedhistory", handlers.HandlerFeedHistory)
+	cmds.Register("feedauth", middleware.LoggedIn(handlers.HandlerFeedAuth))
+	cmds.Register("clearfeedauth", middleware.LoggedIn(handlers.HandlerClearFeedAuth))
 	cmds.Register("follow", middleware.LoggedIn(handlers.HandlerFollow))
 	cmds.Register("following", middleware.LoggedIn(handlers.HandlerFollowing))
 	cmds.Register("unfollow", middleware.LoggedIn(handlers.HandlerUnfollow))
+	cmds.Register("category", middleware.LoggedIn(handlers.HandlerCategory))
+	cmds.Register("setfeedtitle", middleware.LoggedIn(handlers.HandlerSetFeedTitle))
+	cmds.Register("clearfeedtitle", middleware.LoggedIn(handlers.HandlerClearFeedTitle))
 	cmds.Register("browse", middleware.LoggedIn(handlers.HandlerBrowse))
+	cmds.Register("timeline", middleware.LoggedIn(handlers.HandlerTimeline))
+	cmds.Register("fever", middleware.LoggedIn(handlers.HandlerFever))
+	cmds.Register("notify", middleware.LoggedIn(handlers.HandlerNotify))
+	cmds.Register("filter", middleware.LoggedIn(handlers.HandlerFilter))
+	cmds.Register("digest", handlers.HandlerDigest)
+	cmds.Register("setdigest", middleware.LoggedIn(handlers.HandlerSetDigest))
+	cmds.Register("cleardigest", middleware.LoggedIn(handlers.HandlerClearDigest))
+	cmds.Register("save", middleware.LoggedIn(handlers.HandlerSave))
+	cmds.Register("unsave", middleware.LoggedIn(handlers.HandlerUnsave))
+	cmds.Register("prune", middleware.Admin(handlers.HandlerPrune))
+	cmds.Register("serve", handlers.HandlerServe)
 
 	if len(os.Args) < 2 {
 		log.Fatalf("Usage: cli <command> [args...]")
@@ -57,6 +97,12 @@ func main() {
 	cmdName := os.Args[1]
 	cmdArgs := os.Args[2:]
 
+	if cmdName != "migrate" {
+		if err := migrations.CheckCurrent(context.Background(), db, driver); err != nil {
+			log.Fatal(err)
+		}
+	}
+
 	err = cmds.Run(&programState, cli.Command{Name: cmdName, Arguments: cmdArgs})
 	if err != nil {
 		log.Fatal(err)
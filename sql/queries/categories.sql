@@ -0,0 +1,34 @@
+-- name: CreateCategory :one
+INSERT INTO categories (id, user_id, name, created_at)
+VALUES ($1, $2, $3, now())
+RETURNING *;
+
+-- name: GetCategoriesForUser :many
+SELECT
+    categories.*,
+    (
+        SELECT count(*) FROM feed_follows
+        WHERE feed_follows.category_id = categories.id
+    ) AS feed_count
+FROM categories
+WHERE categories.user_id = $1
+ORDER BY categories.name;
+
+-- name: GetCategoryByName :one
+SELECT * FROM categories
+WHERE user_id = $1 AND name = $2;
+
+-- name: RenameCategory :execrows
+UPDATE categories
+SET name = $3
+WHERE id = $1 AND user_id = $2;
+
+-- name: DeleteCategory :execrows
+DELETE FROM categories
+WHERE id = $1 AND user_id = $2;
+
+-- name: SetFollowCategory :execrows
+-- category_id NULL takes the feed out of its category.
+UPDATE feed_follows
+SET category_id = sqlc.narg(category_id), updated_at = now()
+WHERE user_id = sqlc.arg(user_id) AND feed_id = sqlc.arg(feed_id);
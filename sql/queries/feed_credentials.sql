@@ -0,0 +1,23 @@
+-- name: SetFeedCredential :exec
+INSERT INTO feed_credentials (
+    id, created_at, updated_at, feed_id, kind, header_name, username, secret_ref
+)
+VALUES ($1, now(), now(), $2, $3, $4, $5, $6)
+ON CONFLICT (feed_id, kind, header_name) DO UPDATE
+SET
+    username = excluded.username,
+    secret_ref = excluded.secret_ref,
+    updated_at = now();
+
+-- name: GetFeedCredentials :many
+SELECT * FROM feed_credentials
+WHERE feed_id = $1
+ORDER BY kind, header_name;
+
+-- name: DeleteFeedAuthorization :exec
+DELETE FROM feed_credentials
+WHERE feed_id = $1 AND kind IN ('basic', 'bearer');
+
+-- name: ClearFeedCredentials :exec
+DELETE FROM feed_credentials
+WHERE feed_id = $1;
@@ -0,0 +1,18 @@
+-- name: CreateFeedFetch :exec
+INSERT INTO feed_fetches (
+    id, fetched_at, feed_id, content_encoding, compressed_bytes, uncompressed_bytes
+)
+VALUES ($1, now(), $2, $3, $4, $5);
+
+-- name: GetFeedBandwidth :many
+SELECT
+    feeds.name,
+    feeds.url,
+    count(*) AS fetches,
+    sum(feed_fetches.compressed_bytes)::BIGINT AS compressed_bytes,
+    sum(feed_fetches.uncompressed_bytes)::BIGINT AS uncompressed_bytes
+FROM feed_fetches
+INNER JOIN feeds ON feed_fetches.feed_id = feeds.id
+WHERE feed_fetches.fetched_at >= $1
+GROUP BY feeds.id
+ORDER BY compressed_bytes DESC;
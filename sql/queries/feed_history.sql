@@ -0,0 +1,8 @@
+-- name: CreateFeedHistory :exec
+INSERT INTO feed_history (id, created_at, feed_id, event, detail)
+VALUES ($1, now(), $2, $3, $4);
+
+-- name: GetFeedHistory :many
+SELECT * FROM feed_history
+WHERE feed_id = $1
+ORDER BY created_at DESC;
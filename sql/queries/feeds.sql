@@ -18,5 +18,48 @@ WHERE id = $1;
 -- name: GetNextFeedToFetch :one
 SELECT *
 FROM feeds
+WHERE dead_at IS NULL
 ORDER BY last_fetched_at ASC NULLS FIRST
 LIMIT 1;
+
+-- name: RenameFeed :one
+UPDATE feeds
+SET name = $2, updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateFeedURL :one
+UPDATE feeds
+SET
+    url = $2,
+    redirect_url = NULL,
+    redirect_count = 0,
+    dead_at = NULL,
+    updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteFeed :exec
+DELETE FROM feeds
+WHERE id = $1;
+
+-- name: RecordFeedRedirect :one
+UPDATE feeds
+SET
+    redirect_count = CASE
+        WHEN redirect_url = $2 THEN redirect_count + 1
+        ELSE 1
+    END,
+    redirect_url = $2
+WHERE id = $1
+RETURNING redirect_count;
+
+-- name: ClearFeedRedirect :exec
+UPDATE feeds
+SET redirect_url = NULL, redirect_count = 0
+WHERE id = $1;
+
+-- name: MarkFeedDead :exec
+UPDATE feeds
+SET updated_at = now(), dead_at = now()
+WHERE id = $1;
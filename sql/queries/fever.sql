@@ -0,0 +1,100 @@
+-- Queries for the Fever API, which identifies feeds and posts by their seq.
+
+-- name: GetFeverFeeds :many
+SELECT feeds.seq, COALESCE(feed_follows.title, feeds.name) AS name, feeds.url, feeds.last_fetched_at, categories.seq AS category_seq
+FROM feeds
+INNER JOIN feed_follows ON feeds.id = feed_follows.feed_id
+LEFT JOIN categories ON feed_follows.category_id = categories.id
+WHERE feed_follows.user_id = $1
+ORDER BY feeds.seq;
+
+-- name: GetFeverItemsAfter :many
+-- The oldest items after after_seq, for clients syncing with since_id.
+SELECT
+    posts.id,
+    posts.seq,
+    feeds.seq AS feed_seq,
+    posts.title,
+    posts.url,
+    posts.description,
+    posts.published_at,
+    posts.created_at,
+    posts.feed_id,
+    posts.author,
+    posts.categories,
+    EXISTS (
+        SELECT 1 FROM read_posts
+        WHERE read_posts.post_id = posts.id AND read_posts.user_id = feed_follows.user_id
+    ) AS is_read,
+    EXISTS (
+        SELECT 1 FROM saved_posts
+        WHERE saved_posts.post_id = posts.id AND saved_posts.user_id = feed_follows.user_id
+    ) AS is_saved
+FROM posts
+INNER JOIN feeds ON posts.feed_id = feeds.id
+INNER JOIN feed_follows ON posts.feed_id = feed_follows.feed_id
+WHERE feed_follows.user_id = sqlc.arg(user_id) AND posts.seq > sqlc.arg(after_seq)::BIGINT
+ORDER BY posts.seq
+LIMIT sqlc.arg(max_items)::INTEGER;
+
+-- name: GetFeverItemsBefore :many
+-- The newest items before before_seq, for clients paging back with max_id.
+SELECT
+    posts.id,
+    posts.seq,
+    feeds.seq AS feed_seq,
+    posts.title,
+    posts.url,
+    posts.description,
+    posts.published_at,
+    posts.created_at,
+    posts.feed_id,
+    posts.author,
+    posts.categories,
+    EXISTS (
+        SELECT 1 FROM read_posts
+        WHERE read_posts.post_id = posts.id AND read_posts.user_id = feed_follows.user_id
+    ) AS is_read,
+    EXISTS (
+        SELECT 1 FROM saved_posts
+        WHERE saved_posts.post_id = posts.id AND saved_posts.user_id = feed_follows.user_id
+    ) AS is_saved
+FROM posts
+INNER JOIN feeds ON posts.feed_id = feeds.id
+INNER JOIN feed_follows ON posts.feed_id = feed_follows.feed_id
+WHERE feed_follows.user_id = sqlc.arg(user_id) AND posts.seq < sqlc.arg(before_seq)::BIGINT
+ORDER BY posts.seq DESC
+LIMIT sqlc.arg(max_items)::INTEGER;
+
+-- name: GetFeverItemStates :many
+SELECT
+    posts.seq,
+    posts.feed_id,
+    posts.title,
+    posts.description,
+    posts.author,
+    posts.categories,
+    EXISTS (
+        SELECT 1 FROM read_posts
+        WHERE read_posts.post_id = posts.id AND read_posts.user_id = feed_follows.user_id
+    ) AS is_read,
+    EXISTS (
+        SELECT 1 FROM saved_posts
+        WHERE saved_posts.post_id = posts.id AND saved_posts.user_id = feed_follows.user_id
+    ) AS is_saved
+FROM posts
+INNER JOIN feed_follows ON posts.feed_id = feed_follows.feed_id
+WHERE feed_follows.user_id = $1
+ORDER BY posts.seq;
+
+-- name: MarkFeverPostsRead :exec
+INSERT INTO read_posts (user_id, post_id, read_at)
+SELECT feed_follows.user_id, posts.id, now()
+FROM posts
+INNER JOIN feeds ON posts.feed_id = feeds.id
+INNER JOIN feed_follows ON posts.feed_id = feed_follows.feed_id
+WHERE
+    feed_follows.user_id = sqlc.arg(user_id)
+    AND (sqlc.narg(feed_seq)::BIGINT IS NULL OR feeds.seq = sqlc.narg(feed_seq)::BIGINT)
+    AND posts.created_at <= sqlc.arg(added_before)
+ON CONFLICT (user_id, post_id) DO NOTHING;
@@ -0,0 +1,15 @@
+-- name: CreateFilterRule :one
+INSERT INTO filter_rules (id, user_id, feed_id, action, kind, pattern, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, now())
+RETURNING *;
+
+-- name: GetFilterRulesForUser :many
+SELECT filter_rules.*, feeds.name AS feed_name
+FROM filter_rules
+LEFT JOIN feeds ON filter_rules.feed_id = feeds.id
+WHERE filter_rules.user_id = $1
+ORDER BY filter_rules.created_at;
+
+-- name: DeleteFilterRule :execrows
+DELETE FROM filter_rules
+WHERE id = $1 AND user_id = $2;
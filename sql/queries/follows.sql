@@ -16,14 +16,27 @@ INNER JOIN users ON inserted_feed_follow.user_id = users.id;
 -- name: GetFeedFollowsForUser :many
 SELECT
     feed_follows.*,
-    feeds.name AS feed_name,
-    users.name AS user_name
+    COALESCE(feed_follows.title, feeds.name) AS feed_name,
+    users.name AS user_name,
+    categories.name AS category_name
 FROM feed_follows
 INNER JOIN feeds ON feed_follows.feed_id = feeds.id
 INNER JOIN users ON feed_follows.user_id = users.id
+LEFT JOIN categories ON feed_follows.category_id = categories.id
 WHERE feed_follows.user_id = $1;
 
 -- name: Unfollow :one
 DELETE FROM feed_follows
 WHERE user_id = $1 AND feed_id = $2
 RETURNING *;
+
+-- name: CountOtherFeedFollowers :one
+SELECT count(*)
+FROM feed_follows
+WHERE feed_id = $1 AND user_id <> $2;
+
+-- name: SetFollowTitle :execrows
+-- title NULL shows the feed under its own name again.
+UPDATE feed_follows
+SET title = sqlc.narg(title), updated_at = now()
+WHERE user_id = sqlc.arg(user_id) AND feed_id = sqlc.arg(feed_id);
@@ -0,0 +1,44 @@
+-- name: CreateNotificationRule :one
+INSERT INTO notification_rules (id, user_id, feed_id, keyword, webhook_url, format, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, now())
+RETURNING *;
+
+-- name: GetNotificationRulesForUser :many
+SELECT notification_rules.*, feeds.name AS feed_name
+FROM notification_rules
+LEFT JOIN feeds ON notification_rules.feed_id = feeds.id
+WHERE notification_rules.user_id = $1
+ORDER BY notification_rules.created_at;
+
+-- name: GetNotificationRulesForFeed :many
+-- The rules of the feed's followers that cover it.
+SELECT notification_rules.*
+FROM notification_rules
+INNER JOIN feed_follows
+    ON notification_rules.user_id = feed_follows.user_id
+WHERE
+    feed_follows.feed_id = sqlc.arg(feed_id)
+    AND (notification_rules.feed_id IS NULL OR notification_rules.feed_id = sqlc.arg(feed_id))
+ORDER BY notification_rules.created_at;
+
+-- name: DeleteNotificationRule :execrows
+DELETE FROM notification_rules
+WHERE id = $1 AND user_id = $2;
+
+-- name: CreateNotificationDelivery :exec
+INSERT INTO notification_deliveries (
+    id, rule_id, feed_id, post_count, attempts, status_code, error, delivered_at, created_at
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, now());
+
+-- name: GetNotificationDeliveries :many
+SELECT
+    notification_deliveries.*,
+    notification_rules.webhook_url,
+    feeds.name AS feed_name
+FROM notification_deliveries
+INNER JOIN notification_rules ON notification_deliveries.rule_id = notification_rules.id
+INNER JOIN feeds ON notification_deliveries.feed_id = feeds.id
+WHERE notification_rules.user_id = $1
+ORDER BY notification_deliveries.created_at DESC
+LIMIT $2;
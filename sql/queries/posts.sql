@@ -1,13 +1,38 @@
--- name: CreatePost :one
+-- name: CreatePosts :many
 INSERT INTO posts (
-    id, created_at, updated_at, title, url, description, published_at, feed_id
+    id, created_at, updated_at, title, url, description, published_at, feed_id,
+    author, categories
+)
+SELECT
+    new_posts.id,
+    now(),
+    now(),
+    new_posts.title,
+    new_posts.url,
+    new_posts.description,
+    new_posts.published_at,
+    sqlc.arg(feed_id),
+    new_posts.author,
+    new_posts.categories
+FROM (
+    SELECT
+        unnest(sqlc.arg(ids)::UUID []) AS id,
+        nullif(unnest(sqlc.arg(titles)::TEXT []), '') AS title,
+        unnest(sqlc.arg(urls)::TEXT []) AS url,
+        nullif(unnest(sqlc.arg(descriptions)::TEXT []), '') AS description,
+        unnest(sqlc.arg(published_ats)::TIMESTAMP []) AS published_at,
+        nullif(unnest(sqlc.arg(authors)::TEXT []), '') AS author,
+        nullif(unnest(sqlc.arg(categories)::TEXT []), '') AS categories
+) AS new_posts
+WHERE NOT EXISTS (
+    SELECT 1 FROM pruned_posts
+    WHERE pruned_posts.url = new_posts.url
 )
-VALUES ($1, now(), now(), $2, $3, $4, $5, $6)
 ON CONFLICT (url) DO NOTHING
-RETURNING *;
+RETURNING id;
 
 -- name: GetPostsByUser :many
-SELECT posts.title, posts.url, posts.description, posts.published_at
+SELECT posts.title, posts.url, posts.description, posts.published_at, posts.feed_id, posts.author, posts.categories
 FROM posts
 INNER JOIN feeds
     ON feeds.id = posts.feed_id
@@ -17,4 +42,173 @@ INNER JOIN feed_follows
     ON feed_follows.feed_id = posts.feed_id
 WHERE feed_follows.user_id = $1
 ORDER BY published_at DESC NULLS LAST
-LIMIT $2;
+LIMIT $2 OFFSET $3;
+
+-- name: GetPostByURL :one
+SELECT * FROM posts
+WHERE url = $1;
+
+-- name: SavePost :exec
+INSERT INTO saved_posts (user_id, post_id, created_at)
+VALUES ($1, $2, now())
+ON CONFLICT (user_id, post_id) DO NOTHING;
+
+-- name: UnsavePost :execrows
+DELETE FROM saved_posts
+WHERE user_id = $1 AND post_id = $2;
+
+-- name: PrunePostsOlderThan :many
+WITH deleted AS (
+    DELETE FROM posts
+    WHERE
+        coalesce(posts.published_at, posts.created_at) < sqlc.arg(cutoff)::TIMESTAMP
+        AND NOT EXISTS (
+            SELECT 1 FROM saved_posts
+            WHERE saved_posts.post_id = posts.id
+        )
+    RETURNING posts.feed_id, posts.url
+),
+
+tombstones AS (
+    INSERT INTO pruned_posts (url, feed_id, pruned_at)
+    SELECT deleted.url, deleted.feed_id, now()
+    FROM deleted
+    ON CONFLICT (url) DO NOTHING
+)
+
+SELECT
+    feeds.id AS feed_id,
+    feeds.name,
+    feeds.url,
+    count(*) AS removed
+FROM deleted
+INNER JOIN feeds ON deleted.feed_id = feeds.id
+GROUP BY feeds.id
+ORDER BY feeds.name, feeds.url;
+
+-- name: PrunePostsBeyondNewest :many
+WITH deleted AS (
+    DELETE FROM posts
+    WHERE
+        posts.id IN (
+            SELECT ranked.id
+            FROM (
+                SELECT
+                    feed_posts.id,
+                    row_number() OVER (
+                        PARTITION BY feed_posts.feed_id
+                        ORDER BY
+                            feed_posts.published_at DESC NULLS LAST,
+                            feed_posts.created_at DESC
+                    ) AS position
+                FROM posts AS feed_posts
+            ) AS ranked
+            WHERE ranked.position > sqlc.arg(keep)::BIGINT
+        )
+        AND NOT EXISTS (
+            SELECT 1 FROM saved_posts
+            WHERE saved_posts.post_id = posts.id
+        )
+    RETURNING posts.feed_id, posts.url
+),
+
+tombstones AS (
+    INSERT INTO pruned_posts (url, feed_id, pruned_at)
+    SELECT deleted.url, deleted.feed_id, now()
+    FROM deleted
+    ON CONFLICT (url) DO NOTHING
+)
+
+SELECT
+    feeds.id AS feed_id,
+    feeds.name,
+    feeds.url,
+    count(*) AS removed
+FROM deleted
+INNER JOIN feeds ON deleted.feed_id = feeds.id
+GROUP BY feeds.id
+ORDER BY feeds.name, feeds.url;
+
+-- name: MarkPrunedPostsSeen :exec
+UPDATE pruned_posts SET seen_at = now()
+WHERE feed_id = sqlc.arg(feed_id) AND url = ANY(sqlc.arg(urls)::TEXT []);
+
+-- name: ExpirePrunedPosts :execrows
+DELETE FROM pruned_posts
+WHERE coalesce(seen_at, pruned_at) < (
+    SELECT max(feed_fetches.fetched_at) FROM feed_fetches
+    WHERE feed_fetches.feed_id = pruned_posts.feed_id
+);
+
+-- name: GetPostByID :one
+SELECT * FROM posts
+WHERE id = $1;
+
+-- name: GetPostForUser :one
+SELECT
+    posts.id,
+    posts.title,
+    posts.url,
+    posts.description,
+    posts.published_at,
+    posts.author,
+    posts.categories,
+    posts.feed_id,
+    COALESCE(feed_follows.title, feeds.name) AS feed_name,
+    EXISTS (
+        SELECT 1 FROM read_posts
+        WHERE read_posts.post_id = posts.id AND read_posts.user_id = feed_follows.user_id
+    ) AS is_read,
+    EXISTS (
+        SELECT 1 FROM saved_posts
+        WHERE saved_posts.post_id = posts.id AND saved_posts.user_id = feed_follows.user_id
+    ) AS is_saved
+FROM posts
+INNER JOIN feeds ON posts.feed_id = feeds.id
+INNER JOIN feed_follows ON posts.feed_id = feed_follows.feed_id
+WHERE posts.id = $1 AND feed_follows.user_id = $2;
+
+-- name: GetPostsForUser :many
+SELECT
+    posts.id,
+    posts.title,
+    posts.url,
+    posts.description,
+    posts.published_at,
+    posts.author,
+    posts.categories,
+    posts.feed_id,
+    COALESCE(feed_follows.title, feeds.name) AS feed_name,
+    EXISTS (
+        SELECT 1 FROM read_posts
+        WHERE read_posts.post_id = posts.id AND read_posts.user_id = feed_follows.user_id
+    ) AS is_read,
+    EXISTS (
+        SELECT 1 FROM saved_posts
+        WHERE saved_posts.post_id = posts.id AND saved_posts.user_id = feed_follows.user_id
+    ) AS is_saved
+FROM posts
+INNER JOIN feeds ON posts.feed_id = feeds.id
+INNER JOIN feed_follows ON posts.feed_id = feed_follows.feed_id
+WHERE
+    feed_follows.user_id = sqlc.arg(user_id)
+    AND (
+        NOT sqlc.arg(unread_only)::BOOLEAN
+        OR NOT EXISTS (
+            SELECT 1 FROM read_posts
+            WHERE read_posts.post_id = posts.id AND read_posts.user_id = feed_follows.user_id
+        )
+    )
+    AND (sqlc.narg(feed_id)::UUID IS NULL OR posts.feed_id = sqlc.narg(feed_id)::UUID)
+    AND (sqlc.narg(category_id)::UUID IS NULL OR feed_follows.category_id = sqlc.narg(category_id)::UUID)
+ORDER BY posts.published_at DESC NULLS LAST, posts.id
+LIMIT sqlc.arg(page_size)::INTEGER OFFSET sqlc.arg(page_offset)::INTEGER;
+
+-- name: MarkPostRead :exec
+INSERT INTO read_posts (user_id, post_id, read_at)
+VALUES ($1, $2, now())
+ON CONFLICT (user_id, post_id) DO NOTHING;
+
+-- name: MarkPostUnread :exec
+DELETE FROM read_posts
+WHERE user_id = $1 AND post_id = $2;
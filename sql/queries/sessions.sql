@@ -0,0 +1,17 @@
+-- name: CreateSession :exec
+INSERT INTO sessions (token_hash, user_id, created_at)
+VALUES ($1, $2, now());
+
+-- name: GetUserBySession :one
+SELECT users.*
+FROM sessions
+INNER JOIN users ON sessions.user_id = users.id
+WHERE sessions.token_hash = $1;
+
+-- name: DeleteSession :exec
+DELETE FROM sessions
+WHERE token_hash = $1;
+
+-- name: DeleteOtherSessions :exec
+DELETE FROM sessions
+WHERE user_id = $1 AND token_hash <> $2;
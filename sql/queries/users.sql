@@ -1,6 +1,11 @@
 -- name: CreateUser :one 
-INSERT INTO users (id, created_at, updated_at, name)
-VALUES ($1, now(), now(), $2)
+INSERT INTO users (id, created_at, updated_at, name, password_hash, is_admin)
+VALUES ($1, now(), now(), $2, $3, NOT EXISTS (SELECT 1 FROM users))
+RETURNING *;
+
+-- name: CreateNonAdminUser :one
+INSERT INTO users (id, created_at, updated_at, name, password_hash, is_admin)
+VALUES ($1, now(), now(), $2, $3, false)
 RETURNING *;
 
 -- name: GetUserByName :one
@@ -16,3 +21,30 @@ DELETE FROM users;
 
 -- name: GetUsers :many
 SELECT * FROM users;
+
+-- name: SetUserPassword :exec
+UPDATE users
+SET password_hash = $2, updated_at = now()
+WHERE id = $1;
+
+-- name: SetUserAdmin :exec
+UPDATE users
+SET is_admin = $2, updated_at = now()
+WHERE id = $1;
+
+-- name: CountAdmins :one
+SELECT count(*) FROM users
+WHERE is_admin;
+
+-- name: DeleteUser :exec
+DELETE FROM users
+WHERE id = $1;
+
+-- name: SetUserFeverKey :exec
+UPDATE users
+SET fever_key_hash = $2, updated_at = now()
+WHERE id = $1;
+
+-- name: GetUserByFeverKey :one
+SELECT * FROM users
+WHERE fever_key_hash = $1;
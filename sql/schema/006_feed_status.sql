@@ -0,0 +1,18 @@
+-- +goose Up
+ALTER TABLE feeds ADD COLUMN redirect_url TEXT DEFAULT NULL;
+ALTER TABLE feeds ADD COLUMN redirect_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE feeds ADD COLUMN dead_at TIMESTAMP DEFAULT NULL;
+
+CREATE TABLE feed_history (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    feed_id UUID NOT NULL REFERENCES feeds (id) ON DELETE CASCADE,
+    event TEXT NOT NULL,
+    detail TEXT NOT NULL
+);
+
+-- +goose Down
+DROP TABLE feed_history;
+ALTER TABLE feeds DROP COLUMN dead_at;
+ALTER TABLE feeds DROP COLUMN redirect_count;
+ALTER TABLE feeds DROP COLUMN redirect_url;
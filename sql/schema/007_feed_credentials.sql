@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE feed_credentials (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    feed_id UUID NOT NULL REFERENCES feeds (id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    header_name TEXT NOT NULL DEFAULT '',
+    username TEXT,
+    secret_ref TEXT NOT NULL,
+    CONSTRAINT unique_feed_credential UNIQUE (feed_id, kind, header_name)
+);
+
+-- +goose Down
+DROP TABLE feed_credentials;
@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE feed_fetches (
+    id UUID PRIMARY KEY,
+    fetched_at TIMESTAMP NOT NULL,
+    feed_id UUID NOT NULL REFERENCES feeds (id) ON DELETE CASCADE,
+    content_encoding TEXT NOT NULL,
+    compressed_bytes BIGINT NOT NULL,
+    uncompressed_bytes BIGINT NOT NULL
+);
+
+CREATE INDEX feed_fetches_fetched_at_idx ON feed_fetches (fetched_at);
+
+-- +goose Down
+DROP TABLE feed_fetches;
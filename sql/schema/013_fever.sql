@@ -0,0 +1,13 @@
+-- +goose Up
+-- Fever clients identify feeds and items by integers that grow with every
+-- insert; existing rows are numbered when the columns are added.
+ALTER TABLE feeds ADD COLUMN seq BIGINT NOT NULL GENERATED BY DEFAULT AS IDENTITY UNIQUE;
+ALTER TABLE posts ADD COLUMN seq BIGINT NOT NULL GENERATED BY DEFAULT AS IDENTITY UNIQUE;
+
+-- sha256 of the Fever api_key, md5("name:password"); NULL while disabled.
+ALTER TABLE users ADD COLUMN fever_key_hash TEXT UNIQUE;
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN fever_key_hash;
+ALTER TABLE posts DROP COLUMN seq;
+ALTER TABLE feeds DROP COLUMN seq;
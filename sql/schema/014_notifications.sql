@@ -0,0 +1,29 @@
+-- +goose Up
+-- A rule POSTs the new posts of a feed to a webhook. feed_id NULL covers
+-- every feed the user follows, keyword NULL every post.
+CREATE TABLE notification_rules (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    feed_id UUID REFERENCES feeds (id) ON DELETE CASCADE,
+    keyword TEXT,
+    webhook_url TEXT NOT NULL,
+    format TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+-- One row per webhook call, i.e. per rule and fetch, after all retries.
+CREATE TABLE notification_deliveries (
+    id UUID PRIMARY KEY,
+    rule_id UUID NOT NULL REFERENCES notification_rules (id) ON DELETE CASCADE,
+    feed_id UUID NOT NULL REFERENCES feeds (id) ON DELETE CASCADE,
+    post_count INTEGER NOT NULL,
+    attempts INTEGER NOT NULL,
+    status_code INTEGER,
+    error TEXT,
+    delivered_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL
+);
+
+-- +goose Down
+DROP TABLE notification_deliveries;
+DROP TABLE notification_rules;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- Users with an email and a digest frequency are mailed the posts added since
+-- last_digest_at by the digest command.
+ALTER TABLE users ADD COLUMN email TEXT;
+ALTER TABLE users ADD COLUMN digest_frequency TEXT CHECK (digest_frequency IN ('daily', 'weekly'));
+ALTER TABLE users ADD COLUMN last_digest_at TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN last_digest_at;
+ALTER TABLE users DROP COLUMN digest_frequency;
+ALTER TABLE users DROP COLUMN email;
@@ -0,0 +1,18 @@
+-- +goose Up
+-- Categories are folders a user sorts the feeds they follow into. Fever
+-- clients identify them, as groups, by seq.
+CREATE TABLE categories (
+    id UUID PRIMARY KEY,
+    seq BIGINT NOT NULL GENERATED BY DEFAULT AS IDENTITY UNIQUE,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    CONSTRAINT unique_user_category UNIQUE (user_id, name)
+);
+
+-- A follow is in at most one category; deleting the category keeps the follow.
+ALTER TABLE feed_follows ADD COLUMN category_id UUID REFERENCES categories (id) ON DELETE SET NULL;
+
+-- +goose Down
+ALTER TABLE feed_follows DROP COLUMN category_id;
+DROP TABLE categories;
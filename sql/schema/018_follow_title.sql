@@ -0,0 +1,6 @@
+-- +goose Up
+-- A follower's own name for a feed, shown to them instead of feeds.name.
+ALTER TABLE feed_follows ADD COLUMN title TEXT;
+
+-- +goose Down
+ALTER TABLE feed_follows DROP COLUMN title;
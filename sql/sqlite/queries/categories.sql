@@ -0,0 +1,36 @@
+-- name: CreateCategory :one
+INSERT INTO categories (id, seq, user_id, name, created_at)
+VALUES (
+    ?, (SELECT COALESCE(MAX(seq), 0) + 1 FROM categories), ?, ?, CURRENT_TIMESTAMP
+)
+RETURNING *;
+
+-- name: GetCategoriesForUser :many
+SELECT
+    categories.*,
+    (
+        SELECT count(*) FROM feed_follows
+        WHERE feed_follows.category_id = categories.id
+    ) AS feed_count
+FROM categories
+WHERE categories.user_id = ?
+ORDER BY categories.name;
+
+-- name: GetCategoryByName :one
+SELECT * FROM categories
+WHERE user_id = ? AND name = ?;
+
+-- name: RenameCategory :execrows
+UPDATE categories
+SET name = sqlc.arg(name)
+WHERE id = sqlc.arg(id) AND user_id = sqlc.arg(user_id);
+
+-- name: DeleteCategory :execrows
+DELETE FROM categories
+WHERE id = ? AND user_id = ?;
+
+-- name: SetFollowCategory :execrows
+-- category_id NULL takes the feed out of its category.
+UPDATE feed_follows
+SET category_id = sqlc.narg(category_id), updated_at = CURRENT_TIMESTAMP
+WHERE user_id = sqlc.arg(user_id) AND feed_id = sqlc.arg(feed_id);
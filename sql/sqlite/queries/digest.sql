@@ -0,0 +1,38 @@
+-- name: SetUserDigest :exec
+UPDATE users
+SET email = ?, digest_frequency = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: GetDigestSubscribers :many
+SELECT * FROM users
+WHERE email IS NOT NULL AND digest_frequency IS NOT NULL
+ORDER BY name;
+
+-- name: GetDigestPosts :many
+-- The posts added to the user's follows in (added_after, added_before] after
+-- the post with seq after_seq, in the order they were added.
+SELECT
+    posts.seq,
+    posts.feed_id,
+    COALESCE(feed_follows.title, feeds.name) AS feed_name,
+    posts.title,
+    posts.url,
+    posts.published_at,
+    posts.description,
+    posts.author,
+    posts.categories
+FROM posts
+INNER JOIN feeds ON posts.feed_id = feeds.id
+INNER JOIN feed_follows ON posts.feed_id = feed_follows.feed_id
+WHERE
+    feed_follows.user_id = sqlc.arg(user_id)
+    AND posts.created_at > sqlc.arg(added_after)
+    AND posts.created_at <= sqlc.arg(added_before)
+    AND posts.seq > sqlc.arg(after_seq)
+ORDER BY posts.seq
+LIMIT sqlc.arg(max_posts) OFFSET sqlc.arg(page_offset);
+
+-- name: SetUserLastDigest :exec
+UPDATE users
+SET last_digest_at = sqlc.arg(last_digest_at), last_digest_seq = sqlc.arg(last_digest_seq)
+WHERE id = sqlc.arg(id);
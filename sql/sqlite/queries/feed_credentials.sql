@@ -0,0 +1,23 @@
+-- name: SetFeedCredential :exec
+INSERT INTO feed_credentials (
+    id, created_at, updated_at, feed_id, kind, header_name, username, secret_ref
+)
+VALUES (?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP, ?, ?, ?, ?, ?)
+ON CONFLICT (feed_id, kind, header_name) DO UPDATE
+SET
+    username = excluded.username,
+    secret_ref = excluded.secret_ref,
+    updated_at = CURRENT_TIMESTAMP;
+
+-- name: GetFeedCredentials :many
+SELECT * FROM feed_credentials
+WHERE feed_id = ?
+ORDER BY kind, header_name;
+
+-- name: DeleteFeedAuthorization :exec
+DELETE FROM feed_credentials
+WHERE feed_id = ? AND kind IN ('basic', 'bearer');
+
+-- name: ClearFeedCredentials :exec
+DELETE FROM feed_credentials
+WHERE feed_id = ?;
@@ -0,0 +1,18 @@
+-- name: CreateFeedFetch :exec
+INSERT INTO feed_fetches (
+    id, fetched_at, feed_id, content_encoding, compressed_bytes, uncompressed_bytes
+)
+VALUES (?, CURRENT_TIMESTAMP, ?, ?, ?, ?);
+
+-- name: GetFeedBandwidth :many
+SELECT
+    feeds.name,
+    feeds.url,
+    count(*) AS fetches,
+    CAST(sum(feed_fetches.compressed_bytes) AS INTEGER) AS compressed_bytes,
+    CAST(sum(feed_fetches.uncompressed_bytes) AS INTEGER) AS uncompressed_bytes
+FROM feed_fetches
+INNER JOIN feeds ON feed_fetches.feed_id = feeds.id
+WHERE feed_fetches.fetched_at >= ?
+GROUP BY feeds.id
+ORDER BY compressed_bytes DESC;
@@ -0,0 +1,8 @@
+-- name: CreateFeedHistory :exec
+INSERT INTO feed_history (id, created_at, feed_id, event, detail)
+VALUES (?, CURRENT_TIMESTAMP, ?, ?, ?);
+
+-- name: GetFeedHistory :many
+SELECT * FROM feed_history
+WHERE feed_id = ?
+ORDER BY created_at DESC;
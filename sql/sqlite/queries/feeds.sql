@@ -0,0 +1,68 @@
+-- name: CreateFeed :one
+INSERT INTO feeds (id, name, url, user_id, created_at, updated_at, seq)
+VALUES (
+    ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP,
+    (SELECT COALESCE(MAX(seq), 0) + 1 FROM feeds)
+)
+RETURNING *;
+
+-- name: GetAllFeeds :many
+SELECT * FROM feeds;
+
+-- name: GetFeedByURL :one
+SELECT * FROM feeds
+WHERE url = ?;
+
+-- name: MarkFeedFetched :exec
+UPDATE feeds
+SET updated_at = CURRENT_TIMESTAMP, last_fetched_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: GetNextFeedToFetch :one
+SELECT *
+FROM feeds
+WHERE dead_at IS NULL
+ORDER BY last_fetched_at ASC NULLS FIRST
+LIMIT 1;
+
+-- name: RenameFeed :one
+UPDATE feeds
+SET name = sqlc.arg(name), updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: UpdateFeedURL :one
+UPDATE feeds
+SET
+    url = sqlc.arg(url),
+    redirect_url = NULL,
+    redirect_count = 0,
+    dead_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DeleteFeed :exec
+DELETE FROM feeds
+WHERE id = ?;
+
+-- name: RecordFeedRedirect :one
+UPDATE feeds
+SET
+    redirect_count = CASE
+        WHEN redirect_url = sqlc.arg(redirect_url) THEN redirect_count + 1
+        ELSE 1
+    END,
+    redirect_url = sqlc.arg(redirect_url)
+WHERE id = sqlc.arg(id)
+RETURNING redirect_count;
+
+-- name: ClearFeedRedirect :exec
+UPDATE feeds
+SET redirect_url = NULL, redirect_count = 0
+WHERE id = ?;
+
+-- name: MarkFeedDead :exec
+UPDATE feeds
+SET updated_at = CURRENT_TIMESTAMP, dead_at = CURRENT_TIMESTAMP
+WHERE id = ?;
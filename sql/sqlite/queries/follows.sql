@@ -0,0 +1,42 @@
+-- name: CreateFeedFollow :one
+INSERT INTO feed_follows (id, created_at, updated_at, user_id, feed_id)
+VALUES (?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP, ?, ?)
+RETURNING *;
+
+-- name: GetFeedFollowsForUser :many
+SELECT
+    feed_follows.*,
+    COALESCE(feed_follows.title, feeds.name) AS feed_name,
+    users.name AS user_name,
+    categories.name AS category_name
+FROM feed_follows
+INNER JOIN feeds ON feed_follows.feed_id = feeds.id
+INNER JOIN users ON feed_follows.user_id = users.id
+LEFT JOIN categories ON feed_follows.category_id = categories.id
+WHERE feed_follows.user_id = ?;
+
+-- name: Unfollow :one
+DELETE FROM feed_follows
+WHERE user_id = ? AND feed_id = ?
+RETURNING *;
+
+-- name: CountOtherFeedFollowers :one
+SELECT count(*)
+FROM feed_follows
+WHERE feed_id = ? AND user_id <> ?;
+
+-- name: GetFeedFollowByID :one
+SELECT
+    feed_follows.*,
+    feeds.name AS feed_name,
+    users.name AS user_name
+FROM feed_follows
+INNER JOIN feeds ON feed_follows.feed_id = feeds.id
+INNER JOIN users ON feed_follows.user_id = users.id
+WHERE feed_follows.id = ?;
+
+-- name: SetFollowTitle :execrows
+-- title NULL shows the feed under its own name again.
+UPDATE feed_follows
+SET title = sqlc.narg(title), updated_at = CURRENT_TIMESTAMP
+WHERE user_id = sqlc.arg(user_id) AND feed_id = sqlc.arg(feed_id);
@@ -0,0 +1,167 @@
+-- name: CreatePost :execrows
+INSERT INTO posts (
+    id, created_at, updated_at, title, url, description, published_at, feed_id,
+    author, categories, seq
+)
+VALUES (
+    sqlc.arg(id), CURRENT_TIMESTAMP, CURRENT_TIMESTAMP,
+    nullif(CAST(sqlc.arg(title) AS TEXT), ''), sqlc.arg(url),
+    nullif(CAST(sqlc.arg(description) AS TEXT), ''),
+    sqlc.arg(published_at), sqlc.arg(feed_id),
+    nullif(CAST(sqlc.arg(author) AS TEXT), ''),
+    nullif(CAST(sqlc.arg(categories) AS TEXT), ''),
+    (SELECT COALESCE(MAX(seq), 0) + 1 FROM posts)
+)
+ON CONFLICT (url) DO NOTHING;
+
+-- name: GetPostsByUser :many
+SELECT posts.title, posts.url, posts.description, posts.published_at, posts.feed_id, posts.author, posts.categories
+FROM posts
+INNER JOIN feeds
+    ON feeds.id = posts.feed_id
+INNER JOIN users
+    ON feeds.user_id = users.id
+INNER JOIN feed_follows
+    ON feed_follows.feed_id = posts.feed_id
+WHERE feed_follows.user_id = ?
+ORDER BY published_at DESC NULLS LAST
+LIMIT ? OFFSET ?;
+
+-- name: GetPostByURL :one
+SELECT * FROM posts
+WHERE url = ?;
+
+-- name: SavePost :exec
+INSERT INTO saved_posts (user_id, post_id, created_at)
+VALUES (?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT (user_id, post_id) DO NOTHING;
+
+-- name: UnsavePost :execrows
+DELETE FROM saved_posts
+WHERE user_id = ? AND post_id = ?;
+
+-- name: PrunePostsOlderThan :many
+DELETE FROM posts
+WHERE
+    coalesce(posts.published_at, posts.created_at) < sqlc.arg(cutoff)
+    AND NOT EXISTS (
+        SELECT 1 FROM saved_posts
+        WHERE saved_posts.post_id = posts.id
+    )
+RETURNING feed_id, url;
+
+-- name: PrunePostsBeyondNewest :many
+DELETE FROM posts
+WHERE
+    (
+        SELECT count(*) FROM posts AS newer
+        WHERE
+            newer.feed_id = posts.feed_id
+            AND (
+                coalesce(newer.published_at, '') > coalesce(posts.published_at, '')
+                OR (
+                    coalesce(newer.published_at, '') = coalesce(posts.published_at, '')
+                    AND newer.created_at > posts.created_at
+                )
+            )
+    ) >= CAST(sqlc.arg(keep) AS INTEGER)
+    AND NOT EXISTS (
+        SELECT 1 FROM saved_posts
+        WHERE saved_posts.post_id = posts.id
+    )
+RETURNING feed_id, url;
+
+-- name: CreatePrunedPost :exec
+INSERT INTO pruned_posts (url, feed_id, pruned_at)
+VALUES (?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT (url) DO NOTHING;
+
+-- name: IsPostPruned :one
+SELECT CAST(EXISTS (
+    SELECT 1 FROM pruned_posts
+    WHERE url = ?
+) AS BOOLEAN) AS pruned;
+
+-- name: MarkPrunedPostSeen :exec
+UPDATE pruned_posts SET seen_at = CURRENT_TIMESTAMP
+WHERE feed_id = ? AND url = ?;
+
+-- name: ExpirePrunedPosts :execrows
+DELETE FROM pruned_posts
+WHERE coalesce(seen_at, pruned_at) < (
+    SELECT max(feed_fetches.fetched_at) FROM feed_fetches
+    WHERE feed_fetches.feed_id = pruned_posts.feed_id
+);
+
+-- name: GetPostByID :one
+SELECT * FROM posts
+WHERE id = ?;
+
+-- name: GetPostForUser :one
+SELECT
+    posts.id,
+    posts.title,
+    posts.url,
+    posts.description,
+    posts.published_at,
+    posts.author,
+    posts.categories,
+    posts.feed_id,
+    COALESCE(feed_follows.title, feeds.name) AS feed_name,
+    CAST(EXISTS (
+        SELECT 1 FROM read_posts
+        WHERE read_posts.post_id = posts.id AND read_posts.user_id = feed_follows.user_id
+    ) AS BOOLEAN) AS is_read,
+    CAST(EXISTS (
+        SELECT 1 FROM saved_posts
+        WHERE saved_posts.post_id = posts.id AND saved_posts.user_id = feed_follows.user_id
+    ) AS BOOLEAN) AS is_saved
+FROM posts
+INNER JOIN feeds ON posts.feed_id = feeds.id
+INNER JOIN feed_follows ON posts.feed_id = feed_follows.feed_id
+WHERE posts.id = sqlc.arg(id) AND feed_follows.user_id = sqlc.arg(user_id);
+
+-- name: GetPostsForUser :many
+SELECT
+    posts.id,
+    posts.title,
+    posts.url,
+    posts.description,
+    posts.published_at,
+    posts.author,
+    posts.categories,
+    posts.feed_id,
+    COALESCE(feed_follows.title, feeds.name) AS feed_name,
+    CAST(EXISTS (
+        SELECT 1 FROM read_posts
+        WHERE read_posts.post_id = posts.id AND read_posts.user_id = feed_follows.user_id
+    ) AS BOOLEAN) AS is_read,
+    CAST(EXISTS (
+        SELECT 1 FROM saved_posts
+        WHERE saved_posts.post_id = posts.id AND saved_posts.user_id = feed_follows.user_id
+    ) AS BOOLEAN) AS is_saved
+FROM posts
+INNER JOIN feeds ON posts.feed_id = feeds.id
+INNER JOIN feed_follows ON posts.feed_id = feed_follows.feed_id
+WHERE
+    feed_follows.user_id = sqlc.arg(user_id)
+    AND (
+        CAST(sqlc.arg(unread_only) AS BOOLEAN) = FALSE
+        OR NOT EXISTS (
+            SELECT 1 FROM read_posts
+            WHERE read_posts.post_id = posts.id AND read_posts.user_id = feed_follows.user_id
+        )
+    )
+    AND (sqlc.narg(feed_id) IS NULL OR posts.feed_id = sqlc.narg(feed_id))
+    AND (sqlc.narg(category_id) IS NULL OR feed_follows.category_id = sqlc.narg(category_id))
+ORDER BY posts.published_at DESC NULLS LAST, posts.id
+LIMIT CAST(sqlc.arg(page_size) AS INTEGER) OFFSET CAST(sqlc.arg(page_offset) AS INTEGER);
+
+-- name: MarkPostRead :exec
+INSERT INTO read_posts (user_id, post_id, read_at)
+VALUES (?, ?, CURRENT_TIMESTAMP)
+ON CONFLICT (user_id, post_id) DO NOTHING;
+
+-- name: MarkPostUnread :exec
+DELETE FROM read_posts
+WHERE user_id = ? AND post_id = ?;
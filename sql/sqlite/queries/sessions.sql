@@ -0,0 +1,17 @@
+-- name: CreateSession :exec
+INSERT INTO sessions (token_hash, user_id, created_at)
+VALUES (?, ?, CURRENT_TIMESTAMP);
+
+-- name: GetUserBySession :one
+SELECT users.*
+FROM sessions
+INNER JOIN users ON sessions.user_id = users.id
+WHERE sessions.token_hash = ?;
+
+-- name: DeleteSession :exec
+DELETE FROM sessions
+WHERE token_hash = ?;
+
+-- name: DeleteOtherSessions :exec
+DELETE FROM sessions
+WHERE user_id = sqlc.arg(user_id) AND token_hash <> sqlc.arg(token_hash);
@@ -0,0 +1,50 @@
+-- name: CreateUser :one
+INSERT INTO users (id, created_at, updated_at, name, password_hash, is_admin)
+VALUES (?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP, ?, ?, NOT EXISTS (SELECT 1 FROM users))
+RETURNING *;
+
+-- name: CreateNonAdminUser :one
+INSERT INTO users (id, created_at, updated_at, name, password_hash, is_admin)
+VALUES (?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP, ?, ?, false)
+RETURNING *;
+
+-- name: GetUserByName :one
+SELECT * FROM users
+WHERE name = ?;
+
+-- name: GetUserByID :one
+SELECT * FROM users
+WHERE id = ?;
+
+-- name: ResetUsers :exec
+DELETE FROM users;
+
+-- name: GetUsers :many
+SELECT * FROM users;
+
+-- name: SetUserPassword :exec
+UPDATE users
+SET password_hash = sqlc.arg(password_hash), updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id);
+
+-- name: SetUserAdmin :exec
+UPDATE users
+SET is_admin = sqlc.arg(is_admin), updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id);
+
+-- name: CountAdmins :one
+SELECT count(*) FROM users
+WHERE is_admin;
+
+-- name: DeleteUser :exec
+DELETE FROM users
+WHERE id = ?;
+
+-- name: SetUserFeverKey :exec
+UPDATE users
+SET fever_key_hash = sqlc.arg(fever_key_hash), updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id);
+
+-- name: GetUserByFeverKey :one
+SELECT * FROM users
+WHERE fever_key_hash = ?;
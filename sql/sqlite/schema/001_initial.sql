@@ -0,0 +1,88 @@
+-- +goose Up
+CREATE TABLE users (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    name TEXT UNIQUE NOT NULL
+);
+
+CREATE TABLE feeds (
+    id UUID PRIMARY KEY,
+    name TEXT NOT NULL,
+    url TEXT NOT NULL UNIQUE,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    last_fetched_at TIMESTAMP DEFAULT NULL,
+    redirect_url TEXT DEFAULT NULL,
+    redirect_count INTEGER NOT NULL DEFAULT 0,
+    dead_at TIMESTAMP DEFAULT NULL
+);
+
+CREATE TABLE feed_follows (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    feed_id UUID NOT NULL REFERENCES feeds (id) ON DELETE CASCADE,
+    CONSTRAINT unique_user_feed_follow UNIQUE (user_id, feed_id)
+);
+
+CREATE TABLE posts (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    title TEXT,
+    url TEXT NOT NULL UNIQUE,
+    description TEXT,
+    published_at TIMESTAMP,
+    feed_id UUID NOT NULL REFERENCES feeds (id) ON DELETE CASCADE
+);
+
+CREATE TABLE feed_history (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    feed_id UUID NOT NULL REFERENCES feeds (id) ON DELETE CASCADE,
+    event TEXT NOT NULL,
+    detail TEXT NOT NULL
+);
+
+CREATE TABLE feed_credentials (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    feed_id UUID NOT NULL REFERENCES feeds (id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    header_name TEXT NOT NULL DEFAULT '',
+    username TEXT,
+    secret_ref TEXT NOT NULL,
+    CONSTRAINT unique_feed_credential UNIQUE (feed_id, kind, header_name)
+);
+
+CREATE TABLE feed_fetches (
+    id UUID PRIMARY KEY,
+    fetched_at TIMESTAMP NOT NULL,
+    feed_id UUID NOT NULL REFERENCES feeds (id) ON DELETE CASCADE,
+    content_encoding TEXT NOT NULL,
+    compressed_bytes BIGINT NOT NULL,
+    uncompressed_bytes BIGINT NOT NULL
+);
+
+CREATE INDEX feed_fetches_fetched_at_idx ON feed_fetches (fetched_at);
+
+CREATE TABLE saved_posts (
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    post_id UUID NOT NULL REFERENCES posts (id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, post_id)
+);
+
+-- +goose Down
+DROP TABLE saved_posts;
+DROP TABLE feed_fetches;
+DROP TABLE feed_credentials;
+DROP TABLE feed_history;
+DROP TABLE posts;
+DROP TABLE feed_follows;
+DROP TABLE feeds;
+DROP TABLE users;
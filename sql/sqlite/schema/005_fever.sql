@@ -0,0 +1,23 @@
+-- +goose Up
+-- Fever clients identify feeds and items by integers that grow with every
+-- insert. SQLite cannot add an autoincrement column, so the inserts assign
+-- MAX(seq) + 1 and existing rows start from their rowid.
+ALTER TABLE feeds ADD COLUMN seq INTEGER NOT NULL DEFAULT 0;
+UPDATE feeds SET seq = rowid;
+CREATE UNIQUE INDEX feeds_seq ON feeds (seq);
+
+ALTER TABLE posts ADD COLUMN seq INTEGER NOT NULL DEFAULT 0;
+UPDATE posts SET seq = rowid;
+CREATE UNIQUE INDEX posts_seq ON posts (seq);
+
+-- sha256 of the Fever api_key, md5("name:password"); NULL while disabled.
+ALTER TABLE users ADD COLUMN fever_key_hash TEXT;
+CREATE UNIQUE INDEX users_fever_key_hash ON users (fever_key_hash);
+
+-- +goose Down
+DROP INDEX users_fever_key_hash;
+ALTER TABLE users DROP COLUMN fever_key_hash;
+DROP INDEX posts_seq;
+ALTER TABLE posts DROP COLUMN seq;
+DROP INDEX feeds_seq;
+ALTER TABLE feeds DROP COLUMN seq;
@@ -0,0 +1,22 @@
+-- +goose Up
+-- The author and categories of a post, which filter rules can match.
+-- categories holds the <category> elements of the item, one per line.
+ALTER TABLE posts ADD COLUMN author TEXT;
+ALTER TABLE posts ADD COLUMN categories TEXT;
+
+-- Include and exclude rules deciding which posts a user sees. feed_id NULL
+-- applies the rule to every feed.
+CREATE TABLE filter_rules (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    feed_id UUID REFERENCES feeds (id) ON DELETE CASCADE,
+    action TEXT NOT NULL CHECK (action IN ('include', 'exclude')),
+    kind TEXT NOT NULL CHECK (kind IN ('keyword', 'regex', 'author', 'category')),
+    pattern TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+-- +goose Down
+DROP TABLE filter_rules;
+ALTER TABLE posts DROP COLUMN categories;
+ALTER TABLE posts DROP COLUMN author;
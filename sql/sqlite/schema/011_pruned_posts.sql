@@ -0,0 +1,11 @@
+-- +goose Up
+-- URLs of pruned posts, so the next fetch does not insert them again while
+-- they are still in the feed.
+CREATE TABLE pruned_posts (
+    url TEXT PRIMARY KEY,
+    feed_id UUID NOT NULL REFERENCES feeds (id) ON DELETE CASCADE,
+    pruned_at TIMESTAMP NOT NULL
+);
+
+-- +goose Down
+DROP TABLE pruned_posts;
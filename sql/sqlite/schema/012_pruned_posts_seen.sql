@@ -0,0 +1,9 @@
+-- +goose Up
+-- When a pruned post's URL was last in its feed. Prune forgets the URLs that
+-- were not in the latest fetch of their feed.
+ALTER TABLE pruned_posts ADD COLUMN seen_at TIMESTAMP;
+CREATE INDEX feed_fetches_feed_id_fetched_at_idx ON feed_fetches (feed_id, fetched_at);
+
+-- +goose Down
+DROP INDEX feed_fetches_feed_id_fetched_at_idx;
+ALTER TABLE pruned_posts DROP COLUMN seen_at;
@@ -0,0 +1,8 @@
+-- +goose Up
+-- The seq of the last post a digest considered. Later digests start after it
+-- rather than at last_digest_at, so the posts a digest had no room for are not
+-- skipped.
+ALTER TABLE users ADD COLUMN last_digest_seq BIGINT;
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN last_digest_seq;